@@ -6,11 +6,12 @@ import {
   FaUser, FaFolder, FaCode, FaEnvelope, FaSave, FaPlus, FaTrash, FaArrowLeft, FaCheck
 } from "react-icons/fa";
 import Link from "next/link";
+import { useRouter } from "next/navigation";
 import { useLanguage } from "@/shared/lib/language-context";
 import { GridBackground } from "@/shared/ui/grid-background";
 import { LiquidGlass } from "@/shared/ui/liquid-glass";
 import {
-  getContent,
+  getSection,
   updateAbout,
   updateProjects,
   updateSkills,
@@ -20,31 +21,42 @@ import {
   type Project,
   type SkillCategory,
   type Contact,
+  ContentConflictError,
+  ContentValidationError,
 } from "@/shared/api/content";
+import { hasSession } from "@/shared/api/auth";
 
 type Tab = "about" | "projects" | "skills" | "contacts";
 
 export default function EditPage() {
   const { language } = useLanguage();
+  const router = useRouter();
   const [activeTab, setActiveTab] = useState<Tab>("about");
   const [content, setContent] = useState<SiteContent | null>(null);
+  const [etags, setEtags] = useState<Record<Tab, string>>({ about: "", projects: "", skills: "", contacts: "" });
   const [loading, setLoading] = useState(true);
   const [saving, setSaving] = useState(false);
   const [saved, setSaved] = useState(false);
   const [error, setError] = useState("");
-  const [password, setPassword] = useState("");
 
   useEffect(() => {
+    if (!hasSession()) {
+      router.push("/login");
+      return;
+    }
     loadContent();
-    // Получаем пароль из cookie или localStorage
-    const storedPassword = localStorage.getItem("admin_password") || "";
-    setPassword(storedPassword);
-  }, []);
+  }, [router]);
 
   const loadContent = async () => {
     try {
-      const data = await getContent();
-      setContent(data);
+      const [about, projects, skills, contacts] = await Promise.all([
+        getSection<AboutContent>("about"),
+        getSection<Project[]>("projects"),
+        getSection<SkillCategory[]>("skills"),
+        getSection<Contact[]>("contacts"),
+      ]);
+      setContent({ about: about.data, projects: projects.data, skills: skills.data, contacts: contacts.data });
+      setEtags({ about: about.etag, projects: projects.etag, skills: skills.etag, contacts: contacts.etag });
     } catch (err) {
       setError("Не удалось загрузить контент. Убедитесь что сервер запущен.");
     } finally {
@@ -53,27 +65,37 @@ export default function EditPage() {
   };
 
   const handleSave = async () => {
-    if (!content || !password) {
-      setError("Введите пароль администратора");
-      return;
-    }
+    if (!content) return;
 
     setSaving(true);
     setError("");
     setSaved(false);
 
     try {
-      localStorage.setItem("admin_password", password);
-      
-      await updateAbout(content.about, password);
-      await updateProjects(content.projects, password);
-      await updateSkills(content.skills, password);
-      await updateContacts(content.contacts, password);
+      // Each save returns the section's new ETag for the next one
+      const next = { ...etags };
+      try {
+        next.about = (await updateAbout(content.about, etags.about)).etag;
+        next.projects = (await updateProjects(content.projects, etags.projects)).etag;
+        next.skills = (await updateSkills(content.skills, etags.skills)).etag;
+        next.contacts = (await updateContacts(content.contacts, etags.contacts)).etag;
+      } finally {
+        setEtags(next);
+      }
       
       setSaved(true);
       setTimeout(() => setSaved(false), 3000);
     } catch (err) {
-      setError("Ошибка сохранения. Проверьте пароль.");
+      if (err instanceof ContentConflictError) {
+        setError("Контент уже изменён в другой вкладке. Обновите страницу, чтобы не затереть чужие правки.");
+        return;
+      }
+      if (err instanceof ContentValidationError) {
+        const fields = err.errors.map((e) => `${err.section}${e.pointer}: ${e.detail}`);
+        setError(`Некорректные поля: ${fields.join("; ")}`);
+        return;
+      }
+      setError("Ошибка сохранения. Возможно, сессия истекла — войдите заново.");
     } finally {
       setSaving(false);
     }
@@ -143,13 +165,6 @@ export default function EditPage() {
             </div>
 
             <div className="flex items-center gap-3">
-              <input
-                type="password"
-                value={password}
-                onChange={(e) => setPassword(e.target.value)}
-                placeholder={language === "ru" ? "Пароль" : "Password"}
-                className="w-32 sm:w-40 px-3 py-2 text-sm rounded-xl bg-black/5 dark:bg-white/5 border border-black/10 dark:border-white/10 text-primary dark:text-dark-primary placeholder:text-secondary/50"
-              />
               <motion.button
                 onClick={handleSave}
                 disabled={saving}
@@ -11,6 +11,7 @@ import { useRouter } from "next/navigation";
 import { useLanguage } from "@/shared/lib/language-context";
 import { GridBackground } from "@/shared/ui/grid-background";
 import { getAnalytics, type AnalyticsData } from "@/shared/api/analytics";
+import { getMe, hasSession, logout, type Me } from "@/shared/api/auth";
 import { LiquidGlass } from "@/shared/ui/liquid-glass";
 
 const pageNames: Record<string, string> = {
@@ -26,14 +27,19 @@ export default function AdminPage() {
   const { language } = useLanguage();
   const router = useRouter();
   const [data, setData] = useState<AnalyticsData | null>(null);
+  const [me, setMe] = useState<Me | null>(null);
   const [loading, setLoading] = useState(true);
   const [activeTab, setActiveTab] = useState<"overview" | "pages" | "devices">("overview");
 
   useEffect(() => {
-    const password = localStorage.getItem("admin_password") || "";
-    
+    if (!hasSession()) {
+      router.push("/login");
+      return;
+    }
+    getMe().then(setMe);
+
     const fetchData = async () => {
-      const result = await getAnalytics(password);
+      const result = await getAnalytics();
       setData(result);
       setLoading(false);
     };
@@ -44,14 +50,15 @@ export default function AdminPage() {
     const interval = setInterval(fetchData, 10000);
 
     return () => clearInterval(interval);
-  }, []);
+  }, [router]);
 
-  const handleLogout = () => {
-    document.cookie = "admin_auth=; expires=Thu, 01 Jan 1970 00:00:00 UTC; path=/;";
-    localStorage.removeItem("admin_password");
+  const handleLogout = async () => {
+    await logout().catch(() => {});
     router.push("/");
   };
 
+  const canEdit = !!me?.permissions.includes("content:write");
+
   if (loading) {
     return (
       <>
@@ -73,11 +80,13 @@ export default function AdminPage() {
               {language === "ru" ? "Не удалось загрузить аналитику" : "Failed to load analytics"}
             </p>
             <p className="text-xs text-secondary/50 dark:text-dark-secondary/50 mb-4">
-              Убедитесь что сервер запущен и пароль верный
+              Убедитесь что сервер запущен и у аккаунта есть доступ к аналитике
             </p>
-            <Link href="/admin/edit" className="px-4 py-2 bg-blue-500 text-white rounded-lg">
-              Перейти к редактированию
-            </Link>
+            {canEdit && (
+              <Link href="/admin/edit" className="px-4 py-2 bg-blue-500 text-white rounded-lg">
+                Перейти к редактированию
+              </Link>
+            )}
           </div>
         </div>
       </>
@@ -147,13 +156,15 @@ export default function AdminPage() {
             </div>
             
             <div className="flex items-center gap-3">
-              <Link
-                href="/admin/edit"
-                className="flex items-center gap-2 px-4 py-2 rounded-xl bg-blue-500 text-white hover:bg-blue-600 transition-colors"
-              >
-                <FaEdit className="w-4 h-4" />
-                <span className="hidden sm:inline">{language === "ru" ? "Редактировать" : "Edit"}</span>
-              </Link>
+              {canEdit && (
+                <Link
+                  href="/admin/edit"
+                  className="flex items-center gap-2 px-4 py-2 rounded-xl bg-blue-500 text-white hover:bg-blue-600 transition-colors"
+                >
+                  <FaEdit className="w-4 h-4" />
+                  <span className="hidden sm:inline">{language === "ru" ? "Редактировать" : "Edit"}</span>
+                </Link>
+              )}
               
               <motion.button
                 onClick={handleLogout}
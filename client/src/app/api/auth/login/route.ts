@@ -1,23 +0,0 @@
-import { NextResponse } from "next/server";
-
-export async function POST(request: Request) {
-  const { password } = await request.json();
-  const adminPassword = process.env.ADMIN_PASSWORD;
-
-  if (!adminPassword) {
-    return NextResponse.json({ error: "Server not configured" }, { status: 500 });
-  }
-
-  if (password === adminPassword) {
-    const response = NextResponse.json({ success: true });
-    response.cookies.set("admin_auth", "true", {
-      httpOnly: true,
-      secure: process.env.NODE_ENV === "production",
-      sameSite: "strict",
-      maxAge: 60 * 60 * 24, // 24 hours
-    });
-    return response;
-  }
-
-  return NextResponse.json({ error: "Invalid password" }, { status: 401 });
-}
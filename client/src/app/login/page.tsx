@@ -6,11 +6,15 @@ import { motion } from "framer-motion";
 import { FaLock, FaEye, FaEyeSlash } from "react-icons/fa";
 import { useLanguage } from "@/shared/lib/language-context";
 import { GridBackground } from "@/shared/ui/grid-background";
+import { login } from "@/shared/api/auth";
 
 export default function LoginPage() {
   const { language } = useLanguage();
   const router = useRouter();
+  const [username, setUsername] = useState("");
   const [password, setPassword] = useState("");
+  const [otp, setOtp] = useState("");
+  const [otpRequired, setOtpRequired] = useState(false);
   const [showPassword, setShowPassword] = useState(false);
   const [error, setError] = useState("");
   const [loading, setLoading] = useState(false);
@@ -21,17 +25,17 @@ export default function LoginPage() {
     setLoading(true);
 
     try {
-      const res = await fetch("/api/auth/login", {
-        method: "POST",
-        headers: { "Content-Type": "application/json" },
-        body: JSON.stringify({ password }),
-      });
-
-      if (res.ok) {
-        localStorage.setItem("admin_password", password);
+      const result = await login(username, password, otpRequired ? otp : undefined);
+      if (result === "ok") {
         router.push("/admin");
+      } else if (result === "otp_required") {
+        setOtpRequired(true);
+      } else if (result === "locked") {
+        setError(language === "ru" ? "Слишком много попыток, попробуйте позже" : "Too many attempts, try again later");
+      } else if (otpRequired) {
+        setError(language === "ru" ? "Неверный код" : "Wrong code");
       } else {
-        setError(language === "ru" ? "Неверный пароль" : "Wrong password");
+        setError(language === "ru" ? "Неверный логин или пароль" : "Wrong username or password");
       }
     } catch {
       setError(language === "ru" ? "Ошибка сервера" : "Server error");
@@ -63,9 +67,21 @@ export default function LoginPage() {
             </h1>
 
             <form onSubmit={handleSubmit} className="space-y-4">
+              <input
+                type="text"
+                autoComplete="username"
+                value={username}
+                onChange={(e) => setUsername(e.target.value)}
+                placeholder={language === "ru" ? "Логин" : "Username"}
+                className="w-full px-4 py-3 rounded-xl bg-black/5 dark:bg-white/5 
+                  border border-black/10 dark:border-white/10
+                  text-primary dark:text-dark-primary placeholder:text-secondary/50
+                  focus:outline-none focus:ring-2 focus:ring-blue-500/50"
+              />
               <div className="relative">
                 <input
                   type={showPassword ? "text" : "password"}
+                  autoComplete="current-password"
                   value={password}
                   onChange={(e) => setPassword(e.target.value)}
                   placeholder={language === "ru" ? "Пароль" : "Password"}
@@ -83,13 +99,29 @@ export default function LoginPage() {
                 </button>
               </div>
 
+              {otpRequired && (
+                <input
+                  type="text"
+                  inputMode="numeric"
+                  autoComplete="one-time-code"
+                  autoFocus
+                  value={otp}
+                  onChange={(e) => setOtp(e.target.value)}
+                  placeholder={language === "ru" ? "Код из приложения или резервный код" : "App code or recovery code"}
+                  className="w-full px-4 py-3 rounded-xl bg-black/5 dark:bg-white/5 
+                    border border-black/10 dark:border-white/10
+                    text-primary dark:text-dark-primary placeholder:text-secondary/50
+                    focus:outline-none focus:ring-2 focus:ring-blue-500/50"
+                />
+              )}
+
               {error && (
                 <p className="text-sm text-red-500 text-center">{error}</p>
               )}
 
               <button
                 type="submit"
-                disabled={loading || !password}
+                disabled={loading || !username || !password || (otpRequired && !otp)}
                 className="w-full py-3 rounded-xl bg-black dark:bg-white text-white dark:text-black 
                   font-medium hover:opacity-90 transition-opacity disabled:opacity-50"
               >
@@ -1,3 +1,6 @@
+import { authFetch } from "./auth";
+import { apiFetch } from "./trace";
+
 const API_URL = process.env.NEXT_PUBLIC_API_URL || "http://localhost:8080/api";
 
 export interface AnalyticsData {
@@ -19,7 +22,7 @@ export interface AnalyticsData {
 // Track page view
 export async function trackPageView(page: string, visitorId: string, device: string) {
   try {
-    await fetch(`${API_URL}/analytics/track`, {
+    await apiFetch(`${API_URL}/analytics/track`, {
       method: "POST",
       headers: { "Content-Type": "application/json" },
       body: JSON.stringify({
@@ -40,10 +43,11 @@ export async function trackSession(
   duration: number,
   pages: number,
   theme: string,
-  language: string
+  language: string,
+  device: string
 ) {
   try {
-    await fetch(`${API_URL}/analytics/track`, {
+    await apiFetch(`${API_URL}/analytics/track`, {
       method: "POST",
       headers: { "Content-Type": "application/json" },
       body: JSON.stringify({
@@ -53,6 +57,7 @@ export async function trackSession(
         pages,
         theme,
         language,
+        device,
       }),
     });
   } catch {
@@ -61,11 +66,9 @@ export async function trackSession(
 }
 
 // Get analytics (admin only)
-export async function getAnalytics(password: string): Promise<AnalyticsData | null> {
+export async function getAnalytics(): Promise<AnalyticsData | null> {
   try {
-    const res = await fetch(`${API_URL}/analytics`, {
-      headers: { "X-Admin-Password": password },
-    });
+    const res = await authFetch(`${API_URL}/analytics`);
     if (!res.ok) return null;
     return res.json();
   } catch {
@@ -0,0 +1,115 @@
+import { apiFetch } from "./trace";
+
+const API_URL = process.env.NEXT_PUBLIC_API_URL || "http://localhost:8080/api";
+
+const SESSION_KEY = "admin_session";
+
+// Session is what the API issues on login: a short-lived access token and
+// a refresh token to get the next one
+interface Session {
+  access_token: string;
+  expires_at: string;
+  refresh_token: string;
+  refresh_expires_at: string;
+}
+
+function loadSession(): Session | null {
+  if (typeof window === "undefined") return null;
+  const raw = localStorage.getItem(SESSION_KEY);
+  return raw ? (JSON.parse(raw) as Session) : null;
+}
+
+function storeSession(session: Session | null) {
+  if (session) localStorage.setItem(SESSION_KEY, JSON.stringify(session));
+  else localStorage.removeItem(SESSION_KEY);
+}
+
+export function hasSession(): boolean {
+  const session = loadSession();
+  return !!session && new Date(session.refresh_expires_at) > new Date();
+}
+
+export type Permission =
+  | "content:write"
+  | "content:publish"
+  | "analytics:read"
+  | "users:manage"
+  | "keys:manage"
+  | "audit:read";
+
+// Me is the logged-in account, its permissions decide which controls to show
+export interface Me {
+  id: number;
+  username: string;
+  role: "owner" | "editor" | "analyst";
+  permissions: Permission[];
+}
+
+export async function getMe(): Promise<Me | null> {
+  const res = await authFetch(`${API_URL}/auth/me`);
+  if (!res.ok) return null;
+  return res.json();
+}
+
+// "locked" means too many failed attempts, the server says when to retry
+export type LoginResult = "ok" | "invalid" | "otp_required" | "locked";
+
+// Log in with username and password, plus a one-time or recovery code for
+// accounts with two-factor authentication
+export async function login(username: string, password: string, otp?: string): Promise<LoginResult> {
+  const res = await apiFetch(`${API_URL}/auth/login`, {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify({ username, password, otp }),
+  });
+  if (res.status === 401) {
+    const problem = await res.json();
+    return problem.type === "urn:portfolio:problem:otp-required" ? "otp_required" : "invalid";
+  }
+  if (res.status === 429) return "locked";
+  if (!res.ok) throw new Error("Login failed");
+  storeSession(await res.json());
+  return "ok";
+}
+
+// Revoke the session on the server and forget it
+export async function logout(): Promise<void> {
+  try {
+    await authFetch(`${API_URL}/auth/logout`, { method: "POST" });
+  } finally {
+    storeSession(null);
+  }
+}
+
+// Concurrent requests share one refresh, a refresh token works only once
+let refreshing: Promise<Session | null> | null = null;
+
+async function refresh(session: Session): Promise<Session | null> {
+  if (!refreshing) {
+    refreshing = (async () => {
+      const res = await apiFetch(`${API_URL}/auth/refresh`, {
+        method: "POST",
+        headers: { "Content-Type": "application/json" },
+        body: JSON.stringify({ refresh_token: session.refresh_token }),
+      });
+      const next = res.ok ? ((await res.json()) as Session) : null;
+      storeSession(next);
+      return next;
+    })().finally(() => {
+      refreshing = null;
+    });
+  }
+  return refreshing;
+}
+
+// fetch with the admin access token, refreshed when it is about to expire
+export async function authFetch(url: string, init: RequestInit = {}): Promise<Response> {
+  let session = loadSession();
+  if (session && new Date(session.expires_at).getTime() - Date.now() < 30_000) {
+    session = await refresh(session);
+  }
+
+  const headers = new Headers(init.headers);
+  if (session) headers.set("Authorization", `Bearer ${session.access_token}`);
+  return apiFetch(url, { ...init, headers });
+}
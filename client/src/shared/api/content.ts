@@ -1,3 +1,6 @@
+import { authFetch } from "./auth";
+import { apiFetch } from "./trace";
+
 const API_URL = process.env.NEXT_PUBLIC_API_URL || "http://localhost:8080/api";
 
 export interface AboutContent {
@@ -62,93 +65,128 @@ export interface SiteContent {
   contacts: Contact[];
 }
 
+// Versioned is a section together with the ETag to send as If-Match on
+// its next update
+export interface Versioned<T> {
+  data: T;
+  etag: string;
+}
+
+// ContentConflictError means someone else changed the section since it was
+// loaded (HTTP 412)
+export class ContentConflictError extends Error {
+  constructor(section: string) {
+    super(`Section ${section} was changed by someone else`);
+    this.name = "ContentConflictError";
+  }
+}
+
+// FieldError is one invalid field of a rejected update, pointer is a JSON
+// pointer into the sent section, e.g. "/2/glowColor"
+export interface FieldError {
+  pointer: string;
+  detail: string;
+}
+
+// ContentValidationError means the server rejected some fields (HTTP 422)
+export class ContentValidationError extends Error {
+  constructor(public section: string, public errors: FieldError[]) {
+    super(`Section ${section} has invalid fields`);
+    this.name = "ContentValidationError";
+  }
+}
+
+// Get one section as it is being edited (its draft, or the published state
+// if there is none) with the ETag to send on its next update. Public
+// responses carry the same ETag only while the section has no draft.
+export async function getSection<T>(section: string): Promise<Versioned<T>> {
+  const res = await authFetch(`${API_URL}/content/drafts`, { cache: "no-store" });
+  if (!res.ok) throw new Error(`Failed to fetch ${section}`);
+  const drafts = await res.json();
+  return { data: drafts.content[section], etag: drafts.etags[section] || "" };
+}
+
+async function putSection<T>(section: string, data: T, etag: string): Promise<Versioned<T>> {
+  const res = await authFetch(`${API_URL}/content/${section}`, {
+    method: "PUT",
+    headers: {
+      "Content-Type": "application/json",
+      "If-Match": etag,
+    },
+    body: JSON.stringify(data),
+  });
+  if (res.status === 412) throw new ContentConflictError(section);
+  if (res.status === 422) {
+    const problem = await res.json();
+    throw new ContentValidationError(section, problem.errors || []);
+  }
+  if (!res.ok) throw new Error(`Failed to update ${section}`);
+  return { data: await res.json(), etag: res.headers.get("ETag") || "" };
+}
+
 // Get all content
 export async function getContent(): Promise<SiteContent> {
-  const res = await fetch(`${API_URL}/content`);
+  const res = await apiFetch(`${API_URL}/content?lang=all`);
   if (!res.ok) throw new Error("Failed to fetch content");
   return res.json();
 }
 
 // Get about
 export async function getAbout(): Promise<AboutContent> {
-  const res = await fetch(`${API_URL}/content/about`);
+  const res = await apiFetch(`${API_URL}/content/about?lang=all`);
   if (!res.ok) throw new Error("Failed to fetch about");
   return res.json();
 }
 
 // Update about
-export async function updateAbout(about: AboutContent, password: string): Promise<AboutContent> {
-  const res = await fetch(`${API_URL}/content/about`, {
-    method: "PUT",
-    headers: {
-      "Content-Type": "application/json",
-      "X-Admin-Password": password,
-    },
-    body: JSON.stringify(about),
-  });
-  if (!res.ok) throw new Error("Failed to update about");
-  return res.json();
+export async function updateAbout(
+  about: AboutContent,
+  etag: string
+): Promise<Versioned<AboutContent>> {
+  return putSection("about", about, etag);
 }
 
 // Get projects
 export async function getProjects(): Promise<Project[]> {
-  const res = await fetch(`${API_URL}/content/projects`);
+  const res = await apiFetch(`${API_URL}/content/projects?lang=all`);
   if (!res.ok) throw new Error("Failed to fetch projects");
   return res.json();
 }
 
 // Update projects
-export async function updateProjects(projects: Project[], password: string): Promise<Project[]> {
-  const res = await fetch(`${API_URL}/content/projects`, {
-    method: "PUT",
-    headers: {
-      "Content-Type": "application/json",
-      "X-Admin-Password": password,
-    },
-    body: JSON.stringify(projects),
-  });
-  if (!res.ok) throw new Error("Failed to update projects");
-  return res.json();
+export async function updateProjects(
+  projects: Project[],
+  etag: string
+): Promise<Versioned<Project[]>> {
+  return putSection("projects", projects, etag);
 }
 
 // Get skills
 export async function getSkills(): Promise<SkillCategory[]> {
-  const res = await fetch(`${API_URL}/content/skills`);
+  const res = await apiFetch(`${API_URL}/content/skills?lang=all`);
   if (!res.ok) throw new Error("Failed to fetch skills");
   return res.json();
 }
 
 // Update skills
-export async function updateSkills(skills: SkillCategory[], password: string): Promise<SkillCategory[]> {
-  const res = await fetch(`${API_URL}/content/skills`, {
-    method: "PUT",
-    headers: {
-      "Content-Type": "application/json",
-      "X-Admin-Password": password,
-    },
-    body: JSON.stringify(skills),
-  });
-  if (!res.ok) throw new Error("Failed to update skills");
-  return res.json();
+export async function updateSkills(
+  skills: SkillCategory[],
+  etag: string
+): Promise<Versioned<SkillCategory[]>> {
+  return putSection("skills", skills, etag);
 }
 
 // Get contacts
 export async function getContacts(): Promise<Contact[]> {
-  const res = await fetch(`${API_URL}/content/contacts`);
+  const res = await apiFetch(`${API_URL}/content/contacts?lang=all`);
   if (!res.ok) throw new Error("Failed to fetch contacts");
   return res.json();
 }
 
 // Update contacts
-export async function updateContacts(contacts: Contact[], password: string): Promise<Contact[]> {
-  const res = await fetch(`${API_URL}/content/contacts`, {
-    method: "PUT",
-    headers: {
-      "Content-Type": "application/json",
-      "X-Admin-Password": password,
-    },
-    body: JSON.stringify(contacts),
-  });
-  if (!res.ok) throw new Error("Failed to update contacts");
-  return res.json();
+export async function updateContacts(
+  contacts: Contact[],
+  etag: string
+): Promise<Versioned<Contact[]>> {
+  return putSection("contacts", contacts, etag);
 }
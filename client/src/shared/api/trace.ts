@@ -0,0 +1,22 @@
+// W3C Trace Context: every API request starts a trace that the server
+// continues, so its spans and log lines can be found by one trace id.
+// https://www.w3.org/TR/trace-context/#traceparent-header
+
+function randomHex(bytes: number): string {
+  const buf = new Uint8Array(bytes);
+  crypto.getRandomValues(buf);
+  return Array.from(buf, (b) => b.toString(16).padStart(2, "0")).join("");
+}
+
+// A new traceparent for a request: version 00, random trace and parent
+// ids, sampled; the server still applies its own sample ratio
+export function traceparent(): string {
+  return `00-${randomHex(16)}-${randomHex(8)}-01`;
+}
+
+// fetch with a traceparent header, unless the caller already set one
+export function apiFetch(url: string, init: RequestInit = {}): Promise<Response> {
+  const headers = new Headers(init.headers);
+  if (!headers.has("traceparent")) headers.set("traceparent", traceparent());
+  return fetch(url, { ...init, headers });
+}
@@ -62,6 +62,7 @@ export function useAnalytics() {
           pages: pagesVisited.current.size,
           theme,
           language,
+          device,
         });
         
         navigator.sendBeacon(
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"server/internal/migrate"
+	"server/migrations"
+)
+
+const migrateUsage = "usage: api migrate up | down [steps] | status"
+
+// runMigrate handles `api migrate up|down|status`
+func runMigrate(ctx context.Context, dbURL string, args []string) {
+	if len(args) == 0 {
+		fatal("Unknown migrate command", "usage", migrateUsage)
+	}
+
+	pool, err := pgxpool.New(ctx, dbURL)
+	if err != nil {
+		fatal("Failed to connect to database", "err", err)
+	}
+	defer pool.Close()
+
+	migrator, err := migrate.New(pool, migrations.FS)
+	if err != nil {
+		fatal("Failed to load migrations", "err", err)
+	}
+
+	switch args[0] {
+	case "up":
+		n, err := migrator.Up(ctx)
+		if err != nil {
+			fatal("Migration failed", "err", err)
+		}
+		slog.Info("Applied migrations", "count", n)
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				fatal("Invalid steps", "steps", args[1], "usage", migrateUsage)
+			}
+		}
+		n, err := migrator.Down(ctx, steps)
+		if err != nil {
+			fatal("Rollback failed", "err", err)
+		}
+		slog.Info("Reverted migrations", "count", n)
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			fatal("Failed to read migration status", "err", err)
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, s := range statuses {
+			state, appliedAt := "pending", "-"
+			if s.Applied {
+				state = "applied"
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if s.Modified {
+				state = "modified"
+			}
+			fmt.Fprintf(tw, "%03d\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+		}
+		tw.Flush()
+
+	default:
+		fatal("Unknown migrate command", "usage", migrateUsage)
+	}
+}
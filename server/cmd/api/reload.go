@@ -0,0 +1,70 @@
+package main
+
+import (
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-chi/cors"
+
+	"server/internal/config"
+	"server/internal/handler"
+)
+
+// reloader applies the runtime part of the configuration again on SIGHUP.
+// Environment variables cannot change in a running process, so in practice
+// the config file is what changes.
+type reloader struct {
+	path  string
+	live  *atomic.Pointer[config.Config]
+	apply func(config.Config)
+}
+
+// reload keeps the current configuration if the new one does not load or
+// validate; otherwise every reloadable setting is swapped at once and the
+// differences are logged. In-flight requests finish with the old values.
+func (rl *reloader) reload() {
+	next, err := config.Load(rl.path)
+	if err == nil {
+		err = next.Validate()
+	}
+	if err != nil {
+		slog.Error("Config reload rejected, keeping the current configuration", "errors", errorLines(err))
+		return
+	}
+
+	merged, changes, ignored := config.Merge(*rl.live.Load(), next)
+	for _, key := range ignored {
+		slog.Warn("Config reload: changed but needs a restart", "key", key)
+	}
+	if len(changes) == 0 {
+		slog.Info("Config reload: nothing to apply")
+		return
+	}
+	for _, change := range changes {
+		slog.Info("Config reload", "change", change)
+	}
+	rl.live.Store(&merged)
+	rl.apply(merged)
+}
+
+func corsOptions(cfg config.Config) cors.Options {
+	return cors.Options{
+		AllowedOrigins:   cfg.CORS.AllowedOrigins,
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-Admin-Password", "If-Match", "traceparent", "tracestate"},
+		ExposedHeaders:   []string{"Link", "ETag", "Retry-After", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy"},
+		AllowCredentials: cfg.CORS.AllowCredentials,
+		MaxAge:           int(cfg.CORS.MaxAge / time.Second),
+	}
+}
+
+// cachePolicy is the Cache-Control for browsers/CDN and how long the
+// in-process copy of public content is served before it is reloaded
+func cachePolicy(cfg config.Config) handler.CachePolicy {
+	return handler.CachePolicy{
+		MaxAge:               cfg.Content.CacheMaxAge,
+		StaleWhileRevalidate: cfg.Content.CacheStaleWhileRevalidate,
+		Refresh:              cfg.Content.CacheRefresh,
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"server/internal/config"
+)
+
+func TestReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("database:\n  storage: memory\ncors:\n  allowed_origins: [https://a.example]\n")
+	start, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var live atomic.Pointer[config.Config]
+	live.Store(&start)
+	var applied []config.Config
+	rl := &reloader{path: path, live: &live, apply: func(c config.Config) { applied = append(applied, c) }}
+
+	// Reloadable settings are applied, the port only after a restart
+	write("database:\n  storage: memory\nserver:\n  port: 9999\nlog:\n  level: debug\ncors:\n  allowed_origins: [https://b.example]\n")
+	rl.reload()
+	if len(applied) != 1 {
+		t.Fatalf("apply called %d times, want 1", len(applied))
+	}
+	got := *live.Load()
+	if got.Log.Level != slog.LevelDebug || !reflect.DeepEqual(got.CORS.AllowedOrigins, []string{"https://b.example"}) {
+		t.Errorf("live config = %+v, %+v: reloadable settings not applied", got.Log, got.CORS)
+	}
+	if got.Server.Port != start.Server.Port {
+		t.Errorf("port = %d, want %d until a restart", got.Server.Port, start.Server.Port)
+	}
+	if !reflect.DeepEqual(applied[0], got) {
+		t.Error("apply got another config than the live one")
+	}
+
+	// Broken or invalid files keep the current configuration
+	for name, content := range map[string]string{
+		"unparsable": "rate_limit:\n  auth: lots\n",
+		"invalid":    "database:\n  storage: memory\ncors:\n  allowed_origins: [\"*\"]\n",
+	} {
+		write(content)
+		rl.reload()
+		if len(applied) != 1 || !reflect.DeepEqual(*live.Load(), got) {
+			t.Errorf("%s file changed the configuration", name)
+		}
+	}
+
+	// Nothing to apply when nothing changed
+	write("database:\n  storage: memory\nserver:\n  port: 9999\nlog:\n  level: debug\ncors:\n  allowed_origins: [https://b.example]\n")
+	rl.reload()
+	if len(applied) != 1 {
+		t.Errorf("unchanged file applied again")
+	}
+}
@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+
+	"server/internal/entity"
+)
+
+// apiKeyPrefix tells API keys apart from access tokens in the
+// Authorization header
+const apiKeyPrefix = "pk_"
+
+// NewAPIKey returns the id of a new key, the token to hand out once,
+// "pk_<id>_<secret>", and the hash of the secret to store
+func NewAPIKey() (id, token, hash string) {
+	id = hex.EncodeToString(randomBytes(8))
+	secret := base64.RawURLEncoding.EncodeToString(randomBytes(32))
+	return id, apiKeyPrefix + id + "_" + secret, hashSecret(secret)
+}
+
+// IsAPIKey reports whether a bearer token is an API key
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, apiKeyPrefix)
+}
+
+// ParseAPIKey splits a key token into its id and the hash of its secret
+func ParseAPIKey(token string) (id, hash string, ok bool) {
+	rest, ok := strings.CutPrefix(token, apiKeyPrefix)
+	if !ok {
+		return "", "", false
+	}
+	// The id is hex, so the first '_' ends it; the secret may contain more
+	id, secret, ok := strings.Cut(rest, "_")
+	if !ok || id == "" || secret == "" {
+		return "", "", false
+	}
+	return id, hashSecret(secret), true
+}
+
+// Scopes lists what an API key can be granted: the permissions fit for
+// automation, and content:write narrowed to one section
+func Scopes() []string {
+	scopes := []string{string(ContentWrite), string(ContentPublish), string(AnalyticsRead)}
+	for _, section := range entity.Sections {
+		scopes = append(scopes, sectionScope(section))
+	}
+	return scopes
+}
+
+func sectionScope(section string) string {
+	return "content:" + section + ":write"
+}
+
+// ScopesAllow reports whether scopes grant perm. section is the content
+// section the request is about, or "" if it is not about a single one.
+func ScopesAllow(scopes []string, perm Permission, section string) bool {
+	for _, scope := range scopes {
+		if scope == string(perm) {
+			return true
+		}
+		if perm == ContentWrite && section != "" && scope == sectionScope(section) {
+			return true
+		}
+	}
+	return false
+}
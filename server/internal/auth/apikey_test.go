@@ -0,0 +1,53 @@
+package auth
+
+import "testing"
+
+func TestAPIKey(t *testing.T) {
+	id, token, hash := NewAPIKey()
+	if !IsAPIKey(token) {
+		t.Fatalf("IsAPIKey(%q) = false", token)
+	}
+	gotID, gotHash, ok := ParseAPIKey(token)
+	if !ok || gotID != id || !EqualHash(gotHash, hash) {
+		t.Errorf("ParseAPIKey() = %q, %q, %v, want %q, %q", gotID, gotHash, ok, id, hash)
+	}
+
+	if _, other, _ := ParseAPIKey(token + "x"); EqualHash(other, hash) {
+		t.Error("a changed secret has the same hash")
+	}
+	for _, bad := range []string{"", "pk_", "pk_abc", "pk__secret", "pk_abc_", "Bearer pk_abc_secret"} {
+		if _, _, ok := ParseAPIKey(bad); ok {
+			t.Errorf("ParseAPIKey(%q) ok", bad)
+		}
+	}
+}
+
+func TestScopesAllow(t *testing.T) {
+	tests := []struct {
+		scopes  []string
+		perm    Permission
+		section string
+		want    bool
+	}{
+		{[]string{"content:write"}, ContentWrite, "about", true},
+		{[]string{"content:write"}, ContentWrite, "", true},
+		{[]string{"content:projects:write"}, ContentWrite, "projects", true},
+		{[]string{"content:projects:write"}, ContentWrite, "about", false},
+		{[]string{"content:projects:write"}, ContentWrite, "", false},
+		{[]string{"content:projects:write"}, ContentPublish, "projects", false},
+		{[]string{"analytics:read"}, AnalyticsRead, "", true},
+		{[]string{"analytics:read"}, UsersManage, "", false},
+		{nil, AnalyticsRead, "", false},
+	}
+	for _, tt := range tests {
+		if got := ScopesAllow(tt.scopes, tt.perm, tt.section); got != tt.want {
+			t.Errorf("ScopesAllow(%v, %s, %q) = %v, want %v", tt.scopes, tt.perm, tt.section, got, tt.want)
+		}
+	}
+
+	for _, scope := range Scopes() {
+		if scope == string(UsersManage) || scope == string(KeysManage) || scope == string(AuditRead) {
+			t.Errorf("Scopes() offers %s to keys", scope)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+// Package auth hashes admin passwords and issues the tokens of an admin
+// session: short-lived signed access tokens and opaque refresh tokens.
+package auth
+
+import "golang.org/x/crypto/bcrypt"
+
+// dummyHash is compared against when the user does not exist, so a login
+// takes the same time whether or not the username is known
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("not a real password"), bcrypt.DefaultCost)
+
+// HashPassword returns the bcrypt hash of password
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// CheckPassword reports whether password matches hash. An empty hash
+// never matches but costs as much as a real comparison.
+func CheckPassword(hash, password string) bool {
+	if hash == "" {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
@@ -0,0 +1,43 @@
+package auth
+
+import "server/internal/entity"
+
+// Permission is one kind of protected action
+type Permission string
+
+const (
+	// ContentWrite covers editing and previewing content and its
+	// revisions
+	ContentWrite Permission = "content:write"
+	// ContentPublish covers POST /api/content/publish
+	ContentPublish Permission = "content:publish"
+	// AnalyticsRead covers GET /api/analytics
+	AnalyticsRead Permission = "analytics:read"
+	// UsersManage covers the admin account endpoints
+	UsersManage Permission = "users:manage"
+	// KeysManage covers the API key endpoints
+	KeysManage Permission = "keys:manage"
+	// AuditRead covers GET /api/admin/audit
+	AuditRead Permission = "audit:read"
+)
+
+var rolePermissions = map[entity.Role][]Permission{
+	entity.RoleOwner:   {ContentWrite, ContentPublish, AnalyticsRead, UsersManage, KeysManage, AuditRead},
+	entity.RoleEditor:  {ContentWrite, ContentPublish, AnalyticsRead},
+	entity.RoleAnalyst: {AnalyticsRead},
+}
+
+// Permissions returns what role may do; an unknown role may do nothing
+func Permissions(role entity.Role) []Permission {
+	return rolePermissions[role]
+}
+
+// Can reports whether role grants perm
+func Can(role entity.Role, perm Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
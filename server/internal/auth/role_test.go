@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"testing"
+
+	"server/internal/entity"
+)
+
+func TestCan(t *testing.T) {
+	tests := []struct {
+		role entity.Role
+		perm Permission
+		want bool
+	}{
+		{entity.RoleOwner, UsersManage, true},
+		{entity.RoleOwner, AuditRead, true},
+		{entity.RoleEditor, ContentWrite, true},
+		{entity.RoleEditor, ContentPublish, true},
+		{entity.RoleEditor, UsersManage, false},
+		{entity.RoleEditor, KeysManage, false},
+		{entity.RoleAnalyst, AnalyticsRead, true},
+		{entity.RoleAnalyst, ContentWrite, false},
+		{entity.Role("guest"), AnalyticsRead, false},
+	}
+	for _, tt := range tests {
+		if got := Can(tt.role, tt.perm); got != tt.want {
+			t.Errorf("Can(%s, %s) = %v, want %v", tt.role, tt.perm, got, tt.want)
+		}
+	}
+
+	if got := Permissions(entity.Role("guest")); len(got) != 0 {
+		t.Errorf("Permissions(unknown) = %v, want none", got)
+	}
+}
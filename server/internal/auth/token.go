@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"server/internal/entity"
+)
+
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// Claims is the payload of an access token
+type Claims struct {
+	SessionID string      `json:"sid"`
+	UserID    int64       `json:"uid"`
+	Username  string      `json:"sub"`
+	Role      entity.Role `json:"rol"`
+	ExpiresAt int64       `json:"exp"`
+}
+
+// Signer issues and verifies access tokens of the form
+// "<base64 claims>.<base64 HMAC-SHA256>"
+type Signer struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+func NewSigner(secret []byte, ttl time.Duration) *Signer {
+	return &Signer{secret: secret, ttl: ttl}
+}
+
+// Issue returns an access token of user for the session and its expiry
+func (s *Signer) Issue(sessionID string, user entity.AdminUser, now time.Time) (string, time.Time) {
+	expires := now.Add(s.ttl).Truncate(time.Second)
+	payload, _ := json.Marshal(Claims{
+		SessionID: sessionID,
+		UserID:    user.ID,
+		Username:  user.Username,
+		Role:      user.Role,
+		ExpiresAt: expires.Unix(),
+	})
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + s.sign(encoded), expires
+}
+
+// Verify checks the signature and expiry of token. Whether its session is
+// still active is up to the caller.
+func (s *Signer) Verify(token string, now time.Time) (Claims, error) {
+	var claims Claims
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(s.sign(encoded))) {
+		return claims, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return claims, ErrInvalidToken
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return claims, ErrInvalidToken
+	}
+	if now.Unix() >= claims.ExpiresAt {
+		return claims, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+func (s *Signer) sign(payload string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// NewSessionID returns a random session id
+func NewSessionID() string {
+	return hex.EncodeToString(randomBytes(16))
+}
+
+// NewRefreshToken returns a refresh token of the form "<session id>.<secret>"
+// and the hash to store for it
+func NewRefreshToken(sessionID string) (token, hash string) {
+	secret := base64.RawURLEncoding.EncodeToString(randomBytes(32))
+	return sessionID + "." + secret, hashSecret(secret)
+}
+
+// ParseRefreshToken splits a refresh token into its session id and the hash
+// of its secret
+func ParseRefreshToken(token string) (sessionID, hash string, ok bool) {
+	sessionID, secret, ok := strings.Cut(token, ".")
+	if !ok || sessionID == "" || secret == "" {
+		return "", "", false
+	}
+	return sessionID, hashSecret(secret), true
+}
+
+// EqualHash compares two refresh token hashes in constant time
+func EqualHash(a, b string) bool {
+	return hmac.Equal([]byte(a), []byte(b))
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return b
+}
@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"server/internal/entity"
+)
+
+func TestSignerRoundTrip(t *testing.T) {
+	signer := NewSigner([]byte("secret"), 15*time.Minute)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 500, time.UTC)
+	user := entity.AdminUser{ID: 7, Username: "admin", Role: entity.RoleEditor}
+
+	token, expires := signer.Issue("sess", user, now)
+	if want := now.Add(15 * time.Minute).Truncate(time.Second); !expires.Equal(want) {
+		t.Errorf("expires = %v, want %v", expires, want)
+	}
+
+	claims, err := signer.Verify(token, now)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	want := Claims{SessionID: "sess", UserID: 7, Username: "admin", Role: entity.RoleEditor, ExpiresAt: expires.Unix()}
+	if claims != want {
+		t.Errorf("claims = %+v, want %+v", claims, want)
+	}
+}
+
+func TestSignerRejects(t *testing.T) {
+	signer := NewSigner([]byte("secret"), time.Minute)
+	now := time.Now()
+	token, expires := signer.Issue("sess", entity.AdminUser{ID: 1, Username: "admin", Role: entity.RoleAnalyst}, now)
+	payload, signature, _ := strings.Cut(token, ".")
+
+	otherSecret, _ := NewSigner([]byte("other"), time.Minute).Issue("sess", entity.AdminUser{}, now)
+	forged := base64.RawURLEncoding.EncodeToString([]byte(`{"sid":"sess","uid":1,"sub":"admin","rol":"owner","exp":9999999999}`))
+
+	tests := []struct {
+		name  string
+		token string
+		at    time.Time
+	}{
+		{name: "expired", token: token, at: expires},
+		{name: "other secret", token: otherSecret, at: now},
+		{name: "changed claims", token: forged + "." + signature, at: now},
+		{name: "no signature", token: payload, at: now},
+		{name: "empty", token: "", at: now},
+		{name: "not base64", token: "%%%." + signer.sign("%%%"), at: now},
+		{name: "not json", token: "eA." + signer.sign("eA"), at: now},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := signer.Verify(tt.token, tt.at); err != ErrInvalidToken {
+				t.Errorf("Verify() error = %v, want ErrInvalidToken", err)
+			}
+		})
+	}
+}
+
+func TestRefreshToken(t *testing.T) {
+	token, hash := NewRefreshToken("sess")
+	sessionID, parsedHash, ok := ParseRefreshToken(token)
+	if !ok || sessionID != "sess" || !EqualHash(parsedHash, hash) {
+		t.Fatalf("ParseRefreshToken(%q) = %q, %q, %v", token, sessionID, parsedHash, ok)
+	}
+	if strings.Contains(hash, strings.TrimPrefix(token, "sess.")) {
+		t.Error("the stored hash contains the secret")
+	}
+
+	other, otherHash := NewRefreshToken("sess")
+	if other == token || EqualHash(otherHash, hash) {
+		t.Error("two refresh tokens are the same")
+	}
+
+	for _, bad := range []string{"", "sess", "sess.", ".secret"} {
+		if _, _, ok := ParseRefreshToken(bad); ok {
+			t.Errorf("ParseRefreshToken(%q) accepted", bad)
+		}
+	}
+}
+
+func TestPassword(t *testing.T) {
+	hash, err := HashPassword("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		hash, password string
+		want           bool
+	}{
+		{hash, "correct horse", true},
+		{hash, "correct horse ", false},
+		{hash, "", false},
+		{"", "", false},
+		{"", "correct horse", false},
+	}
+	for _, tt := range tests {
+		if got := CheckPassword(tt.hash, tt.password); got != tt.want {
+			t.Errorf("CheckPassword(%q, %q) = %v, want %v", tt.hash, tt.password, got, tt.want)
+		}
+	}
+}
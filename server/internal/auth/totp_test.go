@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA-1 key of the RFC 6238 test vectors,
+// "12345678901234567890" in base32
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestHOTPVectors(t *testing.T) {
+	key, err := base32NoPad.DecodeString(rfcSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// RFC 6238 appendix B, cut to the last six digits
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		if got := hotp(key, tt.unix/totpPeriod); got != tt.want {
+			t.Errorf("hotp at %d = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestVerifyTOTP(t *testing.T) {
+	at := time.Unix(1111111111, 0)
+	step := at.Unix() / totpPeriod
+
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		wantStep int64
+		wantOK   bool
+	}{
+		{name: "current code", secret: rfcSecret, code: "050471", wantStep: step, wantOK: true},
+		{name: "lower case secret", secret: "gezdgnbvgy3tqojqgezdgnbvgy3tqojq", code: "050471", wantStep: step, wantOK: true},
+		{name: "previous period", secret: rfcSecret, code: codeAt(t, at.Add(-totpPeriod*time.Second)), wantStep: step - 1, wantOK: true},
+		{name: "next period", secret: rfcSecret, code: codeAt(t, at.Add(totpPeriod*time.Second)), wantStep: step + 1, wantOK: true},
+		{name: "two periods ago", secret: rfcSecret, code: codeAt(t, at.Add(-2*totpPeriod*time.Second))},
+		{name: "wrong code", secret: rfcSecret, code: "000000"},
+		{name: "too short", secret: rfcSecret, code: "05047"},
+		{name: "eight digits", secret: rfcSecret, code: "14050471"},
+		{name: "bad secret", secret: "not base32!", code: "050471"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStep, ok := VerifyTOTP(tt.secret, tt.code, at)
+			if ok != tt.wantOK || gotStep != tt.wantStep {
+				t.Errorf("VerifyTOTP() = %d, %v, want %d, %v", gotStep, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+func codeAt(t *testing.T, at time.Time) string {
+	t.Helper()
+	key, err := base32NoPad.DecodeString(rfcSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hotp(key, at.Unix()/totpPeriod)
+}
+
+func TestNewTOTPSecret(t *testing.T) {
+	secret := NewTOTPSecret()
+	key, err := base32NoPad.DecodeString(secret)
+	if err != nil || len(key) != 20 {
+		t.Fatalf("secret %q decodes to %d bytes, %v", secret, len(key), err)
+	}
+	if NewTOTPSecret() == secret {
+		t.Error("two secrets are the same")
+	}
+}
+
+func TestTOTPURI(t *testing.T) {
+	u, err := url.Parse(TOTPURI("My Site", "admin", rfcSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Scheme != "otpauth" || u.Host != "totp" || u.Path != "/My Site:admin" {
+		t.Errorf("URI = %s", u)
+	}
+	q := u.Query()
+	want := map[string]string{"secret": rfcSecret, "issuer": "My Site", "algorithm": "SHA1", "digits": "6", "period": "30"}
+	for k, v := range want {
+		if q.Get(k) != v {
+			t.Errorf("%s = %q, want %q", k, q.Get(k), v)
+		}
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, hashes := NewRecoveryCodes()
+	if len(codes) != RecoveryCodeCount || len(hashes) != RecoveryCodeCount {
+		t.Fatalf("got %d codes and %d hashes, want %d", len(codes), len(hashes), RecoveryCodeCount)
+	}
+	format := regexp.MustCompile(`^[a-z2-7]{4}-[a-z2-7]{4}-[a-z2-7]{4}$`)
+	seen := make(map[string]bool)
+	for i, code := range codes {
+		if !format.MatchString(code) {
+			t.Errorf("code %q has the wrong format", code)
+		}
+		if seen[code] {
+			t.Errorf("code %q repeats", code)
+		}
+		seen[code] = true
+		if HashRecoveryCode(code) != hashes[i] {
+			t.Errorf("hash %d does not belong to its code", i)
+		}
+	}
+}
+
+func TestHashRecoveryCodeNormalizes(t *testing.T) {
+	want := HashRecoveryCode("k7qm-x2rd-9fhp")
+	for _, typed := range []string{"K7QM-X2RD-9FHP", "k7qm x2rd 9fhp", "k7qmx2rd9fhp", " k7qm-x2rd-9fhp "} {
+		if got := HashRecoveryCode(typed); got != want {
+			t.Errorf("HashRecoveryCode(%q) differs", typed)
+		}
+	}
+	if HashRecoveryCode("k7qm-x2rd-9fhq") == want {
+		t.Error("different codes have the same hash")
+	}
+}
@@ -0,0 +1,246 @@
+// Package config loads the API settings: built-in defaults, then an
+// optional YAML file, then environment variables, each overriding the one
+// before. Load does not validate; call Validate before using the result.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"server/internal/locale"
+	"server/internal/logging"
+	"server/internal/ratelimit"
+	"server/internal/tracing"
+)
+
+const (
+	Development = "development"
+	Production  = "production"
+)
+
+// Config is the whole API configuration. The env tag names the variable
+// that overrides a field; secret fields are hidden by Redacted; reload
+// fields take effect on SIGHUP, the rest only on restart.
+type Config struct {
+	// Env is development or production; production refuses to start with
+	// generated secrets or without an explicit CORS origin list
+	Env       string          `yaml:"env" env:"APP_ENV"`
+	Log       LogConfig       `yaml:"log"`
+	Server    ServerConfig    `yaml:"server"`
+	Database  DatabaseConfig  `yaml:"database"`
+	CORS      CORSConfig      `yaml:"cors"`
+	Content   ContentConfig   `yaml:"content"`
+	Analytics AnalyticsConfig `yaml:"analytics"`
+	Preview   PreviewConfig   `yaml:"preview"`
+	Auth      AuthConfig      `yaml:"auth"`
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
+	Audit     AuditConfig     `yaml:"audit"`
+	Metrics   MetricsConfig   `yaml:"metrics"`
+	Tracing   TracingConfig   `yaml:"tracing"`
+}
+
+type LogConfig struct {
+	// Level is debug, info, warn or error
+	Level slog.Level `yaml:"level" env:"LOG_LEVEL" reload:"true"`
+	// Format is text or json
+	Format string `yaml:"format" env:"LOG_FORMAT"`
+}
+
+type ServerConfig struct {
+	Port            int           `yaml:"port" env:"SERVER_PORT"`
+	ReadTimeout     time.Duration `yaml:"read_timeout" env:"SERVER_READ_TIMEOUT"`
+	WriteTimeout    time.Duration `yaml:"write_timeout" env:"SERVER_WRITE_TIMEOUT"`
+	IdleTimeout     time.Duration `yaml:"idle_timeout" env:"SERVER_IDLE_TIMEOUT"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SERVER_SHUTDOWN_TIMEOUT"`
+	// TrustProxyHeaders takes the client address from X-Forwarded-For
+	TrustProxyHeaders bool `yaml:"trust_proxy_headers" env:"TRUST_PROXY_HEADERS"`
+}
+
+type DatabaseConfig struct {
+	// Storage is postgres or memory
+	Storage         string        `yaml:"storage" env:"STORAGE"`
+	URL             string        `yaml:"url" env:"DB_URL" secret:"url"`
+	MaxConns        int32         `yaml:"max_conns" env:"DB_MAX_CONNS"`
+	MinConns        int32         `yaml:"min_conns" env:"DB_MIN_CONNS"`
+	MaxConnLifetime time.Duration `yaml:"max_conn_lifetime" env:"DB_MAX_CONN_LIFETIME"`
+	MaxConnIdleTime time.Duration `yaml:"max_conn_idle_time" env:"DB_MAX_CONN_IDLE_TIME"`
+}
+
+type CORSConfig struct {
+	// AllowedOrigins defaults to http://localhost:3000 in development
+	AllowedOrigins []string `yaml:"allowed_origins" env:"CORS_ALLOWED_ORIGINS" reload:"true"`
+	// AllowCredentials lets browsers send cookies and Authorization
+	// cross-origin; it cannot be combined with the "*" origin
+	AllowCredentials bool          `yaml:"allow_credentials" env:"CORS_ALLOW_CREDENTIALS" reload:"true"`
+	MaxAge           time.Duration `yaml:"max_age" env:"CORS_MAX_AGE" reload:"true"`
+}
+
+type ContentConfig struct {
+	Locales                   string        `yaml:"locales" env:"CONTENT_LOCALES"`
+	LocaleFallback            string        `yaml:"locale_fallback" env:"CONTENT_LOCALE_FALLBACK"`
+	CacheMaxAge               time.Duration `yaml:"cache_max_age" env:"CONTENT_CACHE_MAX_AGE" reload:"true"`
+	CacheStaleWhileRevalidate time.Duration `yaml:"cache_stale_while_revalidate" env:"CONTENT_CACHE_STALE_WHILE_REVALIDATE" reload:"true"`
+	CacheRefresh              time.Duration `yaml:"cache_refresh" env:"CONTENT_CACHE_REFRESH" reload:"true"`
+}
+
+// ParseLocales returns the content locales and their fallback chains
+func (c ContentConfig) ParseLocales() (locale.Set, locale.Fallback, error) {
+	locales, err := locale.Parse(c.Locales)
+	if err != nil {
+		return nil, nil, err
+	}
+	fallback, err := locale.ParseFallback(c.LocaleFallback, locales)
+	if err != nil {
+		return nil, nil, err
+	}
+	return locales, fallback, nil
+}
+
+type AnalyticsConfig struct {
+	// BotUserAgents are case-insensitive User-Agent substrings whose
+	// tracking events are dropped
+	BotUserAgents []string `yaml:"bot_user_agents" env:"ANALYTICS_BOT_USER_AGENTS" reload:"true"`
+}
+
+type PreviewConfig struct {
+	Secret   string        `yaml:"secret" env:"PREVIEW_SECRET" secret:"true"`
+	TokenTTL time.Duration `yaml:"token_ttl" env:"PREVIEW_TOKEN_TTL"`
+}
+
+type AuthConfig struct {
+	Secret     string        `yaml:"secret" env:"AUTH_SECRET" secret:"true"`
+	AccessTTL  time.Duration `yaml:"access_ttl" env:"AUTH_ACCESS_TTL"`
+	RefreshTTL time.Duration `yaml:"refresh_ttl" env:"AUTH_REFRESH_TTL"`
+	TOTPIssuer string        `yaml:"totp_issuer" env:"AUTH_TOTP_ISSUER"`
+	// LegacyPassword keeps X-Admin-Password working with AdminPassword
+	LegacyPassword bool `yaml:"legacy_password" env:"AUTH_LEGACY_PASSWORD"`
+	// AdminUsername/AdminPassword create the first owner account
+	AdminUsername    string        `yaml:"admin_username" env:"ADMIN_USERNAME"`
+	AdminPassword    string        `yaml:"admin_password" env:"ADMIN_PASSWORD" secret:"true"`
+	LockoutThreshold int           `yaml:"lockout_threshold" env:"AUTH_LOCKOUT_THRESHOLD"`
+	LockoutBase      time.Duration `yaml:"lockout_base" env:"AUTH_LOCKOUT_BASE"`
+	LockoutMax       time.Duration `yaml:"lockout_max" env:"AUTH_LOCKOUT_MAX"`
+}
+
+type RateLimitConfig struct {
+	// Store is memory or postgres
+	Store        string          `yaml:"store" env:"RATE_LIMIT_STORE"`
+	Auth         ratelimit.Limit `yaml:"auth" env:"RATE_LIMIT_AUTH" reload:"true"`
+	Admin        ratelimit.Limit `yaml:"admin" env:"RATE_LIMIT_ADMIN" reload:"true"`
+	Track        ratelimit.Limit `yaml:"track" env:"RATE_LIMIT_TRACK" reload:"true"`
+	TrackVisitor ratelimit.Limit `yaml:"track_visitor" env:"RATE_LIMIT_TRACK_VISITOR" reload:"true"`
+}
+
+type AuditConfig struct {
+	// Retention is how long entries are kept, 0 keeps them forever
+	Retention time.Duration `yaml:"retention" env:"AUDIT_RETENTION"`
+}
+
+// MetricsConfig exposes /metrics on Listen, a separate address that is
+// meant to stay internal, or else on the API port behind Token. With
+// neither set the endpoint is off.
+type MetricsConfig struct {
+	Listen string `yaml:"listen" env:"METRICS_LISTEN"`
+	Token  string `yaml:"token" env:"METRICS_TOKEN" secret:"true"`
+}
+
+type TracingConfig struct {
+	// Exporter is none, stdout or otlp
+	Exporter string `yaml:"exporter" env:"TRACING_EXPORTER"`
+	// Endpoint is the OTLP/HTTP collector URL; empty falls back to
+	// OTEL_EXPORTER_OTLP_ENDPOINT
+	Endpoint    string  `yaml:"endpoint" env:"TRACING_ENDPOINT"`
+	ServiceName string  `yaml:"service_name" env:"TRACING_SERVICE_NAME"`
+	SampleRatio float64 `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO"`
+}
+
+// Default returns the settings used when nothing overrides them
+func Default() Config {
+	return Config{
+		Env: Development,
+		Log: LogConfig{Level: slog.LevelInfo, Format: logging.Text},
+		Server: ServerConfig{
+			Port:            8080,
+			ReadTimeout:     10 * time.Second,
+			WriteTimeout:    10 * time.Second,
+			IdleTimeout:     time.Minute,
+			ShutdownTimeout: 5 * time.Second,
+		},
+		Database: DatabaseConfig{
+			Storage:         "postgres",
+			MaxConns:        10,
+			MaxConnLifetime: time.Hour,
+			MaxConnIdleTime: 30 * time.Minute,
+		},
+		CORS: CORSConfig{AllowCredentials: true, MaxAge: 5 * time.Minute},
+		Content: ContentConfig{
+			Locales:                   strings.Join(locale.Default, ","),
+			CacheMaxAge:               time.Minute,
+			CacheStaleWhileRevalidate: 5 * time.Minute,
+			CacheRefresh:              30 * time.Second,
+		},
+		Analytics: AnalyticsConfig{
+			BotUserAgents: []string{"bot", "crawler", "spider", "slurp", "headlesschrome", "lighthouse", "python-requests"},
+		},
+		Preview: PreviewConfig{TokenTTL: time.Hour},
+		Auth: AuthConfig{
+			AccessTTL:        15 * time.Minute,
+			RefreshTTL:       30 * 24 * time.Hour,
+			TOTPIssuer:       "Portfolio",
+			AdminUsername:    "admin",
+			LockoutThreshold: 5,
+			LockoutBase:      time.Minute,
+			LockoutMax:       time.Hour,
+		},
+		RateLimit: RateLimitConfig{
+			Store:        "memory",
+			Auth:         ratelimit.Limit{Requests: 10, Per: time.Minute},
+			Admin:        ratelimit.Limit{Requests: 300, Per: time.Minute},
+			Track:        ratelimit.Limit{Requests: 60, Per: time.Minute},
+			TrackVisitor: ratelimit.Limit{Requests: 30, Per: time.Minute},
+		},
+		Audit: AuditConfig{Retention: 90 * 24 * time.Hour},
+		Tracing: TracingConfig{
+			Exporter:    tracing.None,
+			ServiceName: "portfolio-api",
+			SampleRatio: 1,
+		},
+	}
+}
+
+// Load reads the defaults, then path if it is not empty, then the
+// environment
+func Load(path string) (Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return cfg, fmt.Errorf("read config: %w", err)
+		}
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		// An empty file is not an error, it just changes nothing
+		if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+			return cfg, fmt.Errorf("parse %s: %w", path, err)
+		}
+	}
+
+	if err := applyEnv(reflect.ValueOf(&cfg).Elem(), os.LookupEnv); err != nil {
+		return cfg, err
+	}
+
+	if len(cfg.CORS.AllowedOrigins) == 0 && cfg.Env == Development {
+		cfg.CORS.AllowedOrigins = []string{"http://localhost:3000"}
+	}
+	return cfg, nil
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"net/url"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "[redacted]"
+
+// Redacted returns a copy with secrets replaced, safe to print or log.
+// Connection URLs keep everything but the password.
+func (c Config) Redacted() Config {
+	redact(reflect.ValueOf(&c).Elem())
+	return c
+}
+
+func redact(v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field, value := t.Field(i), v.Field(i)
+		switch field.Tag.Get("secret") {
+		case "true":
+			if value.String() != "" {
+				value.SetString(redacted)
+			}
+		case "url":
+			if u, err := url.Parse(value.String()); err == nil {
+				value.SetString(u.Redacted())
+			} else {
+				value.SetString(redacted)
+			}
+		default:
+			if value.Kind() == reflect.Struct && field.Tag.Get("env") == "" {
+				redact(value)
+			}
+		}
+	}
+}
+
+// YAML renders the configuration in the format Load reads
+func (c Config) YAML() ([]byte, error) {
+	return yaml.Marshal(c)
+}
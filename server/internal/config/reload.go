@@ -0,0 +1,39 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Merge returns current with the reloadable settings of next applied.
+// changes describes every applied difference as "key: old -> new";
+// ignored names settings that differ but need a restart, without their
+// values since some of them are secrets.
+func Merge(current, next Config) (merged Config, changes, ignored []string) {
+	merged = current
+	merge(reflect.ValueOf(&merged).Elem(), reflect.ValueOf(next), "", &changes, &ignored)
+	return merged, changes, ignored
+}
+
+func merge(dst, src reflect.Value, prefix string, changes, ignored *[]string) {
+	t := dst.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := prefix + field.Tag.Get("yaml")
+		d, s := dst.Field(i), src.Field(i)
+
+		if field.Tag.Get("env") == "" && d.Kind() == reflect.Struct {
+			merge(d, s, key+".", changes, ignored)
+			continue
+		}
+		if reflect.DeepEqual(d.Interface(), s.Interface()) {
+			continue
+		}
+		if field.Tag.Get("reload") != "true" {
+			*ignored = append(*ignored, key)
+			continue
+		}
+		*changes = append(*changes, fmt.Sprintf("%s: %v -> %v", key, d.Interface(), s.Interface()))
+		d.Set(s)
+	}
+}
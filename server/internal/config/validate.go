@@ -0,0 +1,111 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+
+	"server/internal/logging"
+	"server/internal/tracing"
+)
+
+// minSecretLength is the shortest signing secret accepted in production
+const minSecretLength = 32
+
+// Validate reports every invalid setting at once, so a broken deployment
+// is fixed in one go
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Env == Development || c.Env == Production, "env (APP_ENV) must be %s or %s, got %q", Development, Production, c.Env)
+
+	check(c.Log.Format == logging.Text || c.Log.Format == logging.JSON, "log.format (LOG_FORMAT) must be %s or %s, got %q", logging.Text, logging.JSON, c.Log.Format)
+
+	s := c.Server
+	check(s.Port > 0 && s.Port < 65536, "server.port (SERVER_PORT) must be 1-65535, got %d", s.Port)
+	check(s.ReadTimeout > 0, "server.read_timeout (SERVER_READ_TIMEOUT) must be positive")
+	check(s.WriteTimeout > 0, "server.write_timeout (SERVER_WRITE_TIMEOUT) must be positive")
+	check(s.IdleTimeout > 0, "server.idle_timeout (SERVER_IDLE_TIMEOUT) must be positive")
+	check(s.ShutdownTimeout > 0, "server.shutdown_timeout (SERVER_SHUTDOWN_TIMEOUT) must be positive")
+
+	db := c.Database
+	check(db.Storage == "postgres" || db.Storage == "memory", "database.storage (STORAGE) must be postgres or memory, got %q", db.Storage)
+	if db.Storage == "postgres" {
+		check(db.URL != "", "database.url (DB_URL) is required")
+		check(db.MaxConns > 0, "database.max_conns (DB_MAX_CONNS) must be positive")
+		check(db.MinConns >= 0 && db.MinConns <= db.MaxConns, "database.min_conns (DB_MIN_CONNS) must be between 0 and max_conns")
+		check(db.MaxConnLifetime > 0, "database.max_conn_lifetime (DB_MAX_CONN_LIFETIME) must be positive")
+		check(db.MaxConnIdleTime > 0, "database.max_conn_idle_time (DB_MAX_CONN_IDLE_TIME) must be positive")
+	}
+
+	check(len(c.CORS.AllowedOrigins) > 0, "cors.allowed_origins (CORS_ALLOWED_ORIGINS) is required in %s", c.Env)
+	for _, origin := range c.CORS.AllowedOrigins {
+		u, err := url.Parse(origin)
+		check(origin == "*" || err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" && (u.Path == "" || u.Path == "/"),
+			"cors.allowed_origins: %q is not an origin like https://example.com", origin)
+		check(origin != "*" || !c.CORS.AllowCredentials, "cors.allowed_origins: \"*\" cannot be combined with cors.allow_credentials (CORS_ALLOW_CREDENTIALS)")
+	}
+	check(c.CORS.MaxAge >= 0, "cors.max_age (CORS_MAX_AGE) must not be negative")
+
+	if _, _, err := c.Content.ParseLocales(); err != nil {
+		errs = append(errs, fmt.Errorf("content.locales/locale_fallback (CONTENT_LOCALES/CONTENT_LOCALE_FALLBACK): %w", err))
+	}
+	check(c.Content.CacheMaxAge >= 0, "content.cache_max_age (CONTENT_CACHE_MAX_AGE) must not be negative")
+	check(c.Content.CacheStaleWhileRevalidate >= 0, "content.cache_stale_while_revalidate (CONTENT_CACHE_STALE_WHILE_REVALIDATE) must not be negative")
+	check(c.Content.CacheRefresh >= 0, "content.cache_refresh (CONTENT_CACHE_REFRESH) must not be negative")
+
+	check(c.Preview.TokenTTL > 0, "preview.token_ttl (PREVIEW_TOKEN_TTL) must be positive")
+
+	a := c.Auth
+	check(a.AccessTTL > 0, "auth.access_ttl (AUTH_ACCESS_TTL) must be positive")
+	check(a.RefreshTTL >= a.AccessTTL, "auth.refresh_ttl (AUTH_REFRESH_TTL) must not be shorter than access_ttl")
+	check(a.TOTPIssuer != "", "auth.totp_issuer (AUTH_TOTP_ISSUER) must not be empty")
+	check(a.AdminUsername != "", "auth.admin_username (ADMIN_USERNAME) must not be empty")
+	check(!a.LegacyPassword || a.AdminPassword != "", "auth.legacy_password (AUTH_LEGACY_PASSWORD) needs auth.admin_password (ADMIN_PASSWORD)")
+	check(a.LockoutThreshold >= 0, "auth.lockout_threshold (AUTH_LOCKOUT_THRESHOLD) must not be negative")
+	if a.LockoutThreshold > 0 {
+		check(a.LockoutBase > 0, "auth.lockout_base (AUTH_LOCKOUT_BASE) must be positive")
+		check(a.LockoutMax >= a.LockoutBase, "auth.lockout_max (AUTH_LOCKOUT_MAX) must not be shorter than lockout_base")
+	}
+
+	rl := c.RateLimit
+	check(rl.Store == "memory" || rl.Store == "postgres", "rate_limit.store (RATE_LIMIT_STORE) must be memory or postgres, got %q", rl.Store)
+	check(rl.Store != "postgres" || db.Storage == "postgres", "rate_limit.store (RATE_LIMIT_STORE) postgres needs database.storage postgres")
+
+	check(c.Audit.Retention >= 0, "audit.retention (AUDIT_RETENTION) must not be negative")
+
+	if c.Metrics.Listen != "" {
+		_, port, err := net.SplitHostPort(c.Metrics.Listen)
+		check(err == nil && port != "", "metrics.listen (METRICS_LISTEN) must be host:port like 127.0.0.1:9090, got %q", c.Metrics.Listen)
+		check(port != strconv.Itoa(s.Port), "metrics.listen (METRICS_LISTEN) must not use the API port %d", s.Port)
+	}
+
+	t := c.Tracing
+	check(t.Exporter == tracing.None || t.Exporter == tracing.Stdout || t.Exporter == tracing.OTLP,
+		"tracing.exporter (TRACING_EXPORTER) must be %s, %s or %s, got %q", tracing.None, tracing.Stdout, tracing.OTLP, t.Exporter)
+	if t.Endpoint != "" {
+		u, err := url.Parse(t.Endpoint)
+		check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "", "tracing.endpoint (TRACING_ENDPOINT) must be a URL like http://localhost:4318, got %q", t.Endpoint)
+	}
+	check(t.ServiceName != "", "tracing.service_name (TRACING_SERVICE_NAME) must not be empty")
+	check(t.SampleRatio >= 0 && t.SampleRatio <= 1, "tracing.sample_ratio (TRACING_SAMPLE_RATIO) must be between 0 and 1")
+
+	// Generated secrets change on every restart and differ between
+	// replicas, which is only acceptable on a developer machine
+	if c.Env == Production {
+		check(len(a.Secret) >= minSecretLength, "auth.secret (AUTH_SECRET) must be at least %d characters in production", minSecretLength)
+		check(len(c.Preview.Secret) >= minSecretLength, "preview.secret (PREVIEW_SECRET) must be at least %d characters in production", minSecretLength)
+		check(!a.LegacyPassword, "auth.legacy_password (AUTH_LEGACY_PASSWORD) must be off in production")
+		check(a.AdminPassword != "", "auth.admin_password (ADMIN_PASSWORD) is required in production")
+		check(c.Metrics.Token == "" || len(c.Metrics.Token) >= minSecretLength, "metrics.token (METRICS_TOKEN) must be at least %d characters in production", minSecretLength)
+	}
+
+	return errors.Join(errs...)
+}
@@ -34,19 +34,19 @@ type DailyStats struct {
 }
 
 type AnalyticsData struct {
-	TotalVisits        int                    `json:"total_visits"`
-	UniqueVisitors     int                    `json:"unique_visitors"`
-	TodayVisits        int                    `json:"today_visits"`
-	WeekVisits         int                    `json:"week_visits"`
-	AvgSessionDuration float64                `json:"avg_session_duration"`
-	BounceRate         float64                `json:"bounce_rate"`
-	PageViews          map[string]int         `json:"page_views"`
-	TopPages           []TopPage              `json:"top_pages"`
-	VisitsByDay        []DayVisits            `json:"visits_by_day"`
-	VisitsByHour       []int                  `json:"visits_by_hour"`
-	Devices            DeviceStats            `json:"devices"`
-	Themes             ThemeStats             `json:"themes"`
-	Languages          LanguageStats          `json:"languages"`
+	TotalVisits        int            `json:"total_visits"`
+	UniqueVisitors     int            `json:"unique_visitors"`
+	TodayVisits        int            `json:"today_visits"`
+	WeekVisits         int            `json:"week_visits"`
+	AvgSessionDuration float64        `json:"avg_session_duration"`
+	BounceRate         float64        `json:"bounce_rate"`
+	PageViews          map[string]int `json:"page_views"`
+	TopPages           []TopPage      `json:"top_pages"`
+	VisitsByDay        []DayVisits    `json:"visits_by_day"`
+	VisitsByHour       []int          `json:"visits_by_hour"`
+	Devices            DeviceStats    `json:"devices"`
+	Themes             ThemeStats     `json:"themes"`
+	Languages          LanguageStats  `json:"languages"`
 }
 
 type TopPage struct {
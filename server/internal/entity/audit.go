@@ -0,0 +1,34 @@
+package entity
+
+import "time"
+
+// AuditEntry records one administrative request: who made it, what it was
+// about and how it ended. BeforeHash/AfterHash are SHA-256 of the section
+// snapshots around a content change.
+type AuditEntry struct {
+	ID         int64     `json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	Actor      string    `json:"actor"`
+	Action     string    `json:"action"`
+	Section    string    `json:"section,omitempty"`
+	EntityIDs  []string  `json:"entity_ids"`
+	BeforeHash string    `json:"before_hash,omitempty"`
+	AfterHash  string    `json:"after_hash,omitempty"`
+	Status     int       `json:"status"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+	RequestID  string    `json:"request_id"`
+}
+
+// AuditFilter narrows a listing of the audit log. Zero fields match
+// everything; BeforeID pages back from an entry.
+type AuditFilter struct {
+	Actor    string
+	Action   string
+	Section  string
+	Status   int
+	Since    *time.Time
+	Until    *time.Time
+	BeforeID int64
+	Limit    int
+}
@@ -0,0 +1,69 @@
+package entity
+
+import "time"
+
+// Role decides what an admin account may do
+type Role string
+
+const (
+	// RoleOwner can do everything, including managing accounts
+	RoleOwner Role = "owner"
+	// RoleEditor changes and publishes content
+	RoleEditor Role = "editor"
+	// RoleAnalyst only reads analytics
+	RoleAnalyst Role = "analyst"
+)
+
+// Roles lists every role
+var Roles = []Role{RoleOwner, RoleEditor, RoleAnalyst}
+
+// AdminUser is an account of the admin panel
+type AdminUser struct {
+	ID           int64     `json:"id"`
+	Username     string    `json:"username"`
+	Role         Role      `json:"role"`
+	PasswordHash string    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+
+	// TOTPSecret is set once enrollment starts, TOTPEnabled once the first
+	// code is confirmed
+	TOTPSecret    string   `json:"-"`
+	TOTPEnabled   bool     `json:"totp_enabled"`
+	TOTPLastStep  int64    `json:"-"`
+	RecoveryCodes []string `json:"-"`
+}
+
+// AuthSession is one login of an admin user. Only the hash of its current
+// refresh token is kept.
+type AuthSession struct {
+	ID          string
+	UserID      int64
+	RefreshHash string
+	ExpiresAt   time.Time
+	RevokedAt   *time.Time
+	CreatedAt   time.Time
+}
+
+// Active reports whether the session can still be used at t
+func (s AuthSession) Active(t time.Time) bool {
+	return s.RevokedAt == nil && t.Before(s.ExpiresAt)
+}
+
+// APIKey is a credential for automation with a fixed set of scopes. Only
+// the hash of its secret is kept.
+type APIKey struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	SecretHash string     `json:"-"`
+	CreatedBy  *int64     `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+}
+
+// Active reports whether the key can be used at t
+func (k APIKey) Active(t time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || t.Before(*k.ExpiresAt))
+}
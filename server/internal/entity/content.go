@@ -4,33 +4,34 @@ import "time"
 
 type AboutContent struct {
 	ID        int64             `json:"id"`
-	Name      map[string]string `json:"name"`
-	Username  string            `json:"username"`
-	Title     map[string]string `json:"title"`
-	Bio       map[string]string `json:"bio"`
-	Photo     string            `json:"photo"`
+	Name      map[string]string `json:"name" validate:"required,localized"`
+	Username  string            `json:"username" validate:"required"`
+	Title     map[string]string `json:"title" validate:"required,localized"`
+	Bio       map[string]string `json:"bio" validate:"locales"`
+	Photo     string            `json:"photo" validate:"url"`
 	Stats     []Stat            `json:"stats"`
 	UpdatedAt time.Time         `json:"updated_at"`
 }
 
 type Stat struct {
-	Value map[string]string `json:"value"`
-	Label map[string]string `json:"label"`
-	Icon  string            `json:"icon"`
-	Color string            `json:"color"`
+	Value map[string]string `json:"value" validate:"required,localized"`
+	Label map[string]string `json:"label" validate:"required,localized"`
+	Icon  string            `json:"icon" validate:"required"`
+	Color string            `json:"color" validate:"required,hexcolor"`
 }
 
 type Project struct {
-	ID          string            `json:"id"`
-	Title       map[string]string `json:"title"`
-	Description map[string]string `json:"description"`
-	Categories  []string          `json:"categories"`
-	Image       string            `json:"image"`
+	ID          string            `json:"id" validate:"required,id"`
+	Title       map[string]string `json:"title" validate:"required,localized"`
+	Description map[string]string `json:"description" validate:"locales"`
+	Categories  []string          `json:"categories" validate:"dive,oneof=frontend backend design api fullstack"`
+	Image       string            `json:"image" validate:"url"`
 	Tags        []string          `json:"tags"`
-	Url         string            `json:"url"`
-	GlowColor   string            `json:"glowColor"`
+	Url         string            `json:"url" validate:"url"`
+	GlowColor   string            `json:"glowColor" validate:"hexcolor"`
 	Featured    bool              `json:"featured"`
 	Order       int               `json:"order"`
+	Schedule
 }
 
 type ProjectLinks struct {
@@ -39,35 +40,37 @@ type ProjectLinks struct {
 }
 
 type Skill struct {
-	ID    string `json:"id"`
-	Name  string `json:"name"`
-	Icon  string `json:"icon"`
-	Color string `json:"color"`
+	ID    string `json:"id" validate:"required,id"`
+	Name  string `json:"name" validate:"required"`
+	Icon  string `json:"icon" validate:"required"`
+	Color string `json:"color" validate:"required,hexcolor"`
 }
 
 type SkillCategory struct {
-	ID         string            `json:"id"`
-	Title      map[string]string `json:"title"`
+	ID         string            `json:"id" validate:"required,id"`
+	Title      map[string]string `json:"title" validate:"required,localized"`
 	Skills     []Skill           `json:"skills"`
-	Badge      map[string]string `json:"badge,omitempty"`
+	Badge      map[string]string `json:"badge,omitempty" validate:"locales"`
 	IsLearning bool              `json:"isLearning,omitempty"`
 	Order      int               `json:"order"`
+	Schedule
 }
 
 type Contact struct {
-	ID    string            `json:"id"`
-	Type  string            `json:"type"`
-	Label map[string]string `json:"label"`
-	Value string            `json:"value"`
-	Link  string            `json:"link"`
-	Icon  string            `json:"icon"`
-	Color string            `json:"color"`
+	ID    string            `json:"id" validate:"required,id"`
+	Type  string            `json:"type" validate:"required"`
+	Label map[string]string `json:"label" validate:"required,localized"`
+	Value string            `json:"value" validate:"required"`
+	Link  string            `json:"link" validate:"required,url"`
+	Icon  string            `json:"icon" validate:"required"`
+	Color string            `json:"color" validate:"required,hexcolor"`
 	Order int               `json:"order"`
+	Schedule
 }
 
 type SiteContent struct {
-	About      AboutContent    `json:"about"`
-	Projects   []Project       `json:"projects"`
-	Skills     []SkillCategory `json:"skills"`
-	Contacts   []Contact       `json:"contacts"`
+	About    AboutContent    `json:"about"`
+	Projects []Project       `json:"projects"`
+	Skills   []SkillCategory `json:"skills"`
+	Contacts []Contact       `json:"contacts"`
 }
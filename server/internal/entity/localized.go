@@ -0,0 +1,192 @@
+package entity
+
+import "time"
+
+// Translate picks one string out of a localized map
+type Translate func(map[string]string) string
+
+// The Localized* types mirror the content entities with every localized
+// map replaced by a single string, for ?lang= responses
+
+type LocalizedAbout struct {
+	ID        int64           `json:"id"`
+	Name      string          `json:"name"`
+	Username  string          `json:"username"`
+	Title     string          `json:"title"`
+	Bio       string          `json:"bio"`
+	Photo     string          `json:"photo"`
+	Stats     []LocalizedStat `json:"stats"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+type LocalizedStat struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+	Icon  string `json:"icon"`
+	Color string `json:"color"`
+}
+
+type LocalizedProject struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Categories  []string `json:"categories"`
+	Image       string   `json:"image"`
+	Tags        []string `json:"tags"`
+	Url         string   `json:"url"`
+	GlowColor   string   `json:"glowColor"`
+	Featured    bool     `json:"featured"`
+	Order       int      `json:"order"`
+	Schedule
+}
+
+type LocalizedSkillCategory struct {
+	ID         string  `json:"id"`
+	Title      string  `json:"title"`
+	Skills     []Skill `json:"skills"`
+	Badge      string  `json:"badge,omitempty"`
+	IsLearning bool    `json:"isLearning,omitempty"`
+	Order      int     `json:"order"`
+	Schedule
+}
+
+type LocalizedContact struct {
+	ID    string `json:"id"`
+	Type  string `json:"type"`
+	Label string `json:"label"`
+	Value string `json:"value"`
+	Link  string `json:"link"`
+	Icon  string `json:"icon"`
+	Color string `json:"color"`
+	Order int    `json:"order"`
+	Schedule
+}
+
+type LocalizedSiteContent struct {
+	About    LocalizedAbout           `json:"about"`
+	Projects []LocalizedProject       `json:"projects"`
+	Skills   []LocalizedSkillCategory `json:"skills"`
+	Contacts []LocalizedContact       `json:"contacts"`
+}
+
+func (a AboutContent) Localize(t Translate) LocalizedAbout {
+	out := LocalizedAbout{
+		ID:        a.ID,
+		Name:      t(a.Name),
+		Username:  a.Username,
+		Title:     t(a.Title),
+		Bio:       t(a.Bio),
+		Photo:     a.Photo,
+		UpdatedAt: a.UpdatedAt,
+	}
+	for _, s := range a.Stats {
+		out.Stats = append(out.Stats, LocalizedStat{
+			Value: t(s.Value),
+			Label: t(s.Label),
+			Icon:  s.Icon,
+			Color: s.Color,
+		})
+	}
+	return out
+}
+
+func LocalizeProjects(projects []Project, t Translate) []LocalizedProject {
+	out := make([]LocalizedProject, 0, len(projects))
+	for _, p := range projects {
+		out = append(out, LocalizedProject{
+			ID:          p.ID,
+			Title:       t(p.Title),
+			Description: t(p.Description),
+			Categories:  p.Categories,
+			Image:       p.Image,
+			Tags:        p.Tags,
+			Url:         p.Url,
+			GlowColor:   p.GlowColor,
+			Featured:    p.Featured,
+			Order:       p.Order,
+			Schedule:    p.Schedule,
+		})
+	}
+	return out
+}
+
+func LocalizeSkills(categories []SkillCategory, t Translate) []LocalizedSkillCategory {
+	out := make([]LocalizedSkillCategory, 0, len(categories))
+	for _, c := range categories {
+		out = append(out, LocalizedSkillCategory{
+			ID:         c.ID,
+			Title:      t(c.Title),
+			Skills:     c.Skills,
+			Badge:      t(c.Badge),
+			IsLearning: c.IsLearning,
+			Order:      c.Order,
+			Schedule:   c.Schedule,
+		})
+	}
+	return out
+}
+
+func LocalizeContacts(contacts []Contact, t Translate) []LocalizedContact {
+	out := make([]LocalizedContact, 0, len(contacts))
+	for _, c := range contacts {
+		out = append(out, LocalizedContact{
+			ID:       c.ID,
+			Type:     c.Type,
+			Label:    t(c.Label),
+			Value:    c.Value,
+			Link:     c.Link,
+			Icon:     c.Icon,
+			Color:    c.Color,
+			Order:    c.Order,
+			Schedule: c.Schedule,
+		})
+	}
+	return out
+}
+
+func (c SiteContent) Localize(t Translate) LocalizedSiteContent {
+	return LocalizedSiteContent{
+		About:    c.About.Localize(t),
+		Projects: LocalizeProjects(c.Projects, t),
+		Skills:   LocalizeSkills(c.Skills, t),
+		Contacts: LocalizeContacts(c.Contacts, t),
+	}
+}
+
+// MapLocalized replaces every localized map of the content with f of it
+func (c *SiteContent) MapLocalized(f func(map[string]string) map[string]string) {
+	c.About.MapLocalized(f)
+	for i := range c.Projects {
+		c.Projects[i].MapLocalized(f)
+	}
+	for i := range c.Skills {
+		c.Skills[i].MapLocalized(f)
+	}
+	for i := range c.Contacts {
+		c.Contacts[i].MapLocalized(f)
+	}
+}
+
+func (a *AboutContent) MapLocalized(f func(map[string]string) map[string]string) {
+	a.Name = f(a.Name)
+	a.Title = f(a.Title)
+	a.Bio = f(a.Bio)
+	for i := range a.Stats {
+		a.Stats[i].Value = f(a.Stats[i].Value)
+		a.Stats[i].Label = f(a.Stats[i].Label)
+	}
+}
+
+func (p *Project) MapLocalized(f func(map[string]string) map[string]string) {
+	p.Title = f(p.Title)
+	p.Description = f(p.Description)
+}
+
+func (c *SkillCategory) MapLocalized(f func(map[string]string) map[string]string) {
+	c.Title = f(c.Title)
+	c.Badge = f(c.Badge)
+}
+
+func (c *Contact) MapLocalized(f func(map[string]string) map[string]string) {
+	c.Label = f(c.Label)
+}
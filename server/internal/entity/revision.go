@@ -0,0 +1,15 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type ContentRevision struct {
+	ID        int64           `json:"id"`
+	Section   string          `json:"section"`
+	Author    string          `json:"author"`
+	Summary   string          `json:"summary"`
+	Snapshot  json.RawMessage `json:"snapshot,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
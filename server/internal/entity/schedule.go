@@ -0,0 +1,73 @@
+package entity
+
+import "time"
+
+// Schedule limits when an item is shown on the public site. A nil bound
+// means no limit on that side; with both set, unpublishAt must be later or
+// the item would never show.
+type Schedule struct {
+	PublishAt   *time.Time `json:"publishAt,omitempty"`
+	UnpublishAt *time.Time `json:"unpublishAt,omitempty" validate:"after=PublishAt"`
+}
+
+// VisibleAt reports whether the item is published at t
+func (s Schedule) VisibleAt(t time.Time) bool {
+	if s.PublishAt != nil && s.PublishAt.After(t) {
+		return false
+	}
+	if s.UnpublishAt != nil && !s.UnpublishAt.After(t) {
+		return false
+	}
+	return true
+}
+
+// Visible returns a copy of the content with only the items published at t
+func (c SiteContent) Visible(t time.Time) SiteContent {
+	var projects []Project
+	for _, p := range c.Projects {
+		if p.VisibleAt(t) {
+			projects = append(projects, p)
+		}
+	}
+	var skills []SkillCategory
+	for _, s := range c.Skills {
+		if s.VisibleAt(t) {
+			skills = append(skills, s)
+		}
+	}
+	var contacts []Contact
+	for _, ct := range c.Contacts {
+		if ct.VisibleAt(t) {
+			contacts = append(contacts, ct)
+		}
+	}
+
+	c.Projects = projects
+	c.Skills = skills
+	c.Contacts = contacts
+	return c
+}
+
+// NextChange returns the earliest publish/unpublish time after t, or nil if
+// nothing is scheduled
+func (c SiteContent) NextChange(t time.Time) *time.Time {
+	var next *time.Time
+	consider := func(s Schedule) {
+		for _, at := range []*time.Time{s.PublishAt, s.UnpublishAt} {
+			if at != nil && at.After(t) && (next == nil || at.Before(*next)) {
+				v := *at
+				next = &v
+			}
+		}
+	}
+	for _, p := range c.Projects {
+		consider(p.Schedule)
+	}
+	for _, s := range c.Skills {
+		consider(s.Schedule)
+	}
+	for _, ct := range c.Contacts {
+		consider(ct.Schedule)
+	}
+	return next
+}
@@ -0,0 +1,70 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func at(hour int) *time.Time {
+	t := time.Date(2026, 1, 1, hour, 0, 0, 0, time.UTC)
+	return &t
+}
+
+func TestScheduleVisibleAt(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule Schedule
+		want     bool
+	}{
+		{"no schedule", Schedule{}, true},
+		{"published", Schedule{PublishAt: at(10)}, true},
+		{"published right now", Schedule{PublishAt: at(12)}, true},
+		{"not yet published", Schedule{PublishAt: at(13)}, false},
+		{"unpublished right now", Schedule{UnpublishAt: at(12)}, false},
+		{"unpublished later", Schedule{UnpublishAt: at(13)}, true},
+		{"inside the window", Schedule{PublishAt: at(10), UnpublishAt: at(14)}, true},
+		{"after the window", Schedule{PublishAt: at(8), UnpublishAt: at(10)}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.schedule.VisibleAt(*at(12)); got != tt.want {
+			t.Errorf("%s: VisibleAt() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSiteContentVisible(t *testing.T) {
+	content := SiteContent{
+		Projects: []Project{{ID: "live"}, {ID: "later", Schedule: Schedule{PublishAt: at(13)}}},
+		Skills:   []SkillCategory{{ID: "gone", Schedule: Schedule{UnpublishAt: at(11)}}},
+		Contacts: []Contact{{ID: "mail"}},
+	}
+
+	got := content.Visible(*at(12))
+	if len(got.Projects) != 1 || got.Projects[0].ID != "live" {
+		t.Errorf("projects = %+v", got.Projects)
+	}
+	if len(got.Skills) != 0 || len(got.Contacts) != 1 {
+		t.Errorf("skills = %+v, contacts = %+v", got.Skills, got.Contacts)
+	}
+	if len(content.Projects) != 2 {
+		t.Error("Visible() changed the original content")
+	}
+}
+
+func TestSiteContentNextChange(t *testing.T) {
+	content := SiteContent{
+		Projects: []Project{{ID: "a", Schedule: Schedule{PublishAt: at(10), UnpublishAt: at(16)}}},
+		Contacts: []Contact{{ID: "b", Schedule: Schedule{PublishAt: at(14)}}},
+	}
+
+	if got := content.NextChange(*at(12)); got == nil || !got.Equal(*at(14)) {
+		t.Errorf("NextChange(12:00) = %v, want 14:00", got)
+	}
+	// A change exactly at t is already in effect
+	if got := content.NextChange(*at(14)); got == nil || !got.Equal(*at(16)) {
+		t.Errorf("NextChange(14:00) = %v, want 16:00", got)
+	}
+	if got := content.NextChange(*at(16)); got != nil {
+		t.Errorf("NextChange(16:00) = %v, want nil", got)
+	}
+}
@@ -0,0 +1,82 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Content sections that can be edited from the admin panel
+const (
+	SectionAbout    = "about"
+	SectionProjects = "projects"
+	SectionSkills   = "skills"
+	SectionContacts = "contacts"
+)
+
+var Sections = []string{SectionAbout, SectionProjects, SectionSkills, SectionContacts}
+
+func IsSection(s string) bool {
+	for _, section := range Sections {
+		if s == section {
+			return true
+		}
+	}
+	return false
+}
+
+// Section returns the part of the content that belongs to section
+func (c *SiteContent) Section(section string) (interface{}, error) {
+	switch section {
+	case SectionAbout:
+		return c.About, nil
+	case SectionProjects:
+		return c.Projects, nil
+	case SectionSkills:
+		return c.Skills, nil
+	case SectionContacts:
+		return c.Contacts, nil
+	}
+	return nil, fmt.Errorf("unknown section %q", section)
+}
+
+// SetSection replaces one section with its JSON representation
+func (c *SiteContent) SetSection(section string, data []byte) error {
+	switch section {
+	case SectionAbout:
+		var about AboutContent
+		if err := json.Unmarshal(data, &about); err != nil {
+			return err
+		}
+		c.About = about
+	case SectionProjects:
+		var projects []Project
+		if err := json.Unmarshal(data, &projects); err != nil {
+			return err
+		}
+		c.Projects = projects
+	case SectionSkills:
+		var skills []SkillCategory
+		if err := json.Unmarshal(data, &skills); err != nil {
+			return err
+		}
+		c.Skills = skills
+	case SectionContacts:
+		var contacts []Contact
+		if err := json.Unmarshal(data, &contacts); err != nil {
+			return err
+		}
+		c.Contacts = contacts
+	default:
+		return fmt.Errorf("unknown section %q", section)
+	}
+	return nil
+}
+
+// ContentDraft is an unpublished version of one section
+type ContentDraft struct {
+	Section   string          `json:"section"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	Author    string          `json:"author"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
@@ -3,24 +3,65 @@ package handler
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
+	"sync/atomic"
 
 	"server/internal/entity"
 	"server/internal/repository"
 )
 
+// AnalyticsMetrics counts tracking events as they are stored or dropped
+type AnalyticsMetrics interface {
+	PageView(device string)
+	Session(device string)
+	BotFiltered()
+}
+
 type AnalyticsHandler struct {
-	repo *repository.PostgresRepository
+	repo    repository.AnalyticsStore
+	metrics AnalyticsMetrics
+	// bots are lower-case User-Agent substrings of crawlers
+	bots atomic.Pointer[[]string]
+}
+
+func NewAnalyticsHandler(repo repository.AnalyticsStore, botUserAgents []string, metrics AnalyticsMetrics) *AnalyticsHandler {
+	h := &AnalyticsHandler{repo: repo, metrics: metrics}
+	h.SetBotFilter(botUserAgents)
+	return h
+}
+
+// SetBotFilter replaces the User-Agent substrings whose events are dropped
+func (h *AnalyticsHandler) SetBotFilter(userAgents []string) {
+	bots := make([]string, 0, len(userAgents))
+	for _, ua := range userAgents {
+		if ua = strings.ToLower(strings.TrimSpace(ua)); ua != "" {
+			bots = append(bots, ua)
+		}
+	}
+	h.bots.Store(&bots)
 }
 
-func NewAnalyticsHandler(repo *repository.PostgresRepository) *AnalyticsHandler {
-	return &AnalyticsHandler{repo: repo}
+func (h *AnalyticsHandler) isBot(userAgent string) bool {
+	userAgent = strings.ToLower(userAgent)
+	for _, bot := range *h.bots.Load() {
+		if strings.Contains(userAgent, bot) {
+			return true
+		}
+	}
+	return false
 }
 
-// POST /api/analytics/track - track events
+// POST /api/analytics/track - track events. Events of known bots are
+// answered like any other but not stored.
 func (h *AnalyticsHandler) Track(w http.ResponseWriter, r *http.Request) {
 	var req entity.TrackEventRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		respondProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if h.isBot(r.UserAgent()) {
+		h.metrics.BotFiltered()
+		respondJSON(w, http.StatusOK, map[string]bool{"success": true})
 		return
 	}
 
@@ -29,16 +70,18 @@ func (h *AnalyticsHandler) Track(w http.ResponseWriter, r *http.Request) {
 	switch req.Event {
 	case "page_view":
 		if err := h.repo.TrackPageView(ctx, req.Page, req.VisitorID, req.Device); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			respondError(w, r, err)
 			return
 		}
+		h.metrics.PageView(req.Device)
 	case "session":
 		if err := h.repo.TrackSession(ctx, req.VisitorID, req.Duration, req.Pages, req.Theme, req.Language); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			respondError(w, r, err)
 			return
 		}
+		h.metrics.Session(req.Device)
 	default:
-		http.Error(w, "Unknown event type", http.StatusBadRequest)
+		respondProblem(w, r, http.StatusBadRequest, "Unknown event type")
 		return
 	}
 
@@ -51,7 +94,7 @@ func (h *AnalyticsHandler) Track(w http.ResponseWriter, r *http.Request) {
 func (h *AnalyticsHandler) GetAnalytics(w http.ResponseWriter, r *http.Request) {
 	data, err := h.repo.GetAnalytics(r.Context())
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		respondError(w, r, err)
 		return
 	}
 
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"server/internal/auth"
+	"server/internal/entity"
+	"server/internal/validate"
+)
+
+type createKeyRequest struct {
+	Name      string     `json:"name" validate:"required"`
+	Scopes    []string   `json:"scopes" validate:"required"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// createKeyResponse is the only time the key itself is shown
+type createKeyResponse struct {
+	entity.APIKey
+	Key string `json:"key"`
+}
+
+// GET /api/auth/keys
+func (h *AuthHandler) ListKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.users.ListAPIKeys(r.Context())
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, keys)
+}
+
+// POST /api/auth/keys
+func (h *AuthHandler) CreateKey(w http.ResponseWriter, r *http.Request) {
+	var req createKeyRequest
+	if !h.decodeValid(w, r, &req) {
+		return
+	}
+	var errs validate.Errors
+	for i, scope := range req.Scopes {
+		if !contains(auth.Scopes(), scope) {
+			errs = append(errs, validate.FieldError{Pointer: "/scopes/" + strconv.Itoa(i), Detail: "unknown scope"})
+		}
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		errs = append(errs, validate.FieldError{Pointer: "/expires_at", Detail: "must be in the future"})
+	}
+	if len(errs) > 0 {
+		respondInvalid(w, r, errs)
+		return
+	}
+
+	id, token, hash := auth.NewAPIKey()
+	key := entity.APIKey{
+		ID:         id,
+		Name:       req.Name,
+		Scopes:     req.Scopes,
+		SecretHash: hash,
+		ExpiresAt:  req.ExpiresAt,
+	}
+	if p, _ := principalFromContext(r.Context()); p.UserID != 0 {
+		key.CreatedBy = &p.UserID
+	}
+	key, err := h.users.CreateAPIKey(r.Context(), key)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	respondJSON(w, http.StatusCreated, createKeyResponse{APIKey: key, Key: token})
+}
+
+// DELETE /api/auth/keys/{id} - revoke a key
+func (h *AuthHandler) RevokeKey(w http.ResponseWriter, r *http.Request) {
+	if err := h.users.RevokeAPIKey(r.Context(), chi.URLParam(r, "id")); err != nil {
+		respondError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
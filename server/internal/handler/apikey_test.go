@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"server/internal/auth"
+	"server/internal/entity"
+)
+
+func TestAPIKeys(t *testing.T) {
+	h, _ := newTestUserRouter(t)
+	owner := []string{"Authorization", "Bearer " + loginAs(t, h, testUsername)}
+
+	rec := serve(t, h, http.MethodPost, "/auth/keys", `{"name":"ci","scopes":["content:projects:write"]}`, owner...)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create key = %d: %s", rec.Code, rec.Body)
+	}
+	if got := rec.Header().Get("Cache-Control"); got != "no-store" {
+		t.Errorf("Cache-Control = %q, want no-store", got)
+	}
+	var created createKeyResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	if !auth.IsAPIKey(created.Key) || created.CreatedBy == nil {
+		t.Fatalf("created key = %+v", created)
+	}
+	key := []string{"Authorization", "Bearer " + created.Key}
+
+	tests := []struct {
+		method string
+		path   string
+		want   int
+	}{
+		{http.MethodPut, "/content/projects", http.StatusNoContent},
+		{http.MethodPut, "/content/about", http.StatusForbidden},
+		{http.MethodPost, "/content/publish", http.StatusForbidden},
+		{http.MethodGet, "/analytics", http.StatusForbidden},
+		{http.MethodGet, "/auth/keys", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		if rec := serve(t, h, tt.method, tt.path, "", key...); rec.Code != tt.want {
+			t.Errorf("%s %s with the key = %d, want %d", tt.method, tt.path, rec.Code, tt.want)
+		}
+	}
+
+	// The list shows when the key was used, never the key or its hash
+	rec = serve(t, h, http.MethodGet, "/auth/keys", "", owner...)
+	if strings.Contains(rec.Body.String(), strings.TrimPrefix(created.Key, "pk_"+created.ID+"_")) {
+		t.Error("key list contains the secret")
+	}
+	var keys []entity.APIKey
+	if err := json.Unmarshal(rec.Body.Bytes(), &keys); err != nil || len(keys) != 1 {
+		t.Fatalf("keys = %s, %v", rec.Body, err)
+	}
+	if keys[0].LastUsedAt == nil {
+		t.Error("last_used_at not recorded")
+	}
+
+	wrong := "Bearer pk_" + created.ID + "_wrong"
+	if rec := serve(t, h, http.MethodPut, "/content/projects", "", "Authorization", wrong); rec.Code != http.StatusUnauthorized {
+		t.Errorf("wrong secret = %d, want 401", rec.Code)
+	}
+
+	if rec := serve(t, h, http.MethodDelete, "/auth/keys/"+created.ID, "", owner...); rec.Code != http.StatusNoContent {
+		t.Fatalf("revoke = %d: %s", rec.Code, rec.Body)
+	}
+	if rec := serve(t, h, http.MethodPut, "/content/projects", "", key...); rec.Code != http.StatusUnauthorized {
+		t.Errorf("revoked key = %d, want 401", rec.Code)
+	}
+}
+
+func TestAPIKeyExpires(t *testing.T) {
+	h, repo := newTestUserRouter(t)
+	id, token, hash := auth.NewAPIKey()
+	expired := time.Now().Add(-time.Minute)
+	if _, err := repo.CreateAPIKey(context.Background(), entity.APIKey{
+		ID: id, Name: "old", Scopes: []string{"analytics:read"}, SecretHash: hash, ExpiresAt: &expired,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if rec := serve(t, h, http.MethodGet, "/analytics", "", "Authorization", "Bearer "+token); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expired key = %d, want 401", rec.Code)
+	}
+}
+
+func TestCreateKeyValidates(t *testing.T) {
+	h, _ := newTestUserRouter(t)
+	owner := []string{"Authorization", "Bearer " + loginAs(t, h, testUsername)}
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{name: "unknown scope", body: `{"name":"ci","scopes":["analytics:read","users:manage"]}`, want: "/scopes/1"},
+		{name: "past expiry", body: `{"name":"ci","scopes":["analytics:read"],"expires_at":"2020-01-01T00:00:00Z"}`, want: "/expires_at"},
+		{name: "no scopes", body: `{"name":"ci"}`, want: "/scopes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(t, h, http.MethodPost, "/auth/keys", tt.body, owner...)
+			if rec.Code != http.StatusUnprocessableEntity || !strings.Contains(rec.Body.String(), `"pointer":"`+tt.want+`"`) {
+				t.Errorf("create = %d %s, want 422 at %s", rec.Code, rec.Body, tt.want)
+			}
+		})
+	}
+}
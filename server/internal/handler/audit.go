@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"server/internal/entity"
+	"server/internal/logging"
+	"server/internal/repository"
+)
+
+const (
+	defaultAuditLimit = 50
+	maxAuditLimit     = 500
+	maxUserAgent      = 512
+)
+
+// auditRecord collects what a request did while it runs. Handlers further
+// down fill it in; Record writes it once the response is sent.
+type auditRecord struct {
+	actor      string
+	entityIDs  []string
+	beforeHash string
+	afterHash  string
+}
+
+// auditFromContext returns the record of the request. Without the Record
+// middleware it is nil and every setter does nothing.
+func auditFromContext(ctx context.Context) *auditRecord {
+	rec, _ := ctx.Value(auditKey).(*auditRecord)
+	return rec
+}
+
+func (a *auditRecord) setActor(actor string) {
+	if a != nil {
+		a.actor = actor
+	}
+}
+
+func (a *auditRecord) addEntity(ids ...string) {
+	if a != nil {
+		a.entityIDs = append(a.entityIDs, ids...)
+	}
+}
+
+// setChange remembers the section snapshots around a content change
+func (a *auditRecord) setChange(before, after []byte) {
+	if a != nil {
+		a.beforeHash, a.afterHash = snapshotHash(before), snapshotHash(after)
+	}
+}
+
+func snapshotHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+type AuditHandler struct {
+	store repository.AuditStore
+}
+
+func NewAuditHandler(store repository.AuditStore) *AuditHandler {
+	return &AuditHandler{store: store}
+}
+
+// Record writes an audit entry for every request that passes through it.
+// The action is the method and route pattern, so entries of one endpoint
+// can be filtered together; a failed request is recorded with its status.
+func (h *AuditHandler) Record(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &auditRecord{actor: "anonymous"}
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r.WithContext(context.WithValue(r.Context(), auditKey, rec)))
+
+		action := r.Method + " " + r.URL.Path
+		var ids []string
+		if rctx := chi.RouteContext(r.Context()); rctx != nil {
+			action = r.Method + " " + rctx.RoutePattern()
+			for i, key := range rctx.URLParams.Keys {
+				if key == "id" || key == "skill" {
+					ids = append(ids, rctx.URLParams.Values[i])
+				}
+			}
+		}
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		userAgent := r.UserAgent()
+		if len(userAgent) > maxUserAgent {
+			userAgent = userAgent[:maxUserAgent]
+		}
+
+		entry := entity.AuditEntry{
+			Actor:      rec.actor,
+			Action:     action,
+			Section:    requestSection(r),
+			EntityIDs:  append(ids, rec.entityIDs...),
+			BeforeHash: rec.beforeHash,
+			AfterHash:  rec.afterHash,
+			Status:     status,
+			IP:         ClientIP(r),
+			UserAgent:  userAgent,
+			RequestID:  middleware.GetReqID(r.Context()),
+		}
+		// The response is already sent, a lost entry is only logged
+		if err := h.store.CreateAuditEntry(context.WithoutCancel(r.Context()), entry); err != nil {
+			logging.FromContext(r.Context()).Error("Failed to write audit entry", "err", err)
+		}
+	})
+}
+
+// GET /api/admin/audit?actor=&action=&section=&status=&since=&until=&before=&limit=
+// Entries are newest first; the Link header points to the next page.
+func (h *AuditHandler) List(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := entity.AuditFilter{
+		Actor:   q.Get("actor"),
+		Action:  q.Get("action"),
+		Section: q.Get("section"),
+		Limit:   defaultAuditLimit,
+	}
+
+	if v := q.Get("before"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 1 {
+			respondProblem(w, r, http.StatusBadRequest, "Invalid before")
+			return
+		}
+		filter.BeforeID = n
+	}
+	if v := q.Get("status"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 100 || n > 599 {
+			respondProblem(w, r, http.StatusBadRequest, "Invalid status")
+			return
+		}
+		filter.Status = n
+	}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			respondProblem(w, r, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+		filter.Limit = min(n, maxAuditLimit)
+	}
+	for param, dst := range map[string]**time.Time{"since": &filter.Since, "until": &filter.Until} {
+		if v := q.Get(param); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				respondProblem(w, r, http.StatusBadRequest, "Invalid "+param+", expected RFC 3339")
+				return
+			}
+			*dst = &t
+		}
+	}
+
+	entries, err := h.store.ListAuditEntries(r.Context(), filter)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	if len(entries) == filter.Limit {
+		next := r.URL.Query()
+		next.Set("before", strconv.FormatInt(entries[len(entries)-1].ID, 10))
+		w.Header().Set("Link", "<"+r.URL.Path+"?"+next.Encode()+`>; rel="next"`)
+	}
+	respondJSON(w, http.StatusOK, entries)
+}
+
+// RunRetention removes entries older than retention every hour until ctx
+// is cancelled
+func (h *AuditHandler) RunRetention(ctx context.Context, retention time.Duration) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		removed, err := h.store.PruneAuditLog(ctx, time.Now().Add(-retention))
+		if err != nil {
+			slog.Error("Failed to prune the audit log", "err", err)
+		} else if removed > 0 {
+			slog.Info("Pruned the audit log", "removed", removed, "retention", retention)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"server/internal/entity"
+	"server/internal/locale"
+	"server/internal/repository"
+	"server/internal/validate"
+)
+
+// newTestAuditRouter records login and one protected route like cmd/api
+func newTestAuditRouter(t *testing.T) (http.Handler, *repository.MemoryRepository) {
+	t.Helper()
+	repo := repository.NewMemoryRepository(locale.Default)
+	if _, err := EnsureAdmin(context.Background(), repo, testUsername, testPassword); err != nil {
+		t.Fatal(err)
+	}
+	authHandler := NewAuthHandler(repo, []byte("test-secret"), validate.New(locale.Default), AuthConfig{AccessTTL: time.Minute, RefreshTTL: time.Hour})
+	audit := NewAuditHandler(repo)
+
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	r.Group(func(r chi.Router) {
+		r.Use(audit.Record)
+		r.Post("/api/auth/login", authHandler.Login)
+	})
+	r.Group(func(r chi.Router) {
+		r.Use(audit.Record)
+		r.Use(authHandler.Middleware)
+		r.Delete("/api/content/{section}/{id}", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})
+		r.Get("/api/admin/audit", audit.List)
+	})
+	return r, repo
+}
+
+func TestAuditRecord(t *testing.T) {
+	h, repo := newTestAuditRouter(t)
+
+	serve(t, h, http.MethodPost, "/api/auth/login", `{"username":"admin","password":"wrong"}`, "User-Agent", "test-agent")
+	rec := serve(t, h, http.MethodPost, "/api/auth/login", `{"username":"admin","password":"correct horse battery"}`)
+	tokens := decodeTokens(t, rec.Body.Bytes())
+	serve(t, h, http.MethodDelete, "/api/content/projects/portfolio", "", "Authorization", "Bearer "+tokens.AccessToken)
+
+	entries, err := repo.ListAuditEntries(context.Background(), entity.AuditFilter{Limit: 10})
+	if err != nil || len(entries) != 3 {
+		t.Fatalf("entries = %+v, %v", entries, err)
+	}
+	failed, deleted := entries[2], entries[0]
+	if failed.Actor != "admin" || failed.Action != "POST /api/auth/login" || failed.Status != http.StatusUnauthorized || failed.UserAgent != "test-agent" {
+		t.Errorf("failed login entry = %+v", failed)
+	}
+	if deleted.Actor != "admin" || deleted.Action != "DELETE /api/content/{section}/{id}" || deleted.Section != "projects" ||
+		!reflect.DeepEqual(deleted.EntityIDs, []string{"portfolio"}) || deleted.Status != http.StatusNoContent {
+		t.Errorf("delete entry = %+v", deleted)
+	}
+	if deleted.RequestID == "" || deleted.IP == "" {
+		t.Errorf("delete entry misses request id or IP: %+v", deleted)
+	}
+}
+
+func TestAuditList(t *testing.T) {
+	h, repo := newTestAuditRouter(t)
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		repo.CreateAuditEntry(ctx, entity.AuditEntry{Actor: "editor", Action: "PUT /api/content/about", Section: "about", Status: 200})
+	}
+	repo.CreateAuditEntry(ctx, entity.AuditEntry{Actor: "analyst", Action: "GET /api/analytics", Status: 200})
+
+	rec := serve(t, h, http.MethodPost, "/api/auth/login", `{"username":"admin","password":"correct horse battery"}`)
+	tokens := decodeTokens(t, rec.Body.Bytes())
+	bearer := []string{"Authorization", "Bearer " + tokens.AccessToken}
+	list := func(query string) ([]entity.AuditEntry, *http.Response) {
+		t.Helper()
+		rec := serve(t, h, http.MethodGet, "/api/admin/audit"+query, "", bearer...)
+		var entries []entity.AuditEntry
+		if rec.Code == http.StatusOK {
+			if err := json.Unmarshal(rec.Body.Bytes(), &entries); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return entries, rec.Result()
+	}
+
+	page, resp := list("?actor=editor&limit=2")
+	if len(page) != 2 || page[0].ID != 3 || page[1].ID != 2 {
+		t.Fatalf("first page = %+v", page)
+	}
+	link := resp.Header.Get("Link")
+	if !strings.Contains(link, "before=2") || !strings.Contains(link, "actor=editor") || !strings.HasSuffix(link, `rel="next"`) {
+		t.Errorf("Link = %q", link)
+	}
+	page, resp = list("?actor=editor&limit=2&before=2")
+	if len(page) != 1 || page[0].ID != 1 || resp.Header.Get("Link") != "" {
+		t.Errorf("last page = %+v, Link %q", page, resp.Header.Get("Link"))
+	}
+
+	if page, _ := list("?section=about&status=200"); len(page) != 3 {
+		t.Errorf("section and status filter = %d entries, want 3", len(page))
+	}
+	for _, query := range []string{"?before=x", "?status=42", "?limit=0", "?since=yesterday"} {
+		if _, resp := list(query); resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("GET %s = %d, want 400", query, resp.StatusCode)
+		}
+	}
+}
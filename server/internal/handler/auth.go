@@ -0,0 +1,476 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"server/internal/auth"
+	"server/internal/entity"
+	"server/internal/logging"
+	"server/internal/ratelimit"
+	"server/internal/repository"
+	"server/internal/validate"
+)
+
+// errUnauthorized is any failed authentication; the response does not say
+// which part was wrong
+var errUnauthorized = errors.New("unauthorized")
+
+// AuthConfig sets token lifetimes. LegacyPassword, when not empty, is still
+// accepted in X-Admin-Password while clients move to tokens. TOTPIssuer is
+// the name authenticator apps show next to the code. Lockout, when set,
+// blocks password guessing per username and client address.
+type AuthConfig struct {
+	AccessTTL      time.Duration
+	RefreshTTL     time.Duration
+	LegacyPassword string
+	TOTPIssuer     string
+	Lockout        *ratelimit.Lockout
+}
+
+type AuthHandler struct {
+	users     repository.AuthStore
+	signer    *auth.Signer
+	validator *validate.Validator
+	config    AuthConfig
+
+	// usersMu serializes account changes, so two requests cannot remove
+	// the last two owners at once
+	usersMu sync.Mutex
+}
+
+func NewAuthHandler(users repository.AuthStore, secret []byte, validator *validate.Validator, config AuthConfig) *AuthHandler {
+	return &AuthHandler{users: users, signer: auth.NewSigner(secret, config.AccessTTL), validator: validator, config: config}
+}
+
+type contextKey int
+
+const (
+	actorKey contextKey = iota
+	auditKey
+	logKey
+)
+
+// principal is who is making an authenticated request: an admin session,
+// an API key (KeyID and Scopes set) or the legacy password (neither a
+// session nor a key)
+type principal struct {
+	UserID    int64
+	Username  string
+	Role      entity.Role
+	SessionID string
+	KeyID     string
+	Scopes    []string
+}
+
+// allows checks perm against the scopes of a key or the role of an account
+func (p principal) allows(perm auth.Permission, section string) bool {
+	if p.KeyID != "" {
+		return auth.ScopesAllow(p.Scopes, perm, section)
+	}
+	return auth.Can(p.Role, perm)
+}
+
+func principalFromContext(ctx context.Context) (principal, bool) {
+	p, ok := ctx.Value(actorKey).(principal)
+	return p, ok
+}
+
+// actorFromContext returns who is making an authenticated request
+func actorFromContext(ctx context.Context) string {
+	if p, ok := principalFromContext(ctx); ok {
+		return p.Username
+	}
+	return "anonymous"
+}
+
+// Middleware lets through requests with a valid access token of an active
+// session, or the legacy password if it is enabled. Wrong legacy passwords
+// count towards the lockout of the client address.
+func (h *AuthHandler) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		legacyKey := ""
+		if r.Header.Get("X-Admin-Password") != "" && h.config.LegacyPassword != "" &&
+			!strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+			legacyKey = "legacy:" + ClientIP(r)
+			if h.lockedOut(w, r, legacyKey) {
+				return
+			}
+		}
+
+		p, err := h.authenticate(r)
+		if errors.Is(err, errUnauthorized) {
+			if legacyKey != "" {
+				h.recordFailure(r, legacyKey)
+			}
+			w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+			respondProblem(w, r, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		if legacyKey != "" {
+			h.resetLockout(r, legacyKey)
+		}
+		setActor(r.Context(), p.Username)
+
+		ctx := context.WithValue(r.Context(), actorKey, p)
+		ctx = logging.WithContext(ctx, logging.FromContext(ctx).With("actor", p.Username))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Require lets through authenticated requests whose role or key scopes
+// grant perm. It goes after Middleware, inside a route group, so the
+// section of the route is known.
+func (h *AuthHandler) Require(perm auth.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p, _ := principalFromContext(r.Context())
+			if !p.allows(perm, requestSection(r)) {
+				respondProblem(w, r, http.StatusForbidden, "Not allowed: "+string(perm))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func (h *AuthHandler) authenticate(r *http.Request) (principal, error) {
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		if auth.IsAPIKey(token) {
+			return h.authenticateKey(r, token)
+		}
+		now := time.Now()
+		claims, err := h.signer.Verify(token, now)
+		if err != nil {
+			return principal{}, errUnauthorized
+		}
+		session, err := h.users.GetAuthSession(r.Context(), claims.SessionID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return principal{}, errUnauthorized
+		}
+		if err != nil {
+			return principal{}, err
+		}
+		if !session.Active(now) {
+			return principal{}, errUnauthorized
+		}
+		return principal{UserID: claims.UserID, Username: claims.Username, Role: claims.Role, SessionID: claims.SessionID}, nil
+	}
+
+	if password := r.Header.Get("X-Admin-Password"); password != "" && h.config.LegacyPassword != "" {
+		// Hashing first makes the comparison constant-time regardless of
+		// the lengths
+		given, want := sha256.Sum256([]byte(password)), sha256.Sum256([]byte(h.config.LegacyPassword))
+		if subtle.ConstantTimeCompare(given[:], want[:]) == 1 {
+			return principal{Username: "admin", Role: entity.RoleOwner}, nil
+		}
+	}
+	return principal{}, errUnauthorized
+}
+
+// authenticateKey checks an API key and records its use, at most once a
+// minute so busy pipelines do not write on every request
+func (h *AuthHandler) authenticateKey(r *http.Request, token string) (principal, error) {
+	id, hash, ok := auth.ParseAPIKey(token)
+	if !ok {
+		return principal{}, errUnauthorized
+	}
+	key, err := h.users.GetAPIKey(r.Context(), id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return principal{}, errUnauthorized
+	}
+	if err != nil {
+		return principal{}, err
+	}
+	now := time.Now()
+	if !auth.EqualHash(key.SecretHash, hash) || !key.Active(now) {
+		return principal{}, errUnauthorized
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > time.Minute {
+		if err := h.users.TouchAPIKey(r.Context(), key.ID, now); err != nil {
+			return principal{}, err
+		}
+	}
+	return principal{Username: "key:" + key.Name, KeyID: key.ID, Scopes: key.Scopes}, nil
+}
+
+// requestSection is the content section a route is about, or "" if it is
+// not about a single one
+func requestSection(r *http.Request) string {
+	section := chi.URLParam(r, "section")
+	if section == "" {
+		// PUT /api/content/about etc. are static routes
+		section = path.Base(r.URL.Path)
+	}
+	if entity.IsSection(section) {
+		return section
+	}
+	return ""
+}
+
+// loginRequest carries OTP, a TOTP or recovery code, for accounts with
+// two-factor authentication
+type loginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	OTP      string `json:"otp"`
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type tokenResponse struct {
+	AccessToken      string    `json:"access_token"`
+	TokenType        string    `json:"token_type"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// POST /api/auth/login - exchange username and password for tokens
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var req loginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	setActor(r.Context(), req.Username)
+
+	// Failures are counted per username and address, so guessing is slowed
+	// down without letting anyone lock the owner out from elsewhere
+	lockKey := "login:" + strings.ToLower(req.Username) + "|" + ClientIP(r)
+	if h.lockedOut(w, r, lockKey) {
+		return
+	}
+
+	user, err := h.users.GetAdminUserByName(r.Context(), req.Username)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		respondError(w, r, err)
+		return
+	}
+	// An unknown user has an empty hash, which costs the same to check
+	if !auth.CheckPassword(user.PasswordHash, req.Password) {
+		h.recordFailure(r, lockKey)
+		respondProblem(w, r, http.StatusUnauthorized, "Invalid username or password")
+		return
+	}
+	if user.TOTPEnabled {
+		if req.OTP == "" {
+			respondProblemType(w, r, problemOTPRequired, http.StatusUnauthorized, "A one-time code is required")
+			return
+		}
+		err := h.checkOTP(r.Context(), user, req.OTP)
+		if errors.Is(err, errInvalidOTP) {
+			h.recordFailure(r, lockKey)
+			respondProblem(w, r, http.StatusUnauthorized, "Invalid one-time code")
+			return
+		}
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+	}
+	h.resetLockout(r, lockKey)
+
+	now := time.Now()
+	session := entity.AuthSession{
+		ID:        auth.NewSessionID(),
+		UserID:    user.ID,
+		ExpiresAt: now.Add(h.config.RefreshTTL),
+	}
+	var refreshToken string
+	refreshToken, session.RefreshHash = auth.NewRefreshToken(session.ID)
+	if err := h.users.CreateAuthSession(r.Context(), session); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	h.respondTokens(w, user, session, refreshToken, now)
+}
+
+// lockedOut answers 429 if key is locked after too many failed attempts
+func (h *AuthHandler) lockedOut(w http.ResponseWriter, r *http.Request, key string) bool {
+	if h.config.Lockout == nil {
+		return false
+	}
+	wait, err := h.config.Lockout.Locked(r.Context(), key, time.Now())
+	if err != nil {
+		respondError(w, r, err)
+		return true
+	}
+	if wait > 0 {
+		respondTooManyRequests(w, r, wait, "Too many failed attempts, try again later")
+		return true
+	}
+	return false
+}
+
+// recordFailure counts a failed attempt of key. The attempt is answered
+// either way, so a store error is only logged.
+func (h *AuthHandler) recordFailure(r *http.Request, key string) {
+	if h.config.Lockout == nil {
+		return
+	}
+	wait, err := h.config.Lockout.Fail(r.Context(), key, time.Now())
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Lockout store failed", "err", err)
+		return
+	}
+	if wait > 0 {
+		logging.FromContext(r.Context()).Warn("Locked out", "key", key, "wait", wait)
+	}
+}
+
+func (h *AuthHandler) resetLockout(r *http.Request, key string) {
+	if h.config.Lockout == nil {
+		return
+	}
+	if err := h.config.Lockout.Reset(r.Context(), key); err != nil {
+		logging.FromContext(r.Context()).Error("Lockout store failed", "err", err)
+	}
+}
+
+// POST /api/auth/refresh - exchange a refresh token for a new pair. Every
+// refresh token works once; presenting a used one again revokes the session,
+// since it means the token leaked.
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req refreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	ctx := r.Context()
+	now := time.Now()
+
+	sessionID, hash, ok := auth.ParseRefreshToken(req.RefreshToken)
+	if !ok {
+		respondProblem(w, r, http.StatusUnauthorized, "Invalid refresh token")
+		return
+	}
+	session, err := h.users.GetAuthSession(ctx, sessionID)
+	if errors.Is(err, repository.ErrNotFound) || err == nil && !session.Active(now) {
+		respondProblem(w, r, http.StatusUnauthorized, "Invalid refresh token")
+		return
+	}
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	if !auth.EqualHash(session.RefreshHash, hash) {
+		if err := h.users.RevokeAuthSession(ctx, session.ID); err != nil {
+			respondError(w, r, err)
+			return
+		}
+		respondProblem(w, r, http.StatusUnauthorized, "Invalid refresh token")
+		return
+	}
+
+	refreshToken, newHash := auth.NewRefreshToken(session.ID)
+	session.ExpiresAt = now.Add(h.config.RefreshTTL)
+	err = h.users.RotateAuthSession(ctx, session.ID, hash, newHash, session.ExpiresAt)
+	if errors.Is(err, repository.ErrNotFound) {
+		// A concurrent refresh with the same token won
+		respondProblem(w, r, http.StatusUnauthorized, "Invalid refresh token")
+		return
+	}
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	user, err := h.users.GetAdminUser(ctx, session.UserID)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	setActor(ctx, user.Username)
+	h.respondTokens(w, user, session, refreshToken, now)
+}
+
+// POST /api/auth/logout - revoke the current session, or with ?all=true
+// every session of the user
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	p, _ := principalFromContext(r.Context())
+	if p.SessionID == "" {
+		respondProblem(w, r, http.StatusBadRequest, "Only admin sessions can log out")
+		return
+	}
+
+	var err error
+	if r.URL.Query().Get("all") == "true" {
+		err = h.users.RevokeAdminUserSessions(r.Context(), p.UserID)
+	} else {
+		err = h.users.RevokeAuthSession(r.Context(), p.SessionID)
+	}
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// meResponse lists the permissions of an account, or the scopes of a key
+type meResponse struct {
+	ID          int64             `json:"id"`
+	Username    string            `json:"username"`
+	Role        entity.Role       `json:"role,omitempty"`
+	Permissions []auth.Permission `json:"permissions,omitempty"`
+	Scopes      []string          `json:"scopes,omitempty"`
+}
+
+// GET /api/auth/me - the current account and what it may do
+func (h *AuthHandler) Me(w http.ResponseWriter, r *http.Request) {
+	p, _ := principalFromContext(r.Context())
+	respondJSON(w, http.StatusOK, meResponse{
+		ID:          p.UserID,
+		Username:    p.Username,
+		Role:        p.Role,
+		Permissions: auth.Permissions(p.Role),
+		Scopes:      p.Scopes,
+	})
+}
+
+func (h *AuthHandler) respondTokens(w http.ResponseWriter, user entity.AdminUser, session entity.AuthSession, refreshToken string, now time.Time) {
+	accessToken, expires := h.signer.Issue(session.ID, user, now)
+	w.Header().Set("Cache-Control", "no-store")
+	respondJSON(w, http.StatusOK, tokenResponse{
+		AccessToken:      accessToken,
+		TokenType:        "Bearer",
+		ExpiresAt:        expires,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: session.ExpiresAt,
+	})
+}
+
+// EnsureAdmin creates the first admin account, an owner, when there is none
+// yet, so a fresh install can log in with the configured credentials
+func EnsureAdmin(ctx context.Context, users repository.AuthStore, username, password string) (bool, error) {
+	n, err := users.CountAdminUsers(ctx)
+	if err != nil || n > 0 || password == "" {
+		return false, err
+	}
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		return false, err
+	}
+	_, err = users.CreateAdminUser(ctx, entity.AdminUser{Username: username, Role: entity.RoleOwner, PasswordHash: hash})
+	return err == nil, err
+}
@@ -0,0 +1,285 @@
+package handler
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"server/internal/locale"
+	"server/internal/repository"
+	"server/internal/validate"
+)
+
+const (
+	testUsername = "admin"
+	testPassword = "correct horse battery"
+)
+
+// newTestAuthRouter serves the auth routes over a memory store holding one
+// owner account
+func newTestAuthRouter(t *testing.T, config AuthConfig) (http.Handler, *repository.MemoryRepository) {
+	t.Helper()
+	repo := repository.NewMemoryRepository(locale.Default)
+	if _, err := EnsureAdmin(context.Background(), repo, testUsername, testPassword); err != nil {
+		t.Fatal(err)
+	}
+	if config.AccessTTL == 0 {
+		config.AccessTTL = time.Minute
+	}
+	if config.RefreshTTL == 0 {
+		config.RefreshTTL = time.Hour
+	}
+	h := NewAuthHandler(repo, []byte("test-secret"), validate.New(locale.Default), config)
+
+	r := chi.NewRouter()
+	r.Post("/auth/login", h.Login)
+	r.Post("/auth/refresh", h.Refresh)
+	r.Group(func(r chi.Router) {
+		r.Use(h.Middleware)
+		r.Get("/auth/me", h.Me)
+		r.Post("/auth/logout", h.Logout)
+		r.Post("/auth/totp/setup", h.SetupTOTP)
+		r.Post("/auth/totp/enable", h.EnableTOTP)
+	})
+	return r, repo
+}
+
+func decodeTokens(t *testing.T, body []byte) tokenResponse {
+	t.Helper()
+	var tokens tokenResponse
+	if err := json.Unmarshal(body, &tokens); err != nil {
+		t.Fatal(err)
+	}
+	if tokens.AccessToken == "" || tokens.RefreshToken == "" {
+		t.Fatalf("missing tokens in %s", body)
+	}
+	return tokens
+}
+
+func login(t *testing.T, h http.Handler, body string) tokenResponse {
+	t.Helper()
+	rec := serve(t, h, http.MethodPost, "/auth/login", body)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("login = %d: %s", rec.Code, rec.Body)
+	}
+	if got := rec.Header().Get("Cache-Control"); got != "no-store" {
+		t.Errorf("Cache-Control = %q, want no-store", got)
+	}
+	return decodeTokens(t, rec.Body.Bytes())
+}
+
+func refresh(t *testing.T, h http.Handler, token string) *http.Response {
+	t.Helper()
+	body, _ := json.Marshal(refreshRequest{RefreshToken: token})
+	return serve(t, h, http.MethodPost, "/auth/refresh", string(body)).Result()
+}
+
+func me(t *testing.T, h http.Handler, accessToken string) int {
+	t.Helper()
+	return serve(t, h, http.MethodGet, "/auth/me", "", "Authorization", "Bearer "+accessToken).Code
+}
+
+func TestLogin(t *testing.T) {
+	h, _ := newTestAuthRouter(t, AuthConfig{})
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{name: "valid", body: `{"username":"admin","password":"correct horse battery"}`, want: http.StatusOK},
+		{name: "wrong password", body: `{"username":"admin","password":"wrong"}`, want: http.StatusUnauthorized},
+		{name: "unknown user", body: `{"username":"nobody","password":"correct horse battery"}`, want: http.StatusUnauthorized},
+		{name: "empty password", body: `{"username":"admin","password":""}`, want: http.StatusUnauthorized},
+		{name: "not json", body: `username=admin`, want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := serve(t, h, http.MethodPost, "/auth/login", tt.body); rec.Code != tt.want {
+				t.Errorf("login = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+
+	tokens := login(t, h, `{"username":"admin","password":"correct horse battery"}`)
+	if code := me(t, h, tokens.AccessToken); code != http.StatusOK {
+		t.Errorf("GET /auth/me with the access token = %d", code)
+	}
+	if code := me(t, h, tokens.RefreshToken); code != http.StatusUnauthorized {
+		t.Errorf("GET /auth/me with the refresh token = %d, want 401", code)
+	}
+}
+
+func TestRefreshRotation(t *testing.T) {
+	h, _ := newTestAuthRouter(t, AuthConfig{})
+	first := login(t, h, `{"username":"admin","password":"correct horse battery"}`)
+
+	resp := refresh(t, h, first.RefreshToken)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("refresh = %d", resp.StatusCode)
+	}
+	var second tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&second); err != nil {
+		t.Fatal(err)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Fatal("refresh kept the refresh token")
+	}
+	if code := me(t, h, second.AccessToken); code != http.StatusOK {
+		t.Errorf("new access token = %d", code)
+	}
+
+	// Replaying the used token means it leaked: the session is revoked for
+	// both holders
+	if resp := refresh(t, h, first.RefreshToken); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("replayed refresh = %d, want 401", resp.StatusCode)
+	}
+	if resp := refresh(t, h, second.RefreshToken); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("refresh after replay = %d, want 401", resp.StatusCode)
+	}
+	if code := me(t, h, second.AccessToken); code != http.StatusUnauthorized {
+		t.Errorf("access token after replay = %d, want 401", code)
+	}
+
+	// Other sessions are not affected
+	other := login(t, h, `{"username":"admin","password":"correct horse battery"}`)
+	if resp := refresh(t, h, other.RefreshToken); resp.StatusCode != http.StatusOK {
+		t.Errorf("refresh of another session = %d", resp.StatusCode)
+	}
+}
+
+func TestRefreshRejects(t *testing.T) {
+	h, _ := newTestAuthRouter(t, AuthConfig{})
+	tokens := login(t, h, `{"username":"admin","password":"correct horse battery"}`)
+
+	for _, token := range []string{"", "garbage", "unknown.secret", tokens.AccessToken} {
+		if resp := refresh(t, h, token); resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("refresh(%q) = %d, want 401", token, resp.StatusCode)
+		}
+	}
+}
+
+func TestLogout(t *testing.T) {
+	h, _ := newTestAuthRouter(t, AuthConfig{})
+	tokens := login(t, h, `{"username":"admin","password":"correct horse battery"}`)
+	other := login(t, h, `{"username":"admin","password":"correct horse battery"}`)
+
+	if rec := serve(t, h, http.MethodPost, "/auth/logout", "", "Authorization", "Bearer "+tokens.AccessToken); rec.Code != http.StatusNoContent {
+		t.Fatalf("logout = %d", rec.Code)
+	}
+	if code := me(t, h, tokens.AccessToken); code != http.StatusUnauthorized {
+		t.Errorf("access token after logout = %d, want 401", code)
+	}
+	if resp := refresh(t, h, tokens.RefreshToken); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("refresh after logout = %d, want 401", resp.StatusCode)
+	}
+	if code := me(t, h, other.AccessToken); code != http.StatusOK {
+		t.Errorf("other session after logout = %d, want 200", code)
+	}
+
+	if rec := serve(t, h, http.MethodPost, "/auth/logout?all=true", "", "Authorization", "Bearer "+other.AccessToken); rec.Code != http.StatusNoContent {
+		t.Fatalf("logout all = %d", rec.Code)
+	}
+	if code := me(t, h, other.AccessToken); code != http.StatusUnauthorized {
+		t.Errorf("access token after logout all = %d, want 401", code)
+	}
+}
+
+// totpCode is what an authenticator app shows for secret at t
+func totpCode(t *testing.T, secret string, at time.Time) string {
+	t.Helper()
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(at.Unix()/30))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	return fmt.Sprintf("%06d", binary.BigEndian.Uint32(sum[offset:offset+4])&0x7fffffff%1000000)
+}
+
+// enableTOTP enrolls the test account and returns its secret and
+// recovery codes
+func enableTOTP(t *testing.T, h http.Handler) (string, []string) {
+	t.Helper()
+	tokens := login(t, h, `{"username":"admin","password":"correct horse battery"}`)
+	bearer := "Bearer " + tokens.AccessToken
+
+	rec := serve(t, h, http.MethodPost, "/auth/totp/setup", "", "Authorization", bearer)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("setup = %d: %s", rec.Code, rec.Body)
+	}
+	var setup totpSetupResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &setup); err != nil {
+		t.Fatal(err)
+	}
+
+	if rec := serve(t, h, http.MethodPost, "/auth/totp/enable", `{"code":"000000"}`, "Authorization", bearer); rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("enable with a wrong code = %d, want 422", rec.Code)
+	}
+	rec = serve(t, h, http.MethodPost, "/auth/totp/enable", `{"code":"`+totpCode(t, setup.Secret, time.Now())+`"}`, "Authorization", bearer)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("enable = %d: %s", rec.Code, rec.Body)
+	}
+	var recovery recoveryCodesResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &recovery); err != nil {
+		t.Fatal(err)
+	}
+	return setup.Secret, recovery.RecoveryCodes
+}
+
+func TestLoginWithTOTP(t *testing.T) {
+	h, _ := newTestAuthRouter(t, AuthConfig{})
+	secret, recovery := enableTOTP(t, h)
+	loginWith := func(otp string) *http.Response {
+		return serve(t, h, http.MethodPost, "/auth/login", `{"username":"admin","password":"correct horse battery","otp":"`+otp+`"}`).Result()
+	}
+
+	resp := loginWith("")
+	var p problem
+	json.NewDecoder(resp.Body).Decode(&p)
+	if resp.StatusCode != http.StatusUnauthorized || p.Type != problemOTPRequired {
+		t.Fatalf("login without a code = %d %q, want 401 %q", resp.StatusCode, p.Type, problemOTPRequired)
+	}
+	if resp := serve(t, h, http.MethodPost, "/auth/login", `{"username":"admin","password":"wrong","otp":"`+totpCode(t, secret, time.Now())+`"}`); resp.Code != http.StatusUnauthorized {
+		t.Errorf("wrong password with a valid code = %d, want 401", resp.Code)
+	}
+
+	// The code confirmed at enrollment is spent; the next period's code is
+	// still within the allowed drift
+	if resp := loginWith(totpCode(t, secret, time.Now())); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("login with the enrollment code = %d, want 401", resp.StatusCode)
+	}
+	next := totpCode(t, secret, time.Now().Add(30*time.Second))
+	if resp := loginWith(next); resp.StatusCode != http.StatusOK {
+		t.Fatalf("login with a fresh code = %d, want 200", resp.StatusCode)
+	}
+	if resp := loginWith(next); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("replayed code = %d, want 401", resp.StatusCode)
+	}
+
+	// Recovery codes work once, however they are typed
+	typed := strings.ToUpper(strings.ReplaceAll(recovery[0], "-", " "))
+	if resp := loginWith(typed); resp.StatusCode != http.StatusOK {
+		t.Fatalf("login with a recovery code = %d, want 200", resp.StatusCode)
+	}
+	if resp := loginWith(recovery[0]); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("reused recovery code = %d, want 401", resp.StatusCode)
+	}
+	if resp := loginWith(recovery[1]); resp.StatusCode != http.StatusOK {
+		t.Errorf("login with another recovery code = %d, want 200", resp.StatusCode)
+	}
+}
@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"server/internal/entity"
+)
+
+// CachePolicy is the Cache-Control of public content responses. A zero
+// MaxAge makes clients revalidate every time. Refresh bounds how long the
+// in-process copy is served, so changes made through another replica show
+// up too.
+type CachePolicy struct {
+	MaxAge               time.Duration
+	StaleWhileRevalidate time.Duration
+	Refresh              time.Duration
+}
+
+func (p CachePolicy) header() string {
+	if p.MaxAge <= 0 {
+		return "public, no-cache"
+	}
+	header := fmt.Sprintf("public, max-age=%d", int(p.MaxAge.Seconds()))
+	if p.StaleWhileRevalidate > 0 {
+		header += fmt.Sprintf(", stale-while-revalidate=%d", int(p.StaleWhileRevalidate.Seconds()))
+	}
+	return header
+}
+
+// contentSnapshot is the published content together with its validators
+type contentSnapshot struct {
+	// content is what visitors see
+	content *entity.SiteContent
+	// editable adds scheduled items and translations into locales out of
+	// the config, as admins edit it. Drafts are never part of a snapshot.
+	editable *entity.SiteContent
+	// etags holds the public tag of every section, and of the whole
+	// content under "". They depend only on published content, so saving
+	// a draft neither busts caches nor tells anyone a draft exists. While
+	// a section has no draft its public tag is also its edit tag, so a
+	// plain GET is enough to send If-Match.
+	etags map[string]string
+	// versions are the section versions read before the content, see
+	// freshContent
+	versions     map[string]int64
+	lastModified time.Time
+	// expires is the next scheduled publish/unpublish or the refresh
+	// interval, whichever comes first
+	expires time.Time
+}
+
+// contentCache keeps the assembled public content between requests. It is
+// dropped on every admin change and when a scheduled item goes live or
+// expires.
+type contentCache struct {
+	mu       sync.Mutex
+	snapshot *contentSnapshot
+	// last survives invalidation so Last-Modified only moves when the
+	// content really changed
+	last *contentSnapshot
+}
+
+func (c *contentCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.snapshot = nil
+}
+
+// drafted records that saving a draft moved section from version to the
+// next one. Drafts are not part of the snapshot, so it stays valid.
+func (c *contentCache) drafted(section string, version int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := c.snapshot
+	if s == nil || s.versions[section] != version {
+		return
+	}
+	updated := *s
+	updated.versions = maps.Clone(s.versions)
+	updated.versions[section]++
+	c.snapshot = &updated
+}
+
+// InvalidateCache drops the cached content, e.g. after a scheduled change
+func (h *ContentHandler) InvalidateCache() {
+	h.cache.invalidate()
+}
+
+// content returns the cached snapshot, loading it on a miss. Concurrent
+// misses wait for a single load.
+func (h *ContentHandler) content(ctx context.Context) (*contentSnapshot, error) {
+	h.cache.mu.Lock()
+	defer h.cache.mu.Unlock()
+
+	now := time.Now()
+	if s := h.cache.snapshot; s != nil && now.Before(s.expires) {
+		return s, nil
+	}
+
+	s, err := h.loadSnapshot(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	if last := h.cache.last; last != nil && last.etags[""] == s.etags[""] {
+		s.lastModified = last.lastModified
+	}
+	h.cache.snapshot, h.cache.last = s, s
+	return s, nil
+}
+
+// freshContent is content for writes and the admin views: the snapshot is
+// reloaded first if a section changed since it was taken, e.g. through
+// another replica. Public reads do with the refresh interval instead.
+func (h *ContentHandler) freshContent(ctx context.Context) (*contentSnapshot, error) {
+	versions, err := h.drafts.SectionVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s, err := h.content(ctx)
+	if err != nil || maps.Equal(s.versions, versions) {
+		return s, err
+	}
+	h.cache.invalidate()
+	return h.content(ctx)
+}
+
+func (h *ContentHandler) loadSnapshot(ctx context.Context, now time.Time) (*contentSnapshot, error) {
+	// Read before the content: a change in between moves the versions past
+	// the ones kept, so writes based on this snapshot fail
+	versions, err := h.drafts.SectionVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	editable, err := h.repo.GetEditable(ctx)
+	if err != nil {
+		return nil, err
+	}
+	content := h.visible(editable, now)
+
+	expires := now.Add(h.cachePolicy.Load().Refresh)
+	if next := editable.NextChange(now); next != nil && next.Before(expires) {
+		expires = *next
+	}
+
+	s := &contentSnapshot{
+		content:      &content,
+		editable:     editable,
+		etags:        make(map[string]string, len(entity.Sections)+1),
+		versions:     versions,
+		lastModified: now.Truncate(time.Second),
+		expires:      expires,
+	}
+	all := sha256.New()
+	for _, section := range entity.Sections {
+		if s.etags[section], err = s.editTag(section, nil); err != nil {
+			return nil, err
+		}
+		all.Write([]byte(s.etags[section]))
+	}
+	s.etags[""] = `"` + hex.EncodeToString(all.Sum(nil)[:16]) + `"`
+	return s, nil
+}
+
+// visible returns what visitors see of c at now: the items inside their
+// publish window, in the configured locales only
+func (h *ContentHandler) visible(c *entity.SiteContent, now time.Time) entity.SiteContent {
+	v := c.Visible(now)
+	// The stats are shared with c, which must not change
+	v.About.Stats = slices.Clone(v.About.Stats)
+	v.MapLocalized(h.locales.Locales().Filter)
+	return v
+}
+
+// editTag returns the tag admins send as If-Match for section. It covers
+// the draft, or the editable published state if draft is nil, and what
+// visitors see. With a nil draft it is the public tag of the section.
+func (s *contentSnapshot) editTag(section string, draft []byte) (string, error) {
+	e, err := s.editable.Section(section)
+	if err != nil {
+		return "", err
+	}
+	if draft != nil {
+		var c entity.SiteContent
+		if err := c.SetSection(section, draft); err != nil {
+			return "", err
+		}
+		if e, err = c.Section(section); err != nil {
+			return "", err
+		}
+	}
+	v, err := s.content.Section(section)
+	if err != nil {
+		return "", err
+	}
+	return computeETag(e, v)
+}
+
+// respondContent writes a public content response with its validators and
+// Cache-Control, or 304 if the client's copy is current. etag is the tag of
+// value without a language; localized builds the ?lang= variant of value.
+func (h *ContentHandler) respondContent(w http.ResponseWriter, r *http.Request, s *contentSnapshot, etag string, value interface{}, localized func(entity.Translate) interface{}) {
+	t, localize := h.translator(w, r)
+
+	if lang := w.Header().Get("Content-Language"); lang != "" {
+		etag = strings.TrimSuffix(etag, `"`) + "-" + lang + `"`
+	}
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Last-Modified", s.lastModified.UTC().Format(http.TimeFormat))
+	w.Header().Set("Cache-Control", h.cachePolicy.Load().header())
+
+	if notModified(r, etag, s.lastModified) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	if localize {
+		value = localized(t)
+	}
+	respondJSON(w, http.StatusOK, value)
+}
+
+// notModified evaluates If-None-Match, or If-Modified-Since when there is
+// no If-None-Match, as RFC 9110 requires
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, tag := range strings.Split(inm, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" {
+		since, err := http.ParseTime(ims)
+		return err == nil && !lastModified.After(since)
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"server/internal/entity"
+	"server/internal/locale"
+	"server/internal/preview"
+	"server/internal/repository"
+	"server/internal/validate"
+)
+
+// countingStore counts the full content reads of a memory store
+type countingStore struct {
+	*repository.MemoryRepository
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (s *countingStore) count(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls[name]++
+}
+
+func (s *countingStore) get(name string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[name]
+}
+
+func (s *countingStore) GetAll(ctx context.Context) (*entity.SiteContent, error) {
+	s.count("GetAll")
+	return s.MemoryRepository.GetAll(ctx)
+}
+
+func (s *countingStore) GetEditable(ctx context.Context) (*entity.SiteContent, error) {
+	s.count("GetEditable")
+	return s.MemoryRepository.GetEditable(ctx)
+}
+
+func (s *countingStore) GetDrafts(ctx context.Context) ([]entity.ContentDraft, error) {
+	s.count("GetDrafts")
+	return s.MemoryRepository.GetDrafts(ctx)
+}
+
+func TestSnapshotReads(t *testing.T) {
+	repo := repository.NewMemoryRepository(locale.Default)
+	store := &countingStore{MemoryRepository: repo, calls: make(map[string]int)}
+	h := NewContentHandler(store, repo, store,
+		preview.NewSigner([]byte("test-secret"), time.Hour),
+		locale.NewNegotiator(locale.Default, nil),
+		validate.New(locale.Default),
+		CachePolicy{MaxAge: time.Minute, Refresh: time.Hour},
+	)
+	r := chi.NewRouter()
+	r.Get("/content/about", h.GetAbout)
+	r.Put("/content/about", h.UpdateAbout)
+	r.Get("/content/drafts", h.GetDrafts)
+
+	check := func(step string, editable, drafts int) {
+		t.Helper()
+		if got := store.get("GetAll"); got != 0 {
+			t.Errorf("%s: GetAll called %d times", step, got)
+		}
+		if got := store.get("GetEditable"); got != editable {
+			t.Errorf("%s: GetEditable called %d times, want %d", step, got, editable)
+		}
+		if got := store.get("GetDrafts"); got != drafts {
+			t.Errorf("%s: GetDrafts called %d times, want %d", step, got, drafts)
+		}
+	}
+
+	serve(t, r, http.MethodGet, "/content/about", "")
+	check("public read", 1, 0)
+
+	_, etags := drafts(t, r)
+	check("drafts", 1, 1)
+
+	body := editedAbout(t, r, "@draft")
+	if rec := serve(t, r, http.MethodPut, "/content/about", body, "If-Match", etags["about"]); rec.Code != http.StatusOK {
+		t.Fatalf("PUT = %d: %s", rec.Code, rec.Body)
+	}
+	serve(t, r, http.MethodGet, "/content/about", "")
+	check("write and read", 1, 2)
+
+	// Another replica publishes: the next admin read notices the version
+	if _, err := repo.PublishDrafts(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	drafts(t, r)
+	check("change elsewhere", 2, 3)
+}
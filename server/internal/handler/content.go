@@ -1,68 +1,95 @@
 package handler
 
 import (
-	"context"
 	"encoding/json"
 	"net/http"
-	"os"
+	"sync"
+	"sync/atomic"
 
 	"server/internal/entity"
+	"server/internal/locale"
+	"server/internal/preview"
 	"server/internal/repository"
+	"server/internal/validate"
 )
 
 type ContentHandler struct {
-	repo *repository.PostgresRepository
+	repo      repository.ContentStore
+	revisions repository.RevisionStore
+	drafts    repository.DraftStore
+	preview   *preview.Signer
+	locales   *locale.Negotiator
+	validator *validate.Validator
+
+	cachePolicy atomic.Pointer[CachePolicy]
+	cache       contentCache
+
+	// editMu serializes read-modify-write edits of single items
+	editMu sync.Mutex
 }
 
-func NewContentHandler(repo *repository.PostgresRepository) *ContentHandler {
-	return &ContentHandler{repo: repo}
+func NewContentHandler(repo repository.ContentStore, revisions repository.RevisionStore, drafts repository.DraftStore, previewSigner *preview.Signer, locales *locale.Negotiator, validator *validate.Validator, cachePolicy CachePolicy) *ContentHandler {
+	h := &ContentHandler{repo: repo, revisions: revisions, drafts: drafts, preview: previewSigner, locales: locales, validator: validator}
+	h.SetCachePolicy(cachePolicy)
+	return h
 }
 
-// Auth middleware
-func (h *ContentHandler) AuthMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		password := r.Header.Get("X-Admin-Password")
-		adminPassword := os.Getenv("ADMIN_PASSWORD")
-
-		if adminPassword == "" || password != adminPassword {
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
-			return
-		}
-
-		next.ServeHTTP(w, r)
-	})
+// SetCachePolicy replaces the caching of public content; responses already
+// being written keep the old one
+func (h *ContentHandler) SetCachePolicy(p CachePolicy) {
+	h.cachePolicy.Store(&p)
 }
 
 // GET /api/content - получить весь контент
+// GET /api/content?preview=<token> - the same with unpublished drafts applied
 func (h *ContentHandler) GetAll(w http.ResponseWriter, r *http.Request) {
-	content, err := h.repo.GetAll(r.Context())
+	if token := r.URL.Query().Get("preview"); token != "" {
+		h.getPreview(w, r, token)
+		return
+	}
+
+	s, err := h.content(r.Context())
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		respondError(w, r, err)
 		return
 	}
-	respondJSON(w, http.StatusOK, content)
+	h.respondContent(w, r, s, s.etags[""], s.content, func(t entity.Translate) interface{} {
+		return s.content.Localize(t)
+	})
 }
 
 // GET /api/content/about
 func (h *ContentHandler) GetAbout(w http.ResponseWriter, r *http.Request) {
-	about, err := h.repo.GetAbout(r.Context())
+	s, err := h.content(r.Context())
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		respondError(w, r, err)
 		return
 	}
-	respondJSON(w, http.StatusOK, about)
+	h.respondContent(w, r, s, s.etags[entity.SectionAbout], s.content.About, func(t entity.Translate) interface{} {
+		return s.content.About.Localize(t)
+	})
 }
 
 // PUT /api/content/about
 func (h *ContentHandler) UpdateAbout(w http.ResponseWriter, r *http.Request) {
 	var about entity.AboutContent
 	if err := json.NewDecoder(r.Body).Decode(&about); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		respondProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := h.validator.Validate(about); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(about)
+	if err != nil {
+		respondError(w, r, err)
 		return
 	}
 
-	if err := h.repo.UpdateAbout(context.Background(), about); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if err := h.replaceSection(w, r, entity.SectionAbout, "", data); err != nil {
+		respondError(w, r, err)
 		return
 	}
 
@@ -71,24 +98,36 @@ func (h *ContentHandler) UpdateAbout(w http.ResponseWriter, r *http.Request) {
 
 // GET /api/content/projects
 func (h *ContentHandler) GetProjects(w http.ResponseWriter, r *http.Request) {
-	projects, err := h.repo.GetProjects(r.Context())
+	s, err := h.content(r.Context())
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		respondError(w, r, err)
 		return
 	}
-	respondJSON(w, http.StatusOK, projects)
+	h.respondContent(w, r, s, s.etags[entity.SectionProjects], s.content.Projects, func(t entity.Translate) interface{} {
+		return entity.LocalizeProjects(s.content.Projects, t)
+	})
 }
 
 // PUT /api/content/projects
 func (h *ContentHandler) UpdateProjects(w http.ResponseWriter, r *http.Request) {
 	var projects []entity.Project
 	if err := json.NewDecoder(r.Body).Decode(&projects); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		respondProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := h.validator.Validate(projects); err != nil {
+		respondError(w, r, err)
 		return
 	}
 
-	if err := h.repo.UpdateProjects(context.Background(), projects); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	data, err := json.Marshal(projects)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	if err := h.replaceSection(w, r, entity.SectionProjects, "", data); err != nil {
+		respondError(w, r, err)
 		return
 	}
 
@@ -97,24 +136,36 @@ func (h *ContentHandler) UpdateProjects(w http.ResponseWriter, r *http.Request)
 
 // GET /api/content/skills
 func (h *ContentHandler) GetSkills(w http.ResponseWriter, r *http.Request) {
-	skills, err := h.repo.GetSkills(r.Context())
+	s, err := h.content(r.Context())
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		respondError(w, r, err)
 		return
 	}
-	respondJSON(w, http.StatusOK, skills)
+	h.respondContent(w, r, s, s.etags[entity.SectionSkills], s.content.Skills, func(t entity.Translate) interface{} {
+		return entity.LocalizeSkills(s.content.Skills, t)
+	})
 }
 
 // PUT /api/content/skills
 func (h *ContentHandler) UpdateSkills(w http.ResponseWriter, r *http.Request) {
 	var skills []entity.SkillCategory
 	if err := json.NewDecoder(r.Body).Decode(&skills); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		respondProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := h.validator.Validate(skills); err != nil {
+		respondError(w, r, err)
 		return
 	}
 
-	if err := h.repo.UpdateSkills(context.Background(), skills); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	data, err := json.Marshal(skills)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	if err := h.replaceSection(w, r, entity.SectionSkills, "", data); err != nil {
+		respondError(w, r, err)
 		return
 	}
 
@@ -123,24 +174,36 @@ func (h *ContentHandler) UpdateSkills(w http.ResponseWriter, r *http.Request) {
 
 // GET /api/content/contacts
 func (h *ContentHandler) GetContacts(w http.ResponseWriter, r *http.Request) {
-	contacts, err := h.repo.GetContacts(r.Context())
+	s, err := h.content(r.Context())
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		respondError(w, r, err)
 		return
 	}
-	respondJSON(w, http.StatusOK, contacts)
+	h.respondContent(w, r, s, s.etags[entity.SectionContacts], s.content.Contacts, func(t entity.Translate) interface{} {
+		return entity.LocalizeContacts(s.content.Contacts, t)
+	})
 }
 
 // PUT /api/content/contacts
 func (h *ContentHandler) UpdateContacts(w http.ResponseWriter, r *http.Request) {
 	var contacts []entity.Contact
 	if err := json.NewDecoder(r.Body).Decode(&contacts); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		respondProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := h.validator.Validate(contacts); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(contacts)
+	if err != nil {
+		respondError(w, r, err)
 		return
 	}
 
-	if err := h.repo.UpdateContacts(context.Background(), contacts); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if err := h.replaceSection(w, r, entity.SectionContacts, "", data); err != nil {
+		respondError(w, r, err)
 		return
 	}
 
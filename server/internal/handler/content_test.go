@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"server/internal/entity"
+	"server/internal/locale"
+	"server/internal/preview"
+	"server/internal/repository"
+	"server/internal/validate"
+)
+
+func TestUpdateRejectsDuplicateIDs(t *testing.T) {
+	h := newTestContentRouter(t)
+	content, etags := drafts(t, h)
+
+	var projects []json.RawMessage
+	if err := json.Unmarshal(content["projects"], &projects); err != nil || len(projects) == 0 {
+		t.Fatalf("no projects to copy: %v", err)
+	}
+	body, err := json.Marshal(append(projects, projects[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := serve(t, h, http.MethodPut, "/content/projects", string(body), "If-Match", etags["projects"])
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("PUT = %d, want 422: %s", rec.Code, rec.Body)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	after, afterTags := drafts(t, h)
+	if string(after["projects"]) != string(content["projects"]) || afterTags["projects"] != etags["projects"] {
+		t.Error("rejected update changed the projects")
+	}
+}
+
+func TestUpdateKeepsOtherLocales(t *testing.T) {
+	h := newTestContentRouter(t)
+	_, etags := drafts(t, h)
+
+	body := `[{"id":"mail","type":"email","label":{"ru":"Почта","de":"E-Mail"},"value":"a@example.com","link":"mailto:a@example.com","icon":"mail","color":"#000"}]`
+	rec := serve(t, h, http.MethodPut, "/content/contacts", body, "If-Match", etags["contacts"])
+	if rec.Code != http.StatusOK {
+		t.Fatalf("PUT = %d: %s", rec.Code, rec.Body)
+	}
+
+	content, _ := drafts(t, h)
+	var contacts []struct {
+		Label map[string]string `json:"label"`
+	}
+	if err := json.Unmarshal(content["contacts"], &contacts); err != nil || len(contacts) != 1 {
+		t.Fatalf("contacts %s: %v", content["contacts"], err)
+	}
+	if contacts[0].Label["de"] != "E-Mail" {
+		t.Errorf("draft label = %v, want the de translation kept", contacts[0].Label)
+	}
+
+	// After publishing the editors still see it, the site does not
+	if rec := serve(t, h, http.MethodPost, "/content/publish", ""); rec.Code != http.StatusOK {
+		t.Fatalf("publish = %d: %s", rec.Code, rec.Body)
+	}
+	content, _ = drafts(t, h)
+	if err := json.Unmarshal(content["contacts"], &contacts); err != nil || contacts[0].Label["de"] != "E-Mail" {
+		t.Errorf("editable contacts %s", content["contacts"])
+	}
+	var site struct {
+		Contacts []struct {
+			Label map[string]string `json:"label"`
+		} `json:"contacts"`
+	}
+	rec = serve(t, h, http.MethodGet, "/content", "")
+	if err := json.Unmarshal(rec.Body.Bytes(), &site); err != nil || len(site.Contacts) != 1 {
+		t.Fatalf("GET /content: %v %s", err, rec.Body)
+	}
+	if _, ok := site.Contacts[0].Label["de"]; ok || site.Contacts[0].Label["ru"] != "Почта" {
+		t.Errorf("published label = %v, want only ru and en", site.Contacts[0].Label)
+	}
+}
+
+// racingDrafts runs race, which saves a draft like another replica would,
+// right after the handler read the section version and before it writes
+type racingDrafts struct {
+	*repository.MemoryRepository
+	race func(ctx context.Context) error
+}
+
+func (d *racingDrafts) GetDraft(ctx context.Context, section string) (entity.ContentDraft, error) {
+	if race := d.race; race != nil {
+		d.race = nil
+		if err := race(ctx); err != nil {
+			return entity.ContentDraft{}, err
+		}
+	}
+	return d.MemoryRepository.GetDraft(ctx, section)
+}
+
+// saveOther saves value as the draft of a section at its current version
+func saveOther(ctx context.Context, repo *repository.MemoryRepository, section string, value interface{}) error {
+	versions, err := repo.SectionVersions(ctx)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	draft := entity.ContentDraft{Section: section, Data: data, Author: "other"}
+	return repo.SaveDraft(ctx, draft, versions[section])
+}
+
+func TestUpdateLosesRaceWithAnotherReplica(t *testing.T) {
+	repo := repository.NewMemoryRepository(locale.Default)
+	store := &racingDrafts{MemoryRepository: repo, race: func(ctx context.Context) error {
+		about, err := repo.GetAbout(ctx)
+		if err != nil {
+			return err
+		}
+		about.Username = "@other"
+		return saveOther(ctx, repo, entity.SectionAbout, about)
+	}}
+	h := NewContentHandler(repo, repo, store,
+		preview.NewSigner([]byte("test-secret"), time.Hour),
+		locale.NewNegotiator(locale.Default, nil),
+		validate.New(locale.Default),
+		CachePolicy{MaxAge: time.Minute, Refresh: time.Hour},
+	)
+	r := chi.NewRouter()
+	r.Put("/content/about", h.UpdateAbout)
+	r.Get("/content/drafts", h.GetDrafts)
+
+	_, etags := drafts(t, r)
+	rec := serve(t, r, http.MethodPut, "/content/about", editedAbout(t, r, "@mine"), "If-Match", etags["about"])
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Fatalf("PUT = %d, want 412: %s", rec.Code, rec.Body)
+	}
+
+	draft, err := repo.GetDraft(context.Background(), entity.SectionAbout)
+	if err != nil || !strings.Contains(string(draft.Data), "@other") {
+		t.Errorf("the other write was overwritten: %s %v", draft.Data, err)
+	}
+}
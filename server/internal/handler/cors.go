@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/go-chi/cors"
+)
+
+// CORS applies cross-origin rules that can be replaced while the server
+// runs; a request uses the rules current when it arrives
+type CORS struct {
+	current atomic.Pointer[cors.Cors]
+}
+
+func NewCORS(options cors.Options) *CORS {
+	c := &CORS{}
+	c.Update(options)
+	return c
+}
+
+func (c *CORS) Update(options cors.Options) {
+	c.current.Store(cors.New(options))
+}
+
+func (c *CORS) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.current.Load().Handler(next).ServeHTTP(w, r)
+	})
+}
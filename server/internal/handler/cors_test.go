@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/cors"
+)
+
+func TestCORSUpdate(t *testing.T) {
+	c := NewCORS(cors.Options{AllowedOrigins: []string{"https://a.example"}})
+	h := c.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	allowed := func(origin string) string {
+		r := httptest.NewRequest(http.MethodGet, "/api/content", nil)
+		r.Header.Set("Origin", origin)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+		return rec.Header().Get("Access-Control-Allow-Origin")
+	}
+
+	if got := allowed("https://a.example"); got != "https://a.example" {
+		t.Fatalf("Allow-Origin = %q before the update", got)
+	}
+	c.Update(cors.Options{AllowedOrigins: []string{"https://b.example"}})
+	if got := allowed("https://a.example"); got != "" {
+		t.Errorf("removed origin still allowed: %q", got)
+	}
+	if got := allowed("https://b.example"); got != "https://b.example" {
+		t.Errorf("Allow-Origin = %q for the new origin", got)
+	}
+}
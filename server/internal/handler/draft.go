@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"server/internal/entity"
+	"server/internal/logging"
+	"server/internal/repository"
+)
+
+type draftsResponse struct {
+	Pending []entity.ContentDraft `json:"pending"`
+	Content *entity.SiteContent   `json:"content"`
+	// ETags are the tags to send as If-Match when updating each section
+	ETags map[string]string `json:"etags"`
+}
+
+// GET /api/content/drafts - pending drafts, the content they would publish
+// and the edit tag of every section
+func (h *ContentHandler) GetDrafts(w http.ResponseWriter, r *http.Request) {
+	s, content, drafts, err := h.draftContent(r.Context())
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	pending := make(map[string][]byte, len(drafts))
+	for i := range drafts {
+		pending[drafts[i].Section] = drafts[i].Data
+		drafts[i].Data = nil
+	}
+	etags := make(map[string]string, len(entity.Sections))
+	for _, section := range entity.Sections {
+		if etags[section], err = s.editTag(section, pending[section]); err != nil {
+			respondError(w, r, err)
+			return
+		}
+	}
+	respondJSON(w, http.StatusOK, draftsResponse{Pending: drafts, Content: content, ETags: etags})
+}
+
+// DELETE /api/content/drafts/{section}
+func (h *ContentHandler) DiscardDraft(w http.ResponseWriter, r *http.Request) {
+	section := chi.URLParam(r, "section")
+	if !entity.IsSection(section) {
+		respondProblem(w, r, http.StatusNotFound, "Unknown section")
+		return
+	}
+
+	err := h.drafts.DiscardDraft(r.Context(), section)
+	h.cache.invalidate()
+	if errors.Is(err, repository.ErrNotFound) {
+		respondProblem(w, r, http.StatusNotFound, "No draft for this section")
+		return
+	}
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// POST /api/content/publish - make all drafts live at once
+func (h *ContentHandler) Publish(w http.ResponseWriter, r *http.Request) {
+	ctx := context.WithoutCancel(r.Context())
+
+	published, err := h.drafts.PublishDrafts(ctx)
+	h.cache.invalidate()
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	auditFromContext(ctx).addEntity(published...)
+	author := actorFromContext(r.Context())
+	if len(published) > 0 {
+		h.savePublished(ctx, author, published)
+	}
+
+	if published == nil {
+		published = []string{}
+	}
+	respondJSON(w, http.StatusOK, map[string][]string{"published": published})
+}
+
+// savePublished records a revision of every published section. Publishing
+// already happened, so failures are only logged.
+func (h *ContentHandler) savePublished(ctx context.Context, author string, published []string) {
+	s, err := h.content(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to read published content", "err", err)
+		return
+	}
+	for _, section := range published {
+		v, err := s.editable.Section(section)
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to snapshot published section", "section", section, "err", err)
+			continue
+		}
+		snapshot, err := json.Marshal(v)
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to snapshot published section", "section", section, "err", err)
+			continue
+		}
+		h.saveRevision(ctx, entity.ContentRevision{Section: section, Author: author, Summary: "published", Snapshot: snapshot})
+	}
+}
+
+// POST /api/content/preview - issue a token for GET /api/content?preview=
+func (h *ContentHandler) IssuePreviewToken(w http.ResponseWriter, r *http.Request) {
+	token, expires := h.preview.Issue(time.Now())
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"token":      token,
+		"expires_at": expires,
+	})
+}
+
+func (h *ContentHandler) getPreview(w http.ResponseWriter, r *http.Request, token string) {
+	if !h.preview.Verify(token, time.Now()) {
+		respondProblem(w, r, http.StatusUnauthorized, "Invalid or expired preview token")
+		return
+	}
+
+	_, content, _, err := h.draftContent(r.Context())
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	// Show exactly what visitors would see once the drafts are published
+	visible := h.visible(content, time.Now())
+	w.Header().Set("Cache-Control", "no-store")
+	if t, ok := h.translator(w, r); ok {
+		respondJSON(w, http.StatusOK, visible.Localize(t))
+		return
+	}
+	respondJSON(w, http.StatusOK, visible)
+}
+
+// draftContent returns the published content with every pending draft
+// applied, including items outside their publish window, and the snapshot
+// it started from
+func (h *ContentHandler) draftContent(ctx context.Context) (*contentSnapshot, *entity.SiteContent, []entity.ContentDraft, error) {
+	s, err := h.freshContent(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	drafts, err := h.drafts.GetDrafts(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// SetSection replaces whole sections, so the snapshot is left as it is
+	content := *s.editable
+	for _, d := range drafts {
+		if err := content.SetSection(d.Section, d.Data); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	return s, &content, drafts, nil
+}
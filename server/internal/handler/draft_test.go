@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func previewToken(t *testing.T, h http.Handler) string {
+	t.Helper()
+	rec := serve(t, h, http.MethodPost, "/content/preview", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST /content/preview = %d", rec.Code)
+	}
+	var resp struct {
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || resp.Token == "" {
+		t.Fatalf("no token: %v", err)
+	}
+	return resp.Token
+}
+
+func TestDraftPublish(t *testing.T) {
+	h := newTestContentRouter(t)
+	_, etags := drafts(t, h)
+
+	if rec := serve(t, h, http.MethodPut, "/content/about", editedAbout(t, h, "@draft"), "If-Match", etags["about"]); rec.Code != http.StatusOK {
+		t.Fatalf("PUT = %d: %s", rec.Code, rec.Body)
+	}
+	if body := serve(t, h, http.MethodGet, "/content", "").Body.String(); strings.Contains(body, "@draft") {
+		t.Error("draft is public before publishing")
+	}
+
+	preview := serve(t, h, http.MethodGet, "/content?preview="+url.QueryEscape(previewToken(t, h)), "")
+	if preview.Code != http.StatusOK || !strings.Contains(preview.Body.String(), "@draft") {
+		t.Errorf("preview = %d without the draft", preview.Code)
+	}
+	if got := preview.Header().Get("Cache-Control"); got != "no-store" {
+		t.Errorf("preview Cache-Control = %q", got)
+	}
+	if rec := serve(t, h, http.MethodGet, "/content?preview=1.forged", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("forged preview token = %d, want 401", rec.Code)
+	}
+
+	rec := serve(t, h, http.MethodGet, "/content/drafts", "")
+	var pending struct {
+		Pending []struct {
+			Section string          `json:"section"`
+			Data    json.RawMessage `json:"data"`
+		} `json:"pending"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &pending); err != nil {
+		t.Fatal(err)
+	}
+	if len(pending.Pending) != 1 || pending.Pending[0].Section != "about" || pending.Pending[0].Data != nil {
+		t.Errorf("pending = %+v", pending.Pending)
+	}
+
+	rec = serve(t, h, http.MethodPost, "/content/publish", "")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"published":["about"]`) {
+		t.Fatalf("publish = %d: %s", rec.Code, rec.Body)
+	}
+	if body := serve(t, h, http.MethodGet, "/content", "").Body.String(); !strings.Contains(body, "@draft") {
+		t.Error("published draft is not public")
+	}
+
+	// Nothing left to publish
+	rec = serve(t, h, http.MethodPost, "/content/publish", "")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"published":[]`) {
+		t.Errorf("second publish = %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestDiscardDraft(t *testing.T) {
+	h := newTestContentRouter(t)
+	original, etags := drafts(t, h)
+
+	if rec := serve(t, h, http.MethodPut, "/content/about", editedAbout(t, h, "@draft"), "If-Match", etags["about"]); rec.Code != http.StatusOK {
+		t.Fatalf("PUT = %d: %s", rec.Code, rec.Body)
+	}
+	if rec := serve(t, h, http.MethodDelete, "/content/drafts/about", ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("discard = %d: %s", rec.Code, rec.Body)
+	}
+	content, _ := drafts(t, h)
+	if string(content["about"]) != string(original["about"]) {
+		t.Error("discarding kept the draft")
+	}
+
+	if rec := serve(t, h, http.MethodDelete, "/content/drafts/about", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("discarding again = %d, want 404", rec.Code)
+	}
+	if rec := serve(t, h, http.MethodDelete, "/content/drafts/unknown", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("discarding an unknown section = %d, want 404", rec.Code)
+	}
+}
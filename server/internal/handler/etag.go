@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"server/internal/repository"
+)
+
+var (
+	errPreconditionFailed   = errors.New("content was changed by someone else, reload and try again")
+	errPreconditionRequired = errors.New("If-Match header is required")
+)
+
+// computeETag hashes the JSON of values into a strong tag. The public tag
+// of a section covers what visitors see; the edit tag adds the editable
+// version (draft or published, with scheduled items), so a change to
+// either gives a new one.
+func computeETag(values ...interface{}) (string, error) {
+	sum := sha256.New()
+	for i, v := range values {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		if i > 0 {
+			sum.Write([]byte{0})
+		}
+		sum.Write(data)
+	}
+	return `"` + hex.EncodeToString(sum.Sum(nil)[:16]) + `"`, nil
+}
+
+// sectionState is a section as admins edit it: its pending draft, or the
+// published state with scheduled items if there is none
+type sectionState struct {
+	snapshot *contentSnapshot
+	// version is the one the state was read at, for saveDraft
+	version int64
+	data    []byte
+	tag     string
+}
+
+// editState reads the state of a section a write starts from. Caller must
+// hold h.editMu: it keeps out writes of this process, the version those of
+// other replicas.
+func (h *ContentHandler) editState(ctx context.Context, section string) (sectionState, error) {
+	s, err := h.freshContent(ctx)
+	if err != nil {
+		return sectionState{}, err
+	}
+	st := sectionState{snapshot: s, version: s.versions[section]}
+
+	draft, err := h.drafts.GetDraft(ctx, section)
+	switch {
+	case err == nil:
+		st.data = draft.Data
+	case errors.Is(err, repository.ErrNotFound):
+		published, err := s.editable.Section(section)
+		if err != nil {
+			return st, err
+		}
+		if st.data, err = json.Marshal(published); err != nil {
+			return st, err
+		}
+	default:
+		return st, err
+	}
+
+	st.tag, err = s.editTag(section, draft.Data)
+	return st, err
+}
+
+// checkIfMatch compares the If-Match header with the tags a write accepts:
+// the edit tag of a section, and for item routes the tag of the item. A
+// required tag must be sent; otherwise writes are checked only if they send
+// one. Tags of ?lang= responses match too: they only add the language to
+// the tag of the same content.
+func checkIfMatch(r *http.Request, required bool, tags ...string) error {
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		if required {
+			return errPreconditionRequired
+		}
+		return nil
+	}
+	if strings.TrimSpace(ifMatch) == "*" {
+		return nil
+	}
+
+	for _, t := range strings.Split(ifMatch, ",") {
+		t = withoutLanguage(strings.TrimSpace(t))
+		for _, tag := range tags {
+			if tag != "" && t == tag {
+				return nil
+			}
+		}
+	}
+	return errPreconditionFailed
+}
+
+// withoutLanguage strips the "-en" respondContent adds to a tag; the hex
+// of a tag never contains '-'
+func withoutLanguage(tag string) string {
+	if i := strings.LastIndexByte(tag, '-'); i > 0 && strings.HasSuffix(tag, `"`) {
+		return tag[:i] + `"`
+	}
+	return tag
+}
+
+// replaceSection saves data as the new draft of a section if the request's
+// If-Match, which it must send, still matches, and sets the resulting ETag
+// on the response. An empty summary is computed from the change.
+func (h *ContentHandler) replaceSection(w http.ResponseWriter, r *http.Request, section, summary string, data []byte) error {
+	ctx := context.WithoutCancel(r.Context())
+
+	h.editMu.Lock()
+	defer h.editMu.Unlock()
+
+	st, err := h.editState(ctx, section)
+	if err != nil {
+		return err
+	}
+	if err := checkIfMatch(r, true, st.tag); err != nil {
+		return err
+	}
+	if err := h.saveDraft(ctx, section, st, actorFromContext(r.Context()), summary, data); err != nil {
+		return err
+	}
+	setWrittenETag(w, st, section, data)
+	return nil
+}
+
+// setWrittenETag tells the client the edit tag to send with its next
+// write. The change is already saved, so a failure only leaves the header
+// out.
+func setWrittenETag(w http.ResponseWriter, st sectionState, section string, data []byte) {
+	if tag, err := st.snapshot.editTag(section, data); err == nil {
+		w.Header().Set("ETag", tag)
+	}
+}
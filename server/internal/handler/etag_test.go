@@ -0,0 +1,303 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"server/internal/locale"
+	"server/internal/preview"
+	"server/internal/repository"
+	"server/internal/validate"
+)
+
+// newTestContentRouter serves the content routes over a fresh memory store
+func newTestContentRouter(t *testing.T) http.Handler {
+	t.Helper()
+	repo := repository.NewMemoryRepository(locale.Default)
+	h := NewContentHandler(repo, repo, repo,
+		preview.NewSigner([]byte("test-secret"), time.Hour),
+		locale.NewNegotiator(locale.Default, nil),
+		validate.New(locale.Default),
+		CachePolicy{MaxAge: time.Minute, Refresh: time.Hour},
+	)
+
+	r := chi.NewRouter()
+	r.Get("/content", h.GetAll)
+	r.Get("/content/about", h.GetAbout)
+	r.Get("/content/projects", h.GetProjects)
+	r.Get("/content/{section}/{id}", h.GetItem)
+	r.Get("/content/{section}/{id}/skills/{skill}", h.GetSkill)
+	r.Put("/content/about", h.UpdateAbout)
+	r.Put("/content/projects", h.UpdateProjects)
+	r.Put("/content/skills", h.UpdateSkills)
+	r.Put("/content/contacts", h.UpdateContacts)
+	r.Patch("/content/{section}/{id}", h.UpdateItem)
+	r.Delete("/content/{section}/{id}", h.DeleteItem)
+	r.Post("/content/{section}/reorder", h.ReorderItems)
+	r.Patch("/content/{section}/{id}/skills/{skill}", h.UpdateSkill)
+	r.Get("/content/drafts", h.GetDrafts)
+	r.Get("/content/{section}/revisions", h.ListRevisions)
+	r.Get("/content/{section}/revisions/{id}", h.GetRevision)
+	r.Post("/content/{section}/revisions/{id}/restore", h.RestoreRevision)
+	r.Delete("/content/drafts/{section}", h.DiscardDraft)
+	r.Post("/content/publish", h.Publish)
+	r.Post("/content/preview", h.IssuePreviewToken)
+	return r
+}
+
+// serve sends a request; headers are name/value pairs
+func serve(t *testing.T, h http.Handler, method, path, body string, headers ...string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+// drafts returns the editable content and edit tags from GET /content/drafts
+func drafts(t *testing.T, h http.Handler) (content map[string]json.RawMessage, etags map[string]string) {
+	t.Helper()
+	rec := serve(t, h, http.MethodGet, "/content/drafts", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /content/drafts = %d: %s", rec.Code, rec.Body)
+	}
+	var resp struct {
+		Content map[string]json.RawMessage `json:"content"`
+		ETags   map[string]string          `json:"etags"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp.Content, resp.ETags
+}
+
+// editedAbout is the editable about section with another username
+func editedAbout(t *testing.T, h http.Handler, username string) string {
+	t.Helper()
+	content, _ := drafts(t, h)
+	var about map[string]interface{}
+	if err := json.Unmarshal(content["about"], &about); err != nil {
+		t.Fatal(err)
+	}
+	about["username"] = username
+	data, err := json.Marshal(about)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestConditionalGet(t *testing.T) {
+	h := newTestContentRouter(t)
+
+	first := serve(t, h, http.MethodGet, "/content/about", "")
+	if first.Code != http.StatusOK {
+		t.Fatalf("GET = %d, want 200", first.Code)
+	}
+	etag, lastModified := first.Header().Get("ETag"), first.Header().Get("Last-Modified")
+	if etag == "" || lastModified == "" {
+		t.Fatalf("missing validators: ETag %q, Last-Modified %q", etag, lastModified)
+	}
+	if got := first.Header().Get("Cache-Control"); got != "public, max-age=60" {
+		t.Errorf("Cache-Control = %q", got)
+	}
+	earlier := time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)
+
+	tests := []struct {
+		name    string
+		headers []string
+		want    int
+	}{
+		{name: "no validators", want: http.StatusOK},
+		{name: "matching tag", headers: []string{"If-None-Match", etag}, want: http.StatusNotModified},
+		{name: "weak comparison", headers: []string{"If-None-Match", "W/" + etag}, want: http.StatusNotModified},
+		{name: "one of several", headers: []string{"If-None-Match", `"other", ` + etag}, want: http.StatusNotModified},
+		{name: "any", headers: []string{"If-None-Match", "*"}, want: http.StatusNotModified},
+		{name: "stale tag", headers: []string{"If-None-Match", `"other"`}, want: http.StatusOK},
+		{name: "not modified since", headers: []string{"If-Modified-Since", lastModified}, want: http.StatusNotModified},
+		{name: "modified since", headers: []string{"If-Modified-Since", earlier}, want: http.StatusOK},
+		{name: "If-None-Match wins", headers: []string{"If-None-Match", `"other"`, "If-Modified-Since", lastModified}, want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(t, h, http.MethodGet, "/content/about", "", tt.headers...)
+			if rec.Code != tt.want {
+				t.Fatalf("GET = %d, want %d", rec.Code, tt.want)
+			}
+			if rec.Header().Get("ETag") != etag {
+				t.Errorf("ETag = %q, want %q", rec.Header().Get("ETag"), etag)
+			}
+			if tt.want == http.StatusNotModified && rec.Body.Len() != 0 {
+				t.Errorf("304 has a body: %s", rec.Body)
+			}
+		})
+	}
+}
+
+func TestLocalizedETag(t *testing.T) {
+	h := newTestContentRouter(t)
+
+	all := serve(t, h, http.MethodGet, "/content/about", "").Header().Get("ETag")
+	en := serve(t, h, http.MethodGet, "/content/about?lang=en", "")
+	if got, want := en.Header().Get("ETag"), strings.TrimSuffix(all, `"`)+`-en"`; got != want {
+		t.Fatalf("ETag = %q, want %q", got, want)
+	}
+	if rec := serve(t, h, http.MethodGet, "/content/about?lang=en", "", "If-None-Match", all); rec.Code != http.StatusOK {
+		t.Errorf("tag of all languages matched the en variant: %d", rec.Code)
+	}
+	if rec := serve(t, h, http.MethodGet, "/content/about?lang=en", "", "If-None-Match", en.Header().Get("ETag")); rec.Code != http.StatusNotModified {
+		t.Errorf("GET ?lang=en with its tag = %d, want 304", rec.Code)
+	}
+}
+
+func TestIfMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		ifMatch func(editTag, publicTag string) string
+		want    int
+	}{
+		{name: "PUT without If-Match", method: http.MethodPut, path: "/content/about", ifMatch: func(string, string) string { return "" }, want: http.StatusPreconditionRequired},
+		{name: "PUT with edit tag", method: http.MethodPut, path: "/content/about", ifMatch: func(e, _ string) string { return e }, want: http.StatusOK},
+		{name: "PUT with one of several", method: http.MethodPut, path: "/content/about", ifMatch: func(e, _ string) string { return `"old", ` + e }, want: http.StatusOK},
+		{name: "PUT with any", method: http.MethodPut, path: "/content/about", ifMatch: func(string, string) string { return "*" }, want: http.StatusOK},
+		{name: "PUT with stale tag", method: http.MethodPut, path: "/content/about", ifMatch: func(string, string) string { return `"old"` }, want: http.StatusPreconditionFailed},
+		{name: "PUT with public tag", method: http.MethodPut, path: "/content/about", ifMatch: func(_, p string) string { return p }, want: http.StatusOK},
+		{name: "PATCH without If-Match", method: http.MethodPatch, path: "/content/projects/__ID__", ifMatch: func(string, string) string { return "" }, want: http.StatusPreconditionRequired},
+		{name: "PATCH with stale tag", method: http.MethodPatch, path: "/content/projects/__ID__", ifMatch: func(string, string) string { return `"old"` }, want: http.StatusPreconditionFailed},
+		{name: "DELETE without If-Match", method: http.MethodDelete, path: "/content/projects/__ID__", ifMatch: func(string, string) string { return "" }, want: http.StatusNoContent},
+		{name: "DELETE with stale tag", method: http.MethodDelete, path: "/content/projects/__ID__", ifMatch: func(string, string) string { return `"old"` }, want: http.StatusPreconditionFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestContentRouter(t)
+			content, etags := drafts(t, h)
+
+			section, body := "about", editedAbout(t, h, "@tester")
+			path := tt.path
+			if strings.Contains(path, "__ID__") {
+				var projects []struct {
+					ID string `json:"id"`
+				}
+				if err := json.Unmarshal(content["projects"], &projects); err != nil || len(projects) == 0 {
+					t.Fatalf("no projects to edit: %v", err)
+				}
+				section, body = "projects", `{"featured": true}`
+				path = strings.Replace(path, "__ID__", projects[0].ID, 1)
+			}
+			public := serve(t, h, http.MethodGet, "/content/"+section, "").Header().Get("ETag")
+
+			var headers []string
+			if v := tt.ifMatch(etags[section], public); v != "" {
+				headers = []string{"If-Match", v}
+			}
+			rec := serve(t, h, tt.method, path, body, headers...)
+			if rec.Code != tt.want {
+				t.Fatalf("%s %s = %d, want %d: %s", tt.method, path, rec.Code, tt.want, rec.Body)
+			}
+
+			_, after := drafts(t, h)
+			if rec.Code >= 400 {
+				if after[section] != etags[section] {
+					t.Error("rejected write changed the edit tag")
+				}
+				return
+			}
+			if after[section] == etags[section] {
+				t.Error("write kept the edit tag")
+			}
+			if got := rec.Header().Get("ETag"); tt.method == http.MethodPut && got != after[section] {
+				t.Errorf("response ETag = %q, want the new edit tag %q", got, after[section])
+			}
+		})
+	}
+}
+
+func TestPublicTagSatisfiesIfMatch(t *testing.T) {
+	h := newTestContentRouter(t)
+	content, _ := drafts(t, h)
+	var projects []struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(content["projects"], &projects); err != nil || len(projects) == 0 {
+		t.Fatalf("no projects to edit: %v", err)
+	}
+
+	// A plain GET of the item gives a tag PATCH accepts
+	path := "/content/projects/" + projects[0].ID
+	etag := serve(t, h, http.MethodGet, path, "").Header().Get("ETag")
+	if rec := serve(t, h, http.MethodPatch, path, `{"featured": true}`, "If-Match", etag); rec.Code != http.StatusOK {
+		t.Fatalf("PATCH with the GET tag = %d, want 200: %s", rec.Code, rec.Body)
+	}
+
+	// So does the tag of a ?lang= response
+	about := serve(t, h, http.MethodGet, "/content/about?lang=en", "").Header().Get("ETag")
+	if rec := serve(t, h, http.MethodPut, "/content/about", editedAbout(t, h, "@first"), "If-Match", about); rec.Code != http.StatusOK {
+		t.Fatalf("PUT with the ?lang= tag = %d, want 200: %s", rec.Code, rec.Body)
+	}
+
+	// The public tag does not cover the draft saved since, so it is stale
+	// now even though visitors still get it
+	if got := serve(t, h, http.MethodGet, "/content/about?lang=en", "").Header().Get("ETag"); got != about {
+		t.Fatalf("draft changed the public tag: %q -> %q", about, got)
+	}
+	if rec := serve(t, h, http.MethodPut, "/content/about", editedAbout(t, h, "@second"), "If-Match", about); rec.Code != http.StatusPreconditionFailed {
+		t.Errorf("PUT with the public tag over a draft = %d, want 412", rec.Code)
+	}
+}
+
+func TestDraftKeepsPublicValidators(t *testing.T) {
+	h := newTestContentRouter(t)
+
+	before := serve(t, h, http.MethodGet, "/content/about", "")
+	beforeAll := serve(t, h, http.MethodGet, "/content", "")
+	_, etags := drafts(t, h)
+
+	// Last-Modified has second precision
+	time.Sleep(1100 * time.Millisecond)
+	if rec := serve(t, h, http.MethodPut, "/content/about", editedAbout(t, h, "@draft"), "If-Match", etags["about"]); rec.Code != http.StatusOK {
+		t.Fatalf("PUT = %d: %s", rec.Code, rec.Body)
+	}
+
+	after := serve(t, h, http.MethodGet, "/content/about", "")
+	afterAll := serve(t, h, http.MethodGet, "/content", "")
+	for _, header := range []string{"ETag", "Last-Modified"} {
+		if before.Header().Get(header) != after.Header().Get(header) {
+			t.Errorf("draft changed %s of the section: %q -> %q", header, before.Header().Get(header), after.Header().Get(header))
+		}
+		if beforeAll.Header().Get(header) != afterAll.Header().Get(header) {
+			t.Errorf("draft changed %s of the content: %q -> %q", header, beforeAll.Header().Get(header), afterAll.Header().Get(header))
+		}
+	}
+	if rec := serve(t, h, http.MethodGet, "/content/about", "", "If-None-Match", before.Header().Get("ETag")); rec.Code != http.StatusNotModified {
+		t.Errorf("conditional GET after draft = %d, want 304", rec.Code)
+	}
+	if strings.Contains(after.Body.String(), "@draft") {
+		t.Error("draft is visible in the public content")
+	}
+
+	if rec := serve(t, h, http.MethodPost, "/content/publish", ""); rec.Code != http.StatusOK {
+		t.Fatalf("publish = %d: %s", rec.Code, rec.Body)
+	}
+	published := serve(t, h, http.MethodGet, "/content/about", "", "If-None-Match", before.Header().Get("ETag"))
+	if published.Code != http.StatusOK {
+		t.Fatalf("conditional GET after publish = %d, want 200", published.Code)
+	}
+	if published.Header().Get("Last-Modified") == before.Header().Get("Last-Modified") {
+		t.Error("publish kept Last-Modified")
+	}
+	if !strings.Contains(published.Body.String(), "@draft") {
+		t.Error("published content misses the draft")
+	}
+}
@@ -0,0 +1,572 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"server/internal/entity"
+	"server/internal/repository"
+	"server/internal/validate"
+)
+
+// itemList is a list section, or the skills of one category, as plain JSON
+// objects so every section shares the same per-item operations
+type itemList []map[string]interface{}
+
+func (l itemList) index(id string) int {
+	for i, item := range l {
+		if itemID, _ := item["id"].(string); itemID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+type reorderRequest struct {
+	IDs []string `json:"ids"`
+}
+
+// itemKinds names the items of each list section in error messages
+var itemKinds = map[string]string{
+	entity.SectionProjects: "project",
+	entity.SectionSkills:   "skill category",
+	entity.SectionContacts: "contact",
+}
+
+// GET /api/content/{section}/{id}
+func (h *ContentHandler) GetItem(w http.ResponseWriter, r *http.Request) {
+	section, id := chi.URLParam(r, "section"), chi.URLParam(r, "id")
+	if _, ok := itemKinds[section]; !ok {
+		respondProblem(w, r, http.StatusNotFound, "Unknown section")
+		return
+	}
+
+	s, err := h.content(r.Context())
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	var item interface{}
+	var localized func(entity.Translate) interface{}
+	switch section {
+	case entity.SectionProjects:
+		for _, p := range s.content.Projects {
+			if p.ID == id {
+				item = p
+				localized = func(t entity.Translate) interface{} {
+					return entity.LocalizeProjects([]entity.Project{p}, t)[0]
+				}
+				break
+			}
+		}
+	case entity.SectionSkills:
+		for _, c := range s.content.Skills {
+			if c.ID == id {
+				item = c
+				localized = func(t entity.Translate) interface{} {
+					return entity.LocalizeSkills([]entity.SkillCategory{c}, t)[0]
+				}
+				break
+			}
+		}
+	case entity.SectionContacts:
+		for _, c := range s.content.Contacts {
+			if c.ID == id {
+				item = c
+				localized = func(t entity.Translate) interface{} {
+					return entity.LocalizeContacts([]entity.Contact{c}, t)[0]
+				}
+				break
+			}
+		}
+	}
+
+	if item == nil {
+		respondProblem(w, r, http.StatusNotFound, fmt.Sprintf("%s %q not found", itemKinds[section], id))
+		return
+	}
+	// The tag is the one of the item, so PATCH and DELETE with it succeed
+	// as long as this item is unchanged, whatever happens to the others
+	h.respondContent(w, r, s, itemTag(s.editable, section, id), item, localized)
+}
+
+// POST /api/content/{section} - add one item to the section draft
+func (h *ContentHandler) CreateItem(w http.ResponseWriter, r *http.Request) {
+	section, ok := h.listSection(w, r)
+	if !ok {
+		return
+	}
+
+	var item map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+		respondProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if id, ok := item["id"].(string); ok {
+		auditFromContext(r.Context()).addEntity(id)
+	}
+	h.writeItem(w, r, http.StatusCreated, section, item, createItem(itemKinds[section], item))
+}
+
+// PATCH /api/content/{section}/{id} - merge a JSON patch into one item
+func (h *ContentHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	section, ok := h.listSection(w, r)
+	if !ok {
+		return
+	}
+
+	var patch map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		respondProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	h.writeItem(w, r, http.StatusOK, section, map[string]interface{}{"id": id}, patchItem(itemKinds[section], id, patch))
+}
+
+// DELETE /api/content/{section}/{id}
+func (h *ContentHandler) DeleteItem(w http.ResponseWriter, r *http.Request) {
+	section, ok := h.listSection(w, r)
+	if !ok {
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if _, err := h.editItems(w, r, section, []string{id}, deleteItem(itemKinds[section], id)); err != nil {
+		respondError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// POST /api/content/{section}/reorder - change only the order of items
+func (h *ContentHandler) ReorderItems(w http.ResponseWriter, r *http.Request) {
+	section, ok := h.listSection(w, r)
+	if !ok {
+		return
+	}
+
+	var req reorderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	items, err := h.editItems(w, r, section, nil, reorderItems(req.IDs))
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, items)
+}
+
+// GET /api/content/skills/{id}/skills/{skill}
+func (h *ContentHandler) GetSkill(w http.ResponseWriter, r *http.Request) {
+	if chi.URLParam(r, "section") != entity.SectionSkills {
+		respondProblem(w, r, http.StatusNotFound, "Unknown section")
+		return
+	}
+
+	s, err := h.content(r.Context())
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	categoryID, skillID := chi.URLParam(r, "id"), chi.URLParam(r, "skill")
+	for _, c := range s.content.Skills {
+		if c.ID != categoryID {
+			continue
+		}
+		for _, skill := range c.Skills {
+			if skill.ID == skillID {
+				tag := itemTag(s.editable, entity.SectionSkills, categoryID, skillID)
+				h.respondContent(w, r, s, tag, skill, func(entity.Translate) interface{} { return skill })
+				return
+			}
+		}
+	}
+	respondProblem(w, r, http.StatusNotFound, fmt.Sprintf("skill %q not found", skillID))
+}
+
+// POST /api/content/skills/{id}/skills
+func (h *ContentHandler) CreateSkill(w http.ResponseWriter, r *http.Request) {
+	if chi.URLParam(r, "section") != entity.SectionSkills {
+		respondProblem(w, r, http.StatusNotFound, "Unknown section")
+		return
+	}
+
+	var skill map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&skill); err != nil {
+		respondProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if id, ok := skill["id"].(string); ok {
+		auditFromContext(r.Context()).addEntity(id)
+	}
+	categoryID := chi.URLParam(r, "id")
+	h.writeSkill(w, r, http.StatusCreated, categoryID, skill, createItem("skill", skill))
+}
+
+// PATCH /api/content/skills/{id}/skills/{skill}
+func (h *ContentHandler) UpdateSkill(w http.ResponseWriter, r *http.Request) {
+	if chi.URLParam(r, "section") != entity.SectionSkills {
+		respondProblem(w, r, http.StatusNotFound, "Unknown section")
+		return
+	}
+
+	var patch map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		respondProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	categoryID, skillID := chi.URLParam(r, "id"), chi.URLParam(r, "skill")
+	h.writeSkill(w, r, http.StatusOK, categoryID, map[string]interface{}{"id": skillID}, patchItem("skill", skillID, patch))
+}
+
+// DELETE /api/content/skills/{id}/skills/{skill}
+func (h *ContentHandler) DeleteSkill(w http.ResponseWriter, r *http.Request) {
+	if chi.URLParam(r, "section") != entity.SectionSkills {
+		respondProblem(w, r, http.StatusNotFound, "Unknown section")
+		return
+	}
+
+	categoryID, skillID := chi.URLParam(r, "id"), chi.URLParam(r, "skill")
+	edit := inCategory(categoryID, deleteItem("skill", skillID))
+	if _, err := h.editItems(w, r, entity.SectionSkills, []string{categoryID, skillID}, edit); err != nil {
+		respondError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// POST /api/content/skills/{id}/skills/reorder
+func (h *ContentHandler) ReorderSkills(w http.ResponseWriter, r *http.Request) {
+	if chi.URLParam(r, "section") != entity.SectionSkills {
+		respondProblem(w, r, http.StatusNotFound, "Unknown section")
+		return
+	}
+
+	var req reorderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	categoryID := chi.URLParam(r, "id")
+	categories, err := h.editItems(w, r, entity.SectionSkills, []string{categoryID}, inCategory(categoryID, reorderItems(req.IDs)))
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, categories[categories.index(categoryID)]["skills"])
+}
+
+func (h *ContentHandler) listSection(w http.ResponseWriter, r *http.Request) (string, bool) {
+	section := chi.URLParam(r, "section")
+	if _, ok := itemKinds[section]; !ok {
+		respondProblem(w, r, http.StatusNotFound, "Unknown section")
+		return "", false
+	}
+	return section, true
+}
+
+// writeItem applies edit to a section and responds with the stored item
+func (h *ContentHandler) writeItem(w http.ResponseWriter, r *http.Request, status int, section string, item map[string]interface{}, edit func(itemList) (itemList, error)) {
+	id, _ := item["id"].(string)
+	items, err := h.editItems(w, r, section, []string{id}, edit)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, status, items[items.index(id)])
+}
+
+// writeSkill applies edit to the skills of one category and responds with
+// the stored skill
+func (h *ContentHandler) writeSkill(w http.ResponseWriter, r *http.Request, status int, categoryID string, skill map[string]interface{}, edit func(itemList) (itemList, error)) {
+	id, _ := skill["id"].(string)
+	categories, err := h.editItems(w, r, entity.SectionSkills, []string{categoryID, id}, inCategory(categoryID, edit))
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	skills, err := toItemList(categories[categories.index(categoryID)]["skills"])
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	respondJSON(w, status, skills[skills.index(id)])
+}
+
+// maxEditAttempts bounds how often editItems applies an edit again after
+// another replica saved the section first
+const maxEditAttempts = 3
+
+// editItems applies edit to the editable state of a list section (its
+// draft, or the published items) and saves the result as the new draft.
+// path names the item the request is about, as for itemTag: If-Match may
+// be its tag instead of the one of the section, and the response carries
+// its new tag. Edits are serialized so concurrent changes to different
+// items of the same section do not overwrite each other; when another
+// replica saves the section in between, the edit is applied again to the
+// new state as long as If-Match still holds. The stored items are returned.
+func (h *ContentHandler) editItems(w http.ResponseWriter, r *http.Request, section string, path []string, edit func(itemList) (itemList, error)) (itemList, error) {
+	ctx := context.WithoutCancel(r.Context())
+
+	h.editMu.Lock()
+	defer h.editMu.Unlock()
+
+	for attempt := 1; ; attempt++ {
+		items, err := h.tryEditItems(ctx, w, r, section, path, edit)
+		if !errors.Is(err, repository.ErrStale) || attempt == maxEditAttempts {
+			return items, err
+		}
+	}
+}
+
+// tryEditItems is one attempt of editItems. Caller must hold h.editMu.
+func (h *ContentHandler) tryEditItems(ctx context.Context, w http.ResponseWriter, r *http.Request, section string, path []string, edit func(itemList) (itemList, error)) (itemList, error) {
+	st, err := h.editState(ctx, section)
+	if err != nil {
+		return nil, err
+	}
+	var current entity.SiteContent
+	if err := current.SetSection(section, st.data); err != nil {
+		return nil, err
+	}
+	if err := checkIfMatch(r, r.Method == http.MethodPatch, st.tag, itemTag(&current, section, path...)); err != nil {
+		return nil, err
+	}
+
+	var items itemList
+	if err := json.Unmarshal(st.data, &items); err != nil {
+		return nil, err
+	}
+
+	items, err = edit(items)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i]["order"] = i + 1
+	}
+
+	// Round-trip through the entity types to reject values of the wrong type
+	// and drop unknown fields
+	data, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	var content entity.SiteContent
+	if err := content.SetSection(section, data); err != nil {
+		return nil, fmt.Errorf("%w: %v", repository.ErrInvalid, err)
+	}
+	typed, err := content.Section(section)
+	if err != nil {
+		return nil, err
+	}
+	// Pointers of broken rules are relative to the whole list, so they name
+	// the position of the item as well
+	if err := h.validator.Validate(typed); err != nil {
+		return nil, err
+	}
+	if data, err = json.Marshal(typed); err != nil {
+		return nil, err
+	}
+
+	if err := h.saveDraft(ctx, section, st, actorFromContext(r.Context()), "", data); err != nil {
+		return nil, err
+	}
+	if tag := itemTag(&content, section, path...); tag != "" {
+		w.Header().Set("ETag", tag)
+	} else {
+		setWrittenETag(w, st, section, data)
+	}
+
+	var stored itemList
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, err
+	}
+	return stored, nil
+}
+
+// itemTag returns the ETag of one item of c named by path: the id of a
+// project, skill category or contact, or a category id and a skill id. It
+// leaves out the order, so moving or removing other items keeps it. "" if
+// there is no such item.
+func itemTag(c *entity.SiteContent, section string, path ...string) string {
+	if len(path) == 0 {
+		return ""
+	}
+
+	var item interface{}
+	switch section {
+	case entity.SectionProjects:
+		for _, p := range c.Projects {
+			if p.ID == path[0] {
+				p.Order = 0
+				item = p
+			}
+		}
+	case entity.SectionSkills:
+		for _, cat := range c.Skills {
+			if cat.ID != path[0] {
+				continue
+			}
+			if len(path) == 1 {
+				cat.Order = 0
+				item = cat
+				break
+			}
+			for _, skill := range cat.Skills {
+				if skill.ID == path[1] {
+					item = skill
+				}
+			}
+		}
+	case entity.SectionContacts:
+		for _, contact := range c.Contacts {
+			if contact.ID == path[0] {
+				contact.Order = 0
+				item = contact
+			}
+		}
+	}
+	if item == nil {
+		return ""
+	}
+
+	tag, err := computeETag(section, path, item)
+	if err != nil {
+		return ""
+	}
+	return tag
+}
+
+func createItem(kind string, item map[string]interface{}) func(itemList) (itemList, error) {
+	return func(items itemList) (itemList, error) {
+		id, _ := item["id"].(string)
+		if id == "" {
+			return nil, validate.Errors{{Pointer: "/id", Detail: "is required"}}
+		}
+		if items.index(id) >= 0 {
+			return nil, fmt.Errorf("%s %q already exists: %w", kind, id, repository.ErrConflict)
+		}
+		return append(items, item), nil
+	}
+}
+
+func patchItem(kind, id string, patch map[string]interface{}) func(itemList) (itemList, error) {
+	return func(items itemList) (itemList, error) {
+		i := items.index(id)
+		if i < 0 {
+			return nil, fmt.Errorf("%s %q: %w", kind, id, repository.ErrNotFound)
+		}
+
+		merged := mergePatch(items[i], patch)
+		if newID, _ := merged["id"].(string); newID != id {
+			return nil, fmt.Errorf("%w: %s id cannot be changed", repository.ErrInvalid, kind)
+		}
+		items[i] = merged
+		return items, nil
+	}
+}
+
+func deleteItem(kind, id string) func(itemList) (itemList, error) {
+	return func(items itemList) (itemList, error) {
+		i := items.index(id)
+		if i < 0 {
+			return nil, fmt.Errorf("%s %q: %w", kind, id, repository.ErrNotFound)
+		}
+		return append(items[:i], items[i+1:]...), nil
+	}
+}
+
+// reorderItems puts the items in the order of ids, which must list every
+// existing id exactly once
+func reorderItems(ids []string) func(itemList) (itemList, error) {
+	return func(items itemList) (itemList, error) {
+		if len(ids) != len(items) {
+			return nil, fmt.Errorf("%w: reorder must list all %d ids", repository.ErrInvalid, len(items))
+		}
+
+		reordered := make(itemList, 0, len(items))
+		seen := make(map[string]bool, len(ids))
+		for _, id := range ids {
+			i := items.index(id)
+			if i < 0 || seen[id] {
+				return nil, fmt.Errorf("%w: unknown or repeated id %q", repository.ErrInvalid, id)
+			}
+			seen[id] = true
+			reordered = append(reordered, items[i])
+		}
+		return reordered, nil
+	}
+}
+
+// inCategory applies edit to the skills of one skill category
+func inCategory(categoryID string, edit func(itemList) (itemList, error)) func(itemList) (itemList, error) {
+	return func(categories itemList) (itemList, error) {
+		i := categories.index(categoryID)
+		if i < 0 {
+			return nil, fmt.Errorf("skill category %q: %w", categoryID, repository.ErrNotFound)
+		}
+
+		skills, err := toItemList(categories[i]["skills"])
+		if err != nil {
+			return nil, err
+		}
+		if skills, err = edit(skills); err != nil {
+			return nil, err
+		}
+		categories[i]["skills"] = skills
+		return categories, nil
+	}
+}
+
+func toItemList(v interface{}) (itemList, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var items itemList
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// mergePatch applies an RFC 7396 JSON merge patch: objects are merged
+// recursively and null removes a field
+func mergePatch(target, patch map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(target))
+	for k, v := range target {
+		merged[k] = v
+	}
+	for k, v := range patch {
+		if v == nil {
+			delete(merged, k)
+			continue
+		}
+		if p, ok := v.(map[string]interface{}); ok {
+			t, _ := merged[k].(map[string]interface{})
+			merged[k] = mergePatch(t, p)
+			continue
+		}
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"server/internal/entity"
+	"server/internal/locale"
+	"server/internal/preview"
+	"server/internal/repository"
+	"server/internal/validate"
+)
+
+func TestItemTags(t *testing.T) {
+	h := newTestContentRouter(t)
+	tag := func(path string) string {
+		t.Helper()
+		return serve(t, h, http.MethodGet, path, "").Header().Get("ETag")
+	}
+	portfolio, oll := tag("/content/projects/portfolio"), tag("/content/projects/oll")
+	if portfolio == oll || portfolio == tag("/content/projects") {
+		t.Fatalf("item tags are not per item: %q, %q", portfolio, oll)
+	}
+
+	// Edits of different items, each with the tag of its item, do not collide
+	first := serve(t, h, http.MethodPatch, "/content/projects/portfolio", `{"featured": false}`, "If-Match", portfolio)
+	if first.Code != http.StatusOK {
+		t.Fatalf("PATCH portfolio = %d, want 200: %s", first.Code, first.Body)
+	}
+	second := serve(t, h, http.MethodPatch, "/content/projects/oll", `{"featured": false}`, "If-Match", oll)
+	if second.Code != http.StatusOK {
+		t.Fatalf("PATCH oll after portfolio = %d, want 200: %s", second.Code, second.Body)
+	}
+
+	// The tag of a changed item is stale; the one of the response is not
+	if rec := serve(t, h, http.MethodPatch, "/content/projects/portfolio", `{"url": "https://example.com"}`, "If-Match", portfolio); rec.Code != http.StatusPreconditionFailed {
+		t.Errorf("PATCH with the tag of the old item = %d, want 412", rec.Code)
+	}
+	if rec := serve(t, h, http.MethodPatch, "/content/projects/portfolio", `{"featured": true}`, "If-Match", first.Header().Get("ETag")); rec.Code != http.StatusOK {
+		t.Errorf("PATCH with the tag of the response = %d, want 200: %s", rec.Code, rec.Body)
+	}
+
+	// Moving items does not change their tags
+	if rec := serve(t, h, http.MethodPost, "/content/projects/reorder", `{"ids": ["oll", "portfolio"]}`); rec.Code != http.StatusOK {
+		t.Fatalf("reorder = %d: %s", rec.Code, rec.Body)
+	}
+	if rec := serve(t, h, http.MethodDelete, "/content/projects/oll", "", "If-Match", oll); rec.Code != http.StatusPreconditionFailed {
+		t.Errorf("DELETE with the tag of the old item = %d, want 412", rec.Code)
+	}
+	if rec := serve(t, h, http.MethodDelete, "/content/projects/oll", "", "If-Match", second.Header().Get("ETag")); rec.Code != http.StatusNoContent {
+		t.Errorf("DELETE after reorder = %d, want 204: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestSkillTags(t *testing.T) {
+	h := newTestContentRouter(t)
+	goTag := serve(t, h, http.MethodGet, "/content/skills/backend/skills/go", "").Header().Get("ETag")
+	reactTag := serve(t, h, http.MethodGet, "/content/skills/frontend/skills/react", "").Header().Get("ETag")
+
+	if rec := serve(t, h, http.MethodPatch, "/content/skills/frontend/skills/react", `{"name": "React 19"}`, "If-Match", reactTag); rec.Code != http.StatusOK {
+		t.Fatalf("PATCH react = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	if rec := serve(t, h, http.MethodPatch, "/content/skills/backend/skills/go", `{"name": "Golang"}`, "If-Match", goTag); rec.Code != http.StatusOK {
+		t.Errorf("PATCH go after react = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	if rec := serve(t, h, http.MethodPatch, "/content/skills/frontend/skills/react", `{"name": "React 20"}`, "If-Match", reactTag); rec.Code != http.StatusPreconditionFailed {
+		t.Errorf("PATCH with the tag of the old skill = %d, want 412", rec.Code)
+	}
+}
+
+func TestItemEditRetriesAfterAnotherReplica(t *testing.T) {
+	repo := repository.NewMemoryRepository(locale.Default)
+	store := &racingDrafts{MemoryRepository: repo}
+	h := NewContentHandler(repo, repo, store,
+		preview.NewSigner([]byte("test-secret"), time.Hour),
+		locale.NewNegotiator(locale.Default, nil),
+		validate.New(locale.Default),
+		CachePolicy{MaxAge: time.Minute, Refresh: time.Hour},
+	)
+	r := chi.NewRouter()
+	r.Get("/content/{section}/{id}", h.GetItem)
+	r.Patch("/content/{section}/{id}", h.UpdateItem)
+
+	race := func(id, title string) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			content, err := repo.GetEditable(ctx)
+			if err != nil {
+				return err
+			}
+			projects := content.Projects
+			if draft, err := repo.GetDraft(ctx, entity.SectionProjects); err == nil {
+				if err := json.Unmarshal(draft.Data, &projects); err != nil {
+					return err
+				}
+			}
+			for i := range projects {
+				if projects[i].ID == id {
+					projects[i].Title["en"] = title
+				}
+			}
+			return saveOther(ctx, repo, entity.SectionProjects, projects)
+		}
+	}
+
+	// Another replica edits a different item: the edit is applied again on
+	// top of it
+	etag := serve(t, r, http.MethodGet, "/content/projects/portfolio", "").Header().Get("ETag")
+	store.race = race("oll", "Other")
+	rec := serve(t, r, http.MethodPatch, "/content/projects/portfolio", `{"featured": false}`, "If-Match", etag)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("PATCH = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	draft, err := repo.GetDraft(context.Background(), entity.SectionProjects)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var projects []entity.Project
+	if err := json.Unmarshal(draft.Data, &projects); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range projects {
+		if p.ID == "oll" && p.Title["en"] != "Other" {
+			t.Errorf("the other write was lost: %v", p.Title)
+		}
+		if p.ID == "portfolio" && p.Featured {
+			t.Error("the edit was lost")
+		}
+	}
+
+	// Another replica edits the same item: If-Match no longer holds
+	etag = rec.Header().Get("ETag")
+	store.race = race("portfolio", "Other")
+	if rec := serve(t, r, http.MethodPatch, "/content/projects/portfolio", `{"featured": true}`, "If-Match", etag); rec.Code != http.StatusPreconditionFailed {
+		t.Errorf("PATCH over a change of the same item = %d, want 412: %s", rec.Code, rec.Body)
+	}
+	if draft, err := repo.GetDraft(context.Background(), entity.SectionProjects); err != nil || !strings.Contains(string(draft.Data), `"featured":false`) {
+		t.Errorf("rejected write changed the draft: %s %v", draft.Data, err)
+	}
+}
@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"net/http"
+
+	"server/internal/entity"
+)
+
+// translator picks the locale of a content read. ?lang=uk (or uk-UA)
+// flattens localized fields to Ukrainian. Without ?lang (or with
+// ?lang=auto) the locale is negotiated from Accept-Language, and a request
+// without that header keeps every locale in a map. ?lang=all always keeps
+// the maps; the site client asks for it, since browsers send
+// Accept-Language on every request. Missing translations follow the
+// fallback chain.
+func (h *ContentHandler) translator(w http.ResponseWriter, r *http.Request) (entity.Translate, bool) {
+	lang := r.URL.Query().Get("lang")
+	if lang == "all" {
+		return nil, false
+	}
+	if lang == "" || lang == "auto" {
+		w.Header().Add("Vary", "Accept-Language")
+		lang = r.Header.Get("Accept-Language")
+		if lang == "" {
+			return nil, false
+		}
+	}
+
+	tag := h.locales.Negotiate(lang)
+	w.Header().Set("Content-Language", tag)
+	return h.locales.Translator(tag), true
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestContentLanguage(t *testing.T) {
+	h := newTestContentRouter(t)
+
+	tests := []struct {
+		name     string
+		path     string
+		header   string
+		wantLang string
+		wantVary bool
+	}{
+		{name: "no lang, no header", path: "/content/about", wantVary: true},
+		{name: "no lang", path: "/content/about", header: "en-US,en;q=0.9", wantLang: "en", wantVary: true},
+		{name: "unsupported header", path: "/content/about", header: "de", wantLang: "ru", wantVary: true},
+		{name: "auto", path: "/content/about?lang=auto", header: "en", wantLang: "en", wantVary: true},
+		{name: "explicit lang", path: "/content/about?lang=en", header: "ru", wantLang: "en"},
+		{name: "all", path: "/content/about?lang=all", header: "en"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var headers []string
+			if tt.header != "" {
+				headers = []string{"Accept-Language", tt.header}
+			}
+			rec := serve(t, h, http.MethodGet, tt.path, "", headers...)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("GET = %d: %s", rec.Code, rec.Body)
+			}
+			if got := rec.Header().Get("Content-Language"); got != tt.wantLang {
+				t.Errorf("Content-Language = %q, want %q", got, tt.wantLang)
+			}
+			if got := rec.Header().Get("Vary") == "Accept-Language"; got != tt.wantVary {
+				t.Errorf("Vary = %q", rec.Header().Get("Vary"))
+			}
+
+			var about struct {
+				Name json.RawMessage `json:"name"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &about); err != nil {
+				t.Fatal(err)
+			}
+			flat := about.Name[0] == '"'
+			if flat != (tt.wantLang != "") {
+				t.Errorf("name = %s, want flattened %v", about.Name, tt.wantLang != "")
+			}
+		})
+	}
+}
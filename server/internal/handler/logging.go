@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel/trace"
+
+	"server/internal/logging"
+)
+
+// requestLog collects the access log fields that are only known further
+// down, the way auditRecord does for the audit log
+type requestLog struct {
+	actor string
+}
+
+// setActor names who made the request in the access and audit logs
+func setActor(ctx context.Context, actor string) {
+	auditFromContext(ctx).setActor(actor)
+	if l, ok := ctx.Value(logKey).(*requestLog); ok {
+		l.actor = actor
+	}
+}
+
+// RequestLogger puts a logger with the request id, and the trace id when
+// there is one, into the context of every request and writes one access
+// log line when the response is sent. It goes after RequestID, RealIP and
+// the tracing middleware; server errors are logged at error level.
+func RequestLogger(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			reqLogger := logger.With("request_id", middleware.GetReqID(r.Context()))
+			if sc := trace.SpanContextFromContext(r.Context()); sc.IsValid() {
+				reqLogger = reqLogger.With("trace_id", sc.TraceID().String())
+			}
+			rec := &requestLog{actor: "anonymous"}
+			ctx := logging.WithContext(context.WithValue(r.Context(), logKey, rec), reqLogger)
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			route := ""
+			if rctx := chi.RouteContext(r.Context()); rctx != nil {
+				route = rctx.RoutePattern()
+			}
+			level := slog.LevelInfo
+			if status >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+			reqLogger.LogAttrs(ctx, level, "Request",
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.String("route", route),
+				slog.Int("status", status),
+				slog.Int("bytes", ww.BytesWritten()),
+				slog.Duration("duration", time.Since(start)),
+				slog.String("ip", ClientIP(r)),
+				slog.String("actor", rec.actor),
+			)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"server/internal/logging"
+)
+
+func TestRequestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	r.Use(RequestLogger(logging.New(&buf, logging.JSON, nil)))
+	r.Post("/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		setActor(r.Context(), "alice")
+		logging.FromContext(r.Context()).Info("Inside")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("hello"))
+	})
+	r.Get("/fail", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	serve(t, r, http.MethodPost, "/items/42", "")
+	serve(t, r, http.MethodGet, "/fail", "")
+
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("line %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("logged %d lines, want 3: %s", len(entries), buf.String())
+	}
+
+	inside, access, failed := entries[0], entries[1], entries[2]
+	if inside["msg"] != "Inside" || inside["request_id"] == nil || inside["request_id"] != access["request_id"] {
+		t.Errorf("handler line %v does not share the request id of %v", inside, access)
+	}
+	want := map[string]interface{}{
+		"level":  "INFO",
+		"msg":    "Request",
+		"method": "POST",
+		"path":   "/items/42",
+		"route":  "/items/{id}",
+		"status": float64(http.StatusCreated),
+		"bytes":  float64(len("hello")),
+		"actor":  "alice",
+	}
+	for k, v := range want {
+		if access[k] != v {
+			t.Errorf("access log %s = %v, want %v", k, access[k], v)
+		}
+	}
+	if _, ok := access["duration"]; !ok {
+		t.Error("access log misses duration")
+	}
+	if failed["level"] != "ERROR" || failed["actor"] != "anonymous" || failed["request_id"] == access["request_id"] {
+		t.Errorf("server error line = %v", failed)
+	}
+}
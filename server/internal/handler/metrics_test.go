@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestRequireToken(t *testing.T) {
+	h := RequireToken("metrics-token")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{name: "token", header: "Bearer metrics-token", want: http.StatusOK},
+		{name: "no header", want: http.StatusUnauthorized},
+		{name: "wrong token", header: "Bearer other", want: http.StatusUnauthorized},
+		{name: "prefix of the token", header: "Bearer metrics", want: http.StatusUnauthorized},
+		{name: "not bearer", header: "Basic metrics-token", want: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var headers []string
+			if tt.header != "" {
+				headers = []string{"Authorization", tt.header}
+			}
+			rec := serve(t, h, http.MethodGet, "/metrics", "", headers...)
+			if rec.Code != tt.want {
+				t.Errorf("GET /metrics = %d, want %d", rec.Code, tt.want)
+			}
+			if tt.want == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Error("401 without WWW-Authenticate")
+			}
+		})
+	}
+}
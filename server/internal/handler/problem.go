@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+
+	"server/internal/logging"
+	"server/internal/repository"
+	"server/internal/validate"
+)
+
+// problem is an RFC 7807 error response. Validation failures list every
+// broken rule in Errors, each with a JSON pointer into the request body.
+type problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// RequestID matches the server log line of the failure
+	RequestID string          `json:"requestId,omitempty"`
+	Errors    validate.Errors `json:"errors,omitempty"`
+}
+
+// respondError maps err to a status code. Repository and precondition
+// errors carry messages meant for the client; anything else is logged with
+// the request id and answered with a generic 500, so database details never
+// reach the response.
+func respondError(w http.ResponseWriter, r *http.Request, err error) {
+	if validationError(w, r, err) {
+		return
+	}
+	var cerr *repository.ConstraintError
+	if errors.As(err, &cerr) {
+		respondConstraint(w, r, err, cerr)
+		return
+	}
+	switch {
+	case errors.Is(err, repository.ErrInvalid):
+		respondProblem(w, r, http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, repository.ErrConflict):
+		respondProblem(w, r, http.StatusConflict, err.Error())
+	case errors.Is(err, repository.ErrNotFound):
+		respondProblem(w, r, http.StatusNotFound, err.Error())
+	case errors.Is(err, errPreconditionFailed):
+		respondProblem(w, r, http.StatusPreconditionFailed, err.Error())
+	case errors.Is(err, repository.ErrStale):
+		respondProblem(w, r, http.StatusPreconditionFailed, errPreconditionFailed.Error())
+	case errors.Is(err, errPreconditionRequired):
+		respondProblem(w, r, http.StatusPreconditionRequired, err.Error())
+	default:
+		logging.FromContext(r.Context()).Error("Request failed", "method", r.Method, "path", r.URL.Path, "err", err)
+		respondProblem(w, r, http.StatusInternalServerError, "Internal server error")
+	}
+}
+
+// problemOTPRequired tells the client to ask for a one-time code and send
+// the login again
+const problemOTPRequired = "urn:portfolio:problem:otp-required"
+
+// respondProblem replaces http.Error for every failure response
+func respondProblem(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	respondProblemType(w, r, "about:blank", status, detail)
+}
+
+// respondProblemType is respondProblem for failures the client has to tell
+// apart from others with the same status
+func respondProblemType(w http.ResponseWriter, r *http.Request, typ string, status int, detail string) {
+	writeProblem(w, r, problem{
+		Type:     typ,
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: r.URL.Path,
+	})
+}
+
+// respondInvalid reports a body that failed validation as 422
+func respondInvalid(w http.ResponseWriter, r *http.Request, errs validate.Errors) {
+	writeProblem(w, r, problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(http.StatusUnprocessableEntity),
+		Status:   http.StatusUnprocessableEntity,
+		Detail:   "The request body has invalid fields",
+		Instance: r.URL.Path,
+		Errors:   errs,
+	})
+}
+
+// respondConstraint reports a violated schema constraint, pointing at the
+// request field when the constraint guards one. The constraint name is
+// logged, not sent.
+func respondConstraint(w http.ResponseWriter, r *http.Request, err error, cerr *repository.ConstraintError) {
+	logging.FromContext(r.Context()).Info("Constraint violated", "method", r.Method, "path", r.URL.Path, "constraint", cerr.Constraint)
+
+	status := http.StatusUnprocessableEntity
+	if errors.Is(cerr.Kind, repository.ErrConflict) {
+		status = http.StatusConflict
+	}
+	p := problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   err.Error(),
+		Instance: r.URL.Path,
+	}
+	if cerr.Field != "" {
+		p.Errors = validate.Errors{{Pointer: "/" + cerr.Field, Detail: cerr.Message}}
+	}
+	writeProblem(w, r, p)
+}
+
+func writeProblem(w http.ResponseWriter, r *http.Request, p problem) {
+	p.RequestID = middleware.GetReqID(r.Context())
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.Header().Del("ETag")
+	w.Header().Del("Last-Modified")
+	w.Header().Del("Cache-Control")
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// validationError reports whether err is a validation failure and writes
+// the 422 response if so
+func validationError(w http.ResponseWriter, r *http.Request, err error) bool {
+	var errs validate.Errors
+	if !errors.As(err, &errs) {
+		return false
+	}
+	respondInvalid(w, r, errs)
+	return true
+}
+
+// NotFound and MethodNotAllowed give router errors the problem format too
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	respondProblem(w, r, http.StatusNotFound, "No such endpoint")
+}
+
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	respondProblem(w, r, http.StatusMethodNotAllowed, r.Method+" is not allowed here")
+}
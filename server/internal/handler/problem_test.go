@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5/middleware"
+
+	"server/internal/repository"
+	"server/internal/validate"
+)
+
+func TestRespondError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantDetail string
+		wantErrors validate.Errors
+	}{
+		{
+			name:       "not found",
+			err:        fmt.Errorf("about: %w", repository.ErrNotFound),
+			wantStatus: http.StatusNotFound,
+			wantDetail: "about: not found",
+		},
+		{
+			name:       "conflict",
+			err:        fmt.Errorf("project %q already exists: %w", "a", repository.ErrConflict),
+			wantStatus: http.StatusConflict,
+			wantDetail: `project "a" already exists: conflicting update`,
+		},
+		{
+			name:       "duplicate id",
+			err:        &repository.DuplicateIDError{Kind: "project", ID: "a"},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantDetail: `duplicate project id "a"`,
+		},
+		{
+			name:       "stale version",
+			err:        fmt.Errorf("about: %w", repository.ErrStale),
+			wantStatus: http.StatusPreconditionFailed,
+			wantDetail: errPreconditionFailed.Error(),
+		},
+		{
+			name:       "precondition required",
+			err:        errPreconditionRequired,
+			wantStatus: http.StatusPreconditionRequired,
+			wantDetail: errPreconditionRequired.Error(),
+		},
+		{
+			name: "constraint with a field",
+			err: &repository.ConstraintError{
+				Kind: repository.ErrConflict, Field: "username", Message: "username is taken", Constraint: "admin_users_username_key",
+			},
+			wantStatus: http.StatusConflict,
+			wantDetail: "conflicting update: username is taken",
+			wantErrors: validate.Errors{{Pointer: "/username", Detail: "username is taken"}},
+		},
+		{
+			name:       "validation",
+			err:        validate.Errors{{Pointer: "/id", Detail: "is required"}},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantDetail: "The request body has invalid fields",
+			wantErrors: validate.Errors{{Pointer: "/id", Detail: "is required"}},
+		},
+		{
+			name:       "internal",
+			err:        errors.New(`ERROR: relation "page_views" does not exist (SQLSTATE 42P01)`),
+			wantStatus: http.StatusInternalServerError,
+			wantDetail: "Internal server error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/content/about", nil)
+			r = r.WithContext(context.WithValue(r.Context(), middleware.RequestIDKey, "req-1"))
+			rec := httptest.NewRecorder()
+			respondError(rec, r, tt.err)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json" {
+				t.Errorf("Content-Type = %q", ct)
+			}
+			var p problem
+			if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+				t.Fatal(err)
+			}
+			if p.Status != tt.wantStatus || p.Detail != tt.wantDetail {
+				t.Errorf("problem = %d %q, want %d %q", p.Status, p.Detail, tt.wantStatus, tt.wantDetail)
+			}
+			if !reflect.DeepEqual(p.Errors, tt.wantErrors) {
+				t.Errorf("errors = %v, want %v", p.Errors, tt.wantErrors)
+			}
+			if p.RequestID != "req-1" || p.Instance != "/api/content/about" {
+				t.Errorf("requestId = %q, instance = %q", p.RequestID, p.Instance)
+			}
+		})
+	}
+}
+
+func TestRespondErrorHidesInternalDetails(t *testing.T) {
+	cerr := &repository.ConstraintError{Kind: repository.ErrInvalid, Message: "a value is not allowed", Constraint: "projects_order_check"}
+	for _, err := range []error{
+		errors.New(`ERROR: duplicate key value violates unique constraint "x" (SQLSTATE 23505)`),
+		cerr,
+	} {
+		rec := httptest.NewRecorder()
+		respondError(rec, httptest.NewRequest(http.MethodPost, "/api/analytics/track", nil), err)
+		for _, leak := range []string{"SQLSTATE", "projects_order_check"} {
+			if strings.Contains(rec.Body.String(), leak) {
+				t.Errorf("response to %v contains %q: %s", err, leak, rec.Body)
+			}
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"server/internal/logging"
+	"server/internal/ratelimit"
+)
+
+// maxTrackBody caps how much of a tracking request is read to find the
+// visitor id
+const maxTrackBody = 64 << 10
+
+// RateLimitKey picks the bucket of a request; "" lets it through unlimited
+type RateLimitKey func(r *http.Request) string
+
+// RateLimit allows the current limit of requests per key of the named
+// route group; limit is read on every request so it can be reloaded. Every
+// response carries RateLimit-* headers; a request over the limit gets 429
+// with Retry-After. If the store fails the request is let through, so a
+// database hiccup does not take the site down with it.
+func RateLimit(store ratelimit.Store, name string, limit func() ratelimit.Limit, key RateLimitKey) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limit := limit()
+			if !limit.Enabled() {
+				next.ServeHTTP(w, r)
+				return
+			}
+			k := key(r)
+			if k == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			res, err := store.TakeToken(r.Context(), name+":"+k, limit, time.Now())
+			if err != nil {
+				logging.FromContext(r.Context()).Error("Rate limit store failed", "limit", name, "err", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h := w.Header()
+			h.Set("RateLimit-Policy", strconv.Itoa(limit.Requests)+";w="+strconv.Itoa(int(math.Ceil(limit.Per.Seconds()))))
+			h.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+			h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+			if !res.Allowed {
+				respondTooManyRequests(w, r, res.RetryAfter, "Rate limit exceeded, try again later")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ClientIP keys a request by the address of the client. Behind a proxy
+// it is only meaningful with middleware.RealIP in front.
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// VisitorID keys a tracking request by its visitor_id. The body is read
+// here and put back for the handler.
+func VisitorID(r *http.Request) string {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxTrackBody))
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return ""
+	}
+
+	var req struct {
+		VisitorID string `json:"visitor_id"`
+	}
+	if json.Unmarshal(body, &req) != nil {
+		return ""
+	}
+	return req.VisitorID
+}
+
+// respondTooManyRequests answers 429 with Retry-After in whole seconds
+func respondTooManyRequests(w http.ResponseWriter, r *http.Request, retryAfter time.Duration, detail string) {
+	w.Header().Set("Retry-After", strconv.Itoa(max(ceilSeconds(retryAfter), 1)))
+	respondProblem(w, r, http.StatusTooManyRequests, detail)
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
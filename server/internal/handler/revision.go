@@ -0,0 +1,242 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"server/internal/entity"
+	"server/internal/logging"
+	"server/internal/repository"
+)
+
+const (
+	defaultRevisionLimit = 50
+	maxRevisionLimit     = 500
+)
+
+// GET /api/content/{section}/revisions
+func (h *ContentHandler) ListRevisions(w http.ResponseWriter, r *http.Request) {
+	section := chi.URLParam(r, "section")
+	if !entity.IsSection(section) {
+		respondProblem(w, r, http.StatusNotFound, "Unknown section")
+		return
+	}
+
+	limit := defaultRevisionLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			respondProblem(w, r, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+		limit = min(n, maxRevisionLimit)
+	}
+
+	revisions, err := h.revisions.ListRevisions(r.Context(), section, limit)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, revisions)
+}
+
+// GET /api/content/{section}/revisions/{id}
+func (h *ContentHandler) GetRevision(w http.ResponseWriter, r *http.Request) {
+	rev, ok := h.findRevision(w, r)
+	if !ok {
+		return
+	}
+	respondJSON(w, http.StatusOK, rev)
+}
+
+// POST /api/content/{section}/revisions/{id}/restore - the snapshot becomes
+// the new draft. Like PUT, it replaces the whole section and must send
+// If-Match.
+func (h *ContentHandler) RestoreRevision(w http.ResponseWriter, r *http.Request) {
+	rev, ok := h.findRevision(w, r)
+	if !ok {
+		return
+	}
+
+	summary := fmt.Sprintf("restored revision #%d", rev.ID)
+	if err := h.replaceSection(w, r, rev.Section, summary, rev.Snapshot); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(rev.Snapshot)
+}
+
+func (h *ContentHandler) findRevision(w http.ResponseWriter, r *http.Request) (entity.ContentRevision, bool) {
+	section := chi.URLParam(r, "section")
+	if !entity.IsSection(section) {
+		respondProblem(w, r, http.StatusNotFound, "Unknown section")
+		return entity.ContentRevision{}, false
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondProblem(w, r, http.StatusBadRequest, "Invalid revision id")
+		return entity.ContentRevision{}, false
+	}
+
+	rev, err := h.revisions.GetRevision(r.Context(), section, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		respondProblem(w, r, http.StatusNotFound, "Revision not found")
+		return rev, false
+	}
+	if err != nil {
+		respondError(w, r, err)
+		return rev, false
+	}
+
+	return rev, true
+}
+
+// saveDraft stores data as the new draft of a section, if the section has
+// not changed since st was read, and records a revision of it. If the
+// section has no history yet, its previous state is saved first so the very
+// first overwrite can be undone too. An empty summary is computed from the
+// difference between the two states.
+func (h *ContentHandler) saveDraft(ctx context.Context, section string, st sectionState, author, summary string, data []byte) error {
+	before := st.data
+
+	draft := entity.ContentDraft{Section: section, Data: data, Author: author}
+	if err := h.drafts.SaveDraft(ctx, draft, st.version); err != nil {
+		return err
+	}
+	h.cache.drafted(section, st.version)
+	auditFromContext(ctx).setChange(before, data)
+
+	if summary == "" {
+		summary = summarizeChange(before, data)
+	}
+	h.recordRevision(ctx, section, author, summary, before, data)
+
+	return nil
+}
+
+// recordRevision stores the snapshot after a successful change. The change
+// itself already happened, so a lost revision is logged instead of failing
+// the request.
+func (h *ContentHandler) recordRevision(ctx context.Context, section, author, summary string, before, after []byte) {
+	existing, err := h.revisions.ListRevisions(ctx, section, 1)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to read revisions", "section", section, "err", err)
+		return
+	}
+	if len(existing) == 0 {
+		h.saveRevision(ctx, entity.ContentRevision{Section: section, Author: "system", Summary: "initial state", Snapshot: before})
+	}
+
+	h.saveRevision(ctx, entity.ContentRevision{Section: section, Author: author, Summary: summary, Snapshot: after})
+}
+
+func (h *ContentHandler) saveRevision(ctx context.Context, rev entity.ContentRevision) {
+	if _, err := h.revisions.CreateRevision(ctx, rev); err != nil {
+		logging.FromContext(ctx).Error("Failed to save revision", "section", rev.Section, "err", err)
+	}
+}
+
+// summarizeChange describes the difference between two section snapshots:
+// changed fields for an object, added/removed/changed ids for a list
+func summarizeChange(before, after []byte) string {
+	var beforeList, afterList []map[string]interface{}
+	if json.Unmarshal(before, &beforeList) == nil && json.Unmarshal(after, &afterList) == nil {
+		return summarizeList(beforeList, afterList)
+	}
+
+	var beforeObj, afterObj map[string]interface{}
+	if json.Unmarshal(before, &beforeObj) != nil || json.Unmarshal(after, &afterObj) != nil {
+		return "updated"
+	}
+
+	var changed []string
+	for key, value := range afterObj {
+		if key == "updated_at" {
+			continue
+		}
+		if !reflect.DeepEqual(beforeObj[key], value) {
+			changed = append(changed, key)
+		}
+	}
+	if len(changed) == 0 {
+		return "no changes"
+	}
+	sort.Strings(changed)
+	return "changed " + strings.Join(changed, ", ")
+}
+
+func summarizeList(before, after []map[string]interface{}) string {
+	itemID := func(item map[string]interface{}) string {
+		id, _ := item["id"].(string)
+		return id
+	}
+	// Position is tracked separately so a move is not reported as an edit
+	withoutOrder := func(item map[string]interface{}) map[string]interface{} {
+		clone := make(map[string]interface{}, len(item))
+		for k, v := range item {
+			if k != "order" {
+				clone[k] = v
+			}
+		}
+		return clone
+	}
+
+	old := make(map[string]map[string]interface{}, len(before))
+	var oldOrder []string
+	for _, item := range before {
+		old[itemID(item)] = item
+		oldOrder = append(oldOrder, itemID(item))
+	}
+
+	var added, changed, newOrder []string
+	seen := make(map[string]bool, len(after))
+	for _, item := range after {
+		id := itemID(item)
+		seen[id] = true
+		newOrder = append(newOrder, id)
+		prev, ok := old[id]
+		if !ok {
+			added = append(added, id)
+		} else if !reflect.DeepEqual(withoutOrder(prev), withoutOrder(item)) {
+			changed = append(changed, id)
+		}
+	}
+
+	var removed []string
+	for _, id := range oldOrder {
+		if !seen[id] {
+			removed = append(removed, id)
+		}
+	}
+
+	var parts []string
+	if len(added) > 0 {
+		parts = append(parts, "added "+strings.Join(added, ", "))
+	}
+	if len(removed) > 0 {
+		parts = append(parts, "removed "+strings.Join(removed, ", "))
+	}
+	if len(changed) > 0 {
+		parts = append(parts, "changed "+strings.Join(changed, ", "))
+	}
+	if len(added) == 0 && len(removed) == 0 && !reflect.DeepEqual(oldOrder, newOrder) {
+		parts = append(parts, "reordered")
+	}
+	if len(parts) == 0 {
+		return "no changes"
+	}
+	return strings.Join(parts, "; ")
+}
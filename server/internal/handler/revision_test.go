@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"server/internal/entity"
+)
+
+func revisions(t *testing.T, h http.Handler, section string) []entity.ContentRevision {
+	t.Helper()
+	rec := serve(t, h, http.MethodGet, "/content/"+section+"/revisions", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET revisions = %d: %s", rec.Code, rec.Body)
+	}
+	var revs []entity.ContentRevision
+	if err := json.Unmarshal(rec.Body.Bytes(), &revs); err != nil {
+		t.Fatal(err)
+	}
+	return revs
+}
+
+func TestRevisionHistory(t *testing.T) {
+	h := newTestContentRouter(t)
+	original, etags := drafts(t, h)
+
+	if rec := serve(t, h, http.MethodPut, "/content/about", editedAbout(t, h, "@edited"), "If-Match", etags["about"]); rec.Code != http.StatusOK {
+		t.Fatalf("PUT = %d: %s", rec.Code, rec.Body)
+	}
+
+	// The first change also records the state it replaced
+	revs := revisions(t, h, "about")
+	if len(revs) != 2 {
+		t.Fatalf("%d revisions, want 2", len(revs))
+	}
+	if revs[0].Summary != "changed username" || revs[1].Summary != "initial state" {
+		t.Errorf("summaries %q, %q", revs[0].Summary, revs[1].Summary)
+	}
+	if revs[0].Snapshot != nil {
+		t.Error("the list carries snapshots")
+	}
+	if got := revisions(t, h, "projects"); len(got) != 0 {
+		t.Errorf("projects have %d revisions", len(got))
+	}
+
+	initial := fmt.Sprintf("/content/about/revisions/%d", revs[1].ID)
+	rec := serve(t, h, http.MethodGet, initial, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET revision = %d", rec.Code)
+	}
+	var rev entity.ContentRevision
+	if err := json.Unmarshal(rec.Body.Bytes(), &rev); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(rev.Snapshot), "@edited") {
+		t.Error("initial snapshot has the edit")
+	}
+
+	_, etags = drafts(t, h)
+	rec = serve(t, h, http.MethodPost, initial+"/restore", "", "If-Match", etags["about"])
+	if rec.Code != http.StatusOK {
+		t.Fatalf("restore = %d: %s", rec.Code, rec.Body)
+	}
+	restored, after := drafts(t, h)
+	if strings.Contains(string(restored["about"]), "@edited") {
+		t.Error("restore kept the edit")
+	}
+	if rec.Header().Get("ETag") != after["about"] {
+		t.Errorf("restore ETag = %q, want %q", rec.Header().Get("ETag"), after["about"])
+	}
+	var before, now map[string]interface{}
+	json.Unmarshal(original["about"], &before)
+	json.Unmarshal(restored["about"], &now)
+	if before["username"] != now["username"] {
+		t.Errorf("restored username %v, want %v", now["username"], before["username"])
+	}
+
+	revs = revisions(t, h, "about")
+	if want := fmt.Sprintf("restored revision #%d", rev.ID); revs[0].Summary != want {
+		t.Errorf("summary %q, want %q", revs[0].Summary, want)
+	}
+}
+
+func TestRestoreChecksIfMatch(t *testing.T) {
+	h := newTestContentRouter(t)
+	_, etags := drafts(t, h)
+	if rec := serve(t, h, http.MethodPut, "/content/about", editedAbout(t, h, "@edited"), "If-Match", etags["about"]); rec.Code != http.StatusOK {
+		t.Fatalf("PUT = %d: %s", rec.Code, rec.Body)
+	}
+	revs := revisions(t, h, "about")
+	path := fmt.Sprintf("/content/about/revisions/%d/restore", revs[len(revs)-1].ID)
+
+	tests := []struct {
+		name    string
+		headers []string
+		want    int
+	}{
+		{name: "without If-Match", want: http.StatusPreconditionRequired},
+		// The tag from before the PUT: restoring would drop the edit
+		{name: "stale tag", headers: []string{"If-Match", etags["about"]}, want: http.StatusPreconditionFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, current := drafts(t, h)
+			if rec := serve(t, h, http.MethodPost, path, "", tt.headers...); rec.Code != tt.want {
+				t.Fatalf("restore = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			content, after := drafts(t, h)
+			if after["about"] != current["about"] || !strings.Contains(string(content["about"]), "@edited") {
+				t.Error("rejected restore changed the section")
+			}
+		})
+	}
+}
+
+func TestRevisionErrors(t *testing.T) {
+	h := newTestContentRouter(t)
+
+	tests := []struct {
+		method string
+		path   string
+		want   int
+	}{
+		{http.MethodGet, "/content/about/revisions?limit=0", http.StatusBadRequest},
+		{http.MethodGet, "/content/about/revisions?limit=x", http.StatusBadRequest},
+		{http.MethodGet, "/content/unknown/revisions", http.StatusNotFound},
+		{http.MethodGet, "/content/about/revisions/x", http.StatusBadRequest},
+		{http.MethodGet, "/content/about/revisions/99", http.StatusNotFound},
+		{http.MethodPost, "/content/about/revisions/99/restore", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		if rec := serve(t, h, tt.method, tt.path, ""); rec.Code != tt.want {
+			t.Errorf("%s %s = %d, want %d", tt.method, tt.path, rec.Code, tt.want)
+		}
+	}
+}
+
+func TestSummarizeChange(t *testing.T) {
+	tests := []struct {
+		name          string
+		before, after string
+		want          string
+	}{
+		{"object fields", `{"name":"a","bio":"x","updated_at":"1"}`, `{"name":"b","bio":"y","updated_at":"2"}`, "changed bio, name"},
+		{"object unchanged", `{"name":"a"}`, `{"name":"a"}`, "no changes"},
+		{"added and removed", `[{"id":"a"},{"id":"b"}]`, `[{"id":"b"},{"id":"c"}]`, "added c; removed a"},
+		{"changed item", `[{"id":"a","title":"x"}]`, `[{"id":"a","title":"y"}]`, "changed a"},
+		{"reordered", `[{"id":"a","order":1},{"id":"b","order":2}]`, `[{"id":"b","order":1},{"id":"a","order":2}]`, "reordered"},
+		{"list unchanged", `[{"id":"a"}]`, `[{"id":"a"}]`, "no changes"},
+		{"not JSON", `x`, `y`, "updated"},
+	}
+	for _, tt := range tests {
+		if got := summarizeChange([]byte(tt.before), []byte(tt.after)); got != tt.want {
+			t.Errorf("%s: summarizeChange() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
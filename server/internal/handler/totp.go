@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"server/internal/auth"
+	"server/internal/entity"
+	"server/internal/repository"
+	"server/internal/validate"
+)
+
+// errInvalidOTP is a wrong, expired or replayed one-time code
+var errInvalidOTP = errors.New("invalid one-time code")
+
+type totpSetupResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+type totpCodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type totpDisableRequest struct {
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+type recoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// POST /api/auth/totp/setup - start enrollment: a new secret and its
+// otpauth:// URI for the QR code. 2FA is not on until /enable confirms a
+// code.
+func (h *AuthHandler) SetupTOTP(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+	if user.TOTPEnabled {
+		respondProblem(w, r, http.StatusConflict, "Two-factor authentication is already on")
+		return
+	}
+
+	user.TOTPSecret = auth.NewTOTPSecret()
+	if err := h.users.UpdateAdminUserTOTP(r.Context(), user); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	respondJSON(w, http.StatusOK, totpSetupResponse{
+		Secret: user.TOTPSecret,
+		URI:    auth.TOTPURI(h.config.TOTPIssuer, user.Username, user.TOTPSecret),
+	})
+}
+
+// POST /api/auth/totp/enable - confirm enrollment with a code from the app.
+// The recovery codes are returned only here.
+func (h *AuthHandler) EnableTOTP(w http.ResponseWriter, r *http.Request) {
+	var req totpCodeRequest
+	if !h.decodeValid(w, r, &req) {
+		return
+	}
+	user, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+	if user.TOTPEnabled || user.TOTPSecret == "" {
+		respondProblem(w, r, http.StatusConflict, "Start with POST /api/auth/totp/setup")
+		return
+	}
+
+	step, ok := auth.VerifyTOTP(user.TOTPSecret, req.Code, time.Now())
+	if !ok {
+		respondInvalid(w, r, validate.Errors{{Pointer: "/code", Detail: "does not match"}})
+		return
+	}
+	codes, hashes := auth.NewRecoveryCodes()
+	user.TOTPEnabled, user.TOTPLastStep, user.RecoveryCodes = true, step, hashes
+	if err := h.users.UpdateAdminUserTOTP(r.Context(), user); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	respondJSON(w, http.StatusOK, recoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// POST /api/auth/totp/disable - needs the password and a one-time or
+// recovery code, so a stolen session alone cannot turn 2FA off
+func (h *AuthHandler) DisableTOTP(w http.ResponseWriter, r *http.Request) {
+	var req totpDisableRequest
+	if !h.decodeValid(w, r, &req) {
+		return
+	}
+	user, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+	if !user.TOTPEnabled {
+		respondProblem(w, r, http.StatusConflict, "Two-factor authentication is off")
+		return
+	}
+	if !auth.CheckPassword(user.PasswordHash, req.Password) {
+		respondProblem(w, r, http.StatusForbidden, "Wrong password")
+		return
+	}
+	if !h.secondFactor(w, r, user, req.Code) {
+		return
+	}
+
+	user.TOTPSecret, user.TOTPEnabled, user.TOTPLastStep, user.RecoveryCodes = "", false, 0, nil
+	if err := h.users.UpdateAdminUserTOTP(r.Context(), user); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// POST /api/auth/totp/recovery-codes - replace the recovery codes
+func (h *AuthHandler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	var req totpCodeRequest
+	if !h.decodeValid(w, r, &req) {
+		return
+	}
+	user, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+	if !user.TOTPEnabled {
+		respondProblem(w, r, http.StatusConflict, "Two-factor authentication is off")
+		return
+	}
+	if !h.secondFactor(w, r, user, req.Code) {
+		return
+	}
+
+	// Reload, the code check may have moved the last used step
+	user, err := h.users.GetAdminUser(r.Context(), user.ID)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	codes, hashes := auth.NewRecoveryCodes()
+	user.RecoveryCodes = hashes
+	if err := h.users.UpdateAdminUserTOTP(r.Context(), user); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	respondJSON(w, http.StatusOK, recoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// secondFactor checks a TOTP or recovery code and writes 401 if it fails
+func (h *AuthHandler) secondFactor(w http.ResponseWriter, r *http.Request, user entity.AdminUser, code string) bool {
+	err := h.checkOTP(r.Context(), user, code)
+	if errors.Is(err, errInvalidOTP) {
+		respondProblem(w, r, http.StatusUnauthorized, "Invalid one-time code")
+		return false
+	}
+	if err != nil {
+		respondError(w, r, err)
+		return false
+	}
+	return true
+}
+
+// checkOTP accepts a current TOTP code that was not used before, or an
+// unused recovery code, which is then spent
+func (h *AuthHandler) checkOTP(ctx context.Context, user entity.AdminUser, code string) error {
+	if step, ok := auth.VerifyTOTP(user.TOTPSecret, code, time.Now()); ok {
+		err := h.users.UseTOTPStep(ctx, user.ID, step)
+		if errors.Is(err, repository.ErrConflict) {
+			return errInvalidOTP
+		}
+		return err
+	}
+
+	err := h.users.UseRecoveryCode(ctx, user.ID, auth.HashRecoveryCode(code))
+	if errors.Is(err, repository.ErrNotFound) {
+		return errInvalidOTP
+	}
+	return err
+}
+
+// currentUser loads the account of the request; API keys and the legacy
+// password have none
+func (h *AuthHandler) currentUser(w http.ResponseWriter, r *http.Request) (entity.AdminUser, bool) {
+	p, _ := principalFromContext(r.Context())
+	if p.SessionID == "" {
+		respondProblem(w, r, http.StatusBadRequest, "Only admin sessions have an account")
+		return entity.AdminUser{}, false
+	}
+	user, err := h.users.GetAdminUser(r.Context(), p.UserID)
+	if err != nil {
+		respondError(w, r, err)
+		return user, false
+	}
+	return user, true
+}
+
+// decodeValid decodes and validates a JSON body, writing the error response
+// if either fails
+func (h *AuthHandler) decodeValid(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		respondProblem(w, r, http.StatusBadRequest, err.Error())
+		return false
+	}
+	if err := h.validator.Validate(v); err != nil {
+		respondError(w, r, err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"server/internal/auth"
+	"server/internal/entity"
+	"server/internal/repository"
+)
+
+// errLastOwner keeps the panel from locking everyone out of user management
+var errLastOwner = fmt.Errorf("%w: at least one owner must remain", repository.ErrConflict)
+
+type createUserRequest struct {
+	Username string      `json:"username" validate:"required,id"`
+	Password string      `json:"password" validate:"required,min=12"`
+	Role     entity.Role `json:"role" validate:"required,oneof=owner editor analyst"`
+}
+
+// updateUserRequest changes only the fields that are present
+type updateUserRequest struct {
+	Role     *entity.Role `json:"role" validate:"oneof=owner editor analyst"`
+	Password *string      `json:"password" validate:"min=12"`
+}
+
+// GET /api/auth/users
+func (h *AuthHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	users, err := h.users.ListAdminUsers(r.Context())
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, users)
+}
+
+// POST /api/auth/users
+func (h *AuthHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	var req createUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := h.validator.Validate(req); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	hash, err := auth.HashPassword(req.Password)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+	user, err := h.users.CreateAdminUser(r.Context(), entity.AdminUser{
+		Username:     req.Username,
+		Role:         req.Role,
+		PasswordHash: hash,
+	})
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, user)
+}
+
+// PATCH /api/auth/users/{id} - change the role and/or password. The
+// account's sessions end, so its tokens carry the new role from the next
+// login.
+func (h *AuthHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	var req updateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := h.validator.Validate(req); err != nil {
+		respondError(w, r, err)
+		return
+	}
+	ctx := r.Context()
+
+	h.usersMu.Lock()
+	defer h.usersMu.Unlock()
+
+	user, ok := h.userFromURL(w, r)
+	if !ok {
+		return
+	}
+	if req.Role != nil {
+		// Lowering an owner must leave another owner behind
+		if *req.Role != entity.RoleOwner {
+			if err := h.checkOwnerRemains(ctx, user); err != nil {
+				respondError(w, r, err)
+				return
+			}
+		}
+		user.Role = *req.Role
+	}
+	if req.Password != nil {
+		hash, err := auth.HashPassword(*req.Password)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+		user.PasswordHash = hash
+	}
+
+	if err := h.users.UpdateAdminUser(ctx, user); err != nil {
+		respondError(w, r, err)
+		return
+	}
+	if err := h.users.RevokeAdminUserSessions(ctx, user.ID); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, user)
+}
+
+// DELETE /api/auth/users/{id}
+func (h *AuthHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	h.usersMu.Lock()
+	defer h.usersMu.Unlock()
+
+	user, ok := h.userFromURL(w, r)
+	if !ok {
+		return
+	}
+	if err := h.checkOwnerRemains(ctx, user); err != nil {
+		respondError(w, r, err)
+		return
+	}
+	if err := h.users.DeleteAdminUser(ctx, user.ID); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AuthHandler) userFromURL(w http.ResponseWriter, r *http.Request) (entity.AdminUser, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondProblem(w, r, http.StatusBadRequest, "Invalid user id")
+		return entity.AdminUser{}, false
+	}
+	user, err := h.users.GetAdminUser(r.Context(), id)
+	if err != nil {
+		respondError(w, r, err)
+		return user, false
+	}
+	return user, true
+}
+
+// checkOwnerRemains fails if user is the only owner, before it is demoted
+// or deleted
+func (h *AuthHandler) checkOwnerRemains(ctx context.Context, user entity.AdminUser) error {
+	if user.Role != entity.RoleOwner {
+		return nil
+	}
+	users, err := h.users.ListAdminUsers(ctx)
+	if err != nil {
+		return err
+	}
+	for _, u := range users {
+		if u.ID != user.ID && u.Role == entity.RoleOwner {
+			return nil
+		}
+	}
+	return errLastOwner
+}
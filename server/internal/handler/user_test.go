@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"server/internal/auth"
+	"server/internal/entity"
+	"server/internal/locale"
+	"server/internal/repository"
+	"server/internal/validate"
+)
+
+// newTestUserRouter serves /auth/me, the user and key endpoints and content
+// and analytics routes guarded like in cmd/api, over a store holding one
+// owner account
+func newTestUserRouter(t *testing.T) (http.Handler, *repository.MemoryRepository) {
+	t.Helper()
+	repo := repository.NewMemoryRepository(locale.Default)
+	if _, err := EnsureAdmin(context.Background(), repo, testUsername, testPassword); err != nil {
+		t.Fatal(err)
+	}
+	h := NewAuthHandler(repo, []byte("test-secret"), validate.New(locale.Default), AuthConfig{AccessTTL: time.Minute, RefreshTTL: time.Hour})
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) }
+
+	r := chi.NewRouter()
+	r.Post("/auth/login", h.Login)
+	r.Group(func(r chi.Router) {
+		r.Use(h.Middleware)
+		r.Get("/auth/me", h.Me)
+		r.With(h.Require(auth.ContentWrite)).Put("/content/projects", ok)
+		r.With(h.Require(auth.ContentWrite)).Put("/content/about", ok)
+		r.With(h.Require(auth.ContentPublish)).Post("/content/publish", ok)
+		r.With(h.Require(auth.AnalyticsRead)).Get("/analytics", ok)
+		r.Group(func(r chi.Router) {
+			r.Use(h.Require(auth.UsersManage))
+			r.Get("/auth/users", h.ListUsers)
+			r.Post("/auth/users", h.CreateUser)
+			r.Patch("/auth/users/{id}", h.UpdateUser)
+			r.Delete("/auth/users/{id}", h.DeleteUser)
+		})
+		r.Group(func(r chi.Router) {
+			r.Use(h.Require(auth.KeysManage))
+			r.Get("/auth/keys", h.ListKeys)
+			r.Post("/auth/keys", h.CreateKey)
+			r.Delete("/auth/keys/{id}", h.RevokeKey)
+		})
+	})
+	return r, repo
+}
+
+// createUser adds an account through the owner and returns it
+func createUser(t *testing.T, h http.Handler, ownerToken, username string, role entity.Role) entity.AdminUser {
+	t.Helper()
+	body := fmt.Sprintf(`{"username":%q,"password":%q,"role":%q}`, username, testPassword, role)
+	rec := serve(t, h, http.MethodPost, "/auth/users", body, "Authorization", "Bearer "+ownerToken)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create %s = %d: %s", username, rec.Code, rec.Body)
+	}
+	var user entity.AdminUser
+	if err := json.Unmarshal(rec.Body.Bytes(), &user); err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+func loginAs(t *testing.T, h http.Handler, username string) string {
+	t.Helper()
+	return login(t, h, fmt.Sprintf(`{"username":%q,"password":%q}`, username, testPassword)).AccessToken
+}
+
+func TestRoles(t *testing.T) {
+	h, _ := newTestUserRouter(t)
+	owner := loginAs(t, h, testUsername)
+	createUser(t, h, owner, "editor", entity.RoleEditor)
+	createUser(t, h, owner, "analyst", entity.RoleAnalyst)
+	tokens := map[string]string{
+		"owner":   owner,
+		"editor":  loginAs(t, h, "editor"),
+		"analyst": loginAs(t, h, "analyst"),
+	}
+
+	tests := []struct {
+		account string
+		method  string
+		path    string
+		want    int
+	}{
+		{"owner", http.MethodGet, "/auth/users", http.StatusOK},
+		{"owner", http.MethodPut, "/content/projects", http.StatusNoContent},
+		{"editor", http.MethodPut, "/content/projects", http.StatusNoContent},
+		{"editor", http.MethodGet, "/analytics", http.StatusNoContent},
+		{"editor", http.MethodGet, "/auth/users", http.StatusForbidden},
+		{"analyst", http.MethodGet, "/analytics", http.StatusNoContent},
+		{"analyst", http.MethodPut, "/content/projects", http.StatusForbidden},
+		{"analyst", http.MethodGet, "/auth/users", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		rec := serve(t, h, tt.method, tt.path, "", "Authorization", "Bearer "+tokens[tt.account])
+		if rec.Code != tt.want {
+			t.Errorf("%s %s as %s = %d, want %d", tt.method, tt.path, tt.account, rec.Code, tt.want)
+		}
+	}
+
+	rec := serve(t, h, http.MethodGet, "/auth/me", "", "Authorization", "Bearer "+tokens["analyst"])
+	var got meResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := []auth.Permission{auth.AnalyticsRead}
+	if got.Username != "analyst" || got.Role != entity.RoleAnalyst || !reflect.DeepEqual(got.Permissions, want) {
+		t.Errorf("GET /auth/me = %+v, want analyst with %v", got, want)
+	}
+}
+
+func TestUserManagement(t *testing.T) {
+	h, _ := newTestUserRouter(t)
+	owner := loginAs(t, h, testUsername)
+	bearer := func(token string) []string { return []string{"Authorization", "Bearer " + token} }
+
+	me := serve(t, h, http.MethodGet, "/auth/me", "", bearer(owner)...)
+	var self meResponse
+	if err := json.Unmarshal(me.Body.Bytes(), &self); err != nil {
+		t.Fatal(err)
+	}
+	selfPath := fmt.Sprintf("/auth/users/%d", self.ID)
+
+	// The only owner can be neither demoted nor deleted
+	if rec := serve(t, h, http.MethodPatch, selfPath, `{"role":"editor"}`, bearer(owner)...); rec.Code != http.StatusConflict {
+		t.Errorf("demote the last owner = %d, want 409", rec.Code)
+	}
+	if rec := serve(t, h, http.MethodDelete, selfPath, "", bearer(owner)...); rec.Code != http.StatusConflict {
+		t.Errorf("delete the last owner = %d, want 409", rec.Code)
+	}
+
+	if rec := serve(t, h, http.MethodPost, "/auth/users", `{"username":"short","password":"123","role":"editor"}`, bearer(owner)...); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("create with a short password = %d, want 422", rec.Code)
+	}
+	if rec := serve(t, h, http.MethodPost, "/auth/users", fmt.Sprintf(`{"username":%q,"password":%q,"role":"editor"}`, testUsername, testPassword), bearer(owner)...); rec.Code != http.StatusConflict {
+		t.Errorf("create with a taken username = %d, want 409", rec.Code)
+	}
+
+	// A changed role takes effect on the next login; the old tokens stop
+	// working
+	editor := createUser(t, h, owner, "editor", entity.RoleEditor)
+	editorToken := loginAs(t, h, "editor")
+	editorPath := fmt.Sprintf("/auth/users/%d", editor.ID)
+	rec := serve(t, h, http.MethodPatch, editorPath, `{"role":"owner"}`, bearer(owner)...)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("promote = %d: %s", rec.Code, rec.Body)
+	}
+	var promoted entity.AdminUser
+	if err := json.Unmarshal(rec.Body.Bytes(), &promoted); err != nil || promoted.Role != entity.RoleOwner {
+		t.Errorf("promoted user = %+v, %v", promoted, err)
+	}
+	if rec := serve(t, h, http.MethodGet, "/auth/me", "", bearer(editorToken)...); rec.Code != http.StatusUnauthorized {
+		t.Errorf("old token after the role change = %d, want 401", rec.Code)
+	}
+	if rec := serve(t, h, http.MethodGet, "/auth/users", "", bearer(loginAs(t, h, "editor"))...); rec.Code != http.StatusOK {
+		t.Errorf("new owner lists users = %d, want 200", rec.Code)
+	}
+
+	// With a second owner the first one may go
+	if rec := serve(t, h, http.MethodDelete, selfPath, "", bearer(owner)...); rec.Code != http.StatusNoContent {
+		t.Errorf("delete an owner with another left = %d, want 204: %s", rec.Code, rec.Body)
+	}
+	if rec := serve(t, h, http.MethodPatch, "/auth/users/999", `{"role":"editor"}`, bearer(loginAs(t, h, "editor"))...); rec.Code != http.StatusNotFound {
+		t.Errorf("update an unknown user = %d, want 404", rec.Code)
+	}
+}
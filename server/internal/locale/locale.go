@@ -0,0 +1,79 @@
+// Package locale describes which languages the site content is written in.
+package locale
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var tagRe = regexp.MustCompile(`^[a-z]{2,3}$`)
+
+// Set is the ordered list of supported content locales. The first one is
+// the default.
+type Set []string
+
+// Default is used when nothing is configured
+var Default = Set{"ru", "en"}
+
+// Parse reads a comma separated list such as "ru,en,de,uk"
+func Parse(s string) (Set, error) {
+	var set Set
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ",") {
+		tag := strings.ToLower(strings.TrimSpace(part))
+		if tag == "" {
+			continue
+		}
+		if !tagRe.MatchString(tag) {
+			return nil, fmt.Errorf("invalid locale %q", part)
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			set = append(set, tag)
+		}
+	}
+	if len(set) == 0 {
+		return nil, fmt.Errorf("no locales in %q", s)
+	}
+	return set, nil
+}
+
+func (s Set) Contains(tag string) bool {
+	for _, l := range s {
+		if l == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTag reports whether tag looks like a locale, supported or not
+func IsTag(tag string) bool {
+	return tagRe.MatchString(tag)
+}
+
+// Normalize returns a copy of m with a key for every supported locale,
+// missing translations become "". Translations into other locales are kept,
+// so taking a locale out of the config does not lose them on the next save.
+func (s Set) Normalize(m map[string]string) map[string]string {
+	out := make(map[string]string, len(s)+len(m))
+	for l, v := range m {
+		out[l] = v
+	}
+	for _, l := range s {
+		out[l] = m[l]
+	}
+	return out
+}
+
+// Filter returns a copy of m with exactly one key per supported locale, for
+// content read out to the site: missing translations become "" and other
+// locales are dropped
+func (s Set) Filter(m map[string]string) map[string]string {
+	out := make(map[string]string, len(s))
+	for _, l := range s {
+		out[l] = m[l]
+	}
+	return out
+}
@@ -0,0 +1,155 @@
+package locale
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Set
+		wantErr bool
+	}{
+		{in: "ru,en", want: Set{"ru", "en"}},
+		{in: " RU , en,,uk ", want: Set{"ru", "en", "uk"}},
+		{in: "en,ru,en", want: Set{"en", "ru"}},
+		{in: "", wantErr: true},
+		{in: " , ", wantErr: true},
+		{in: "ru,en-US", wantErr: true},
+		{in: "r", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Parse(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Parse(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	got := Set{"ru", "en"}.Normalize(map[string]string{"en": "Hi", "de": "Hallo"})
+	want := map[string]string{"ru": "", "en": "Hi", "de": "Hallo"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Normalize() = %v, want %v", got, want)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	got := Set{"ru", "en"}.Filter(map[string]string{"en": "Hi", "de": "Hallo"})
+	want := map[string]string{"ru": "", "en": "Hi"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Filter() = %v, want %v", got, want)
+	}
+}
+
+func TestParseFallback(t *testing.T) {
+	set := Set{"ru", "en", "uk", "de"}
+	tests := []struct {
+		in      string
+		want    Fallback
+		wantErr string
+	}{
+		{in: "", want: Fallback{}},
+		{in: "uk:ru,en; de:en", want: Fallback{"uk": {"ru", "en"}, "de": {"en"}}},
+		{in: "uk", wantErr: "invalid fallback rule"},
+		{in: "fr:en", wantErr: "unsupported locale"},
+		{in: "uk:fr", wantErr: "uses unsupported locale"},
+		{in: "uk:", wantErr: "no locales"},
+	}
+	for _, tt := range tests {
+		got, err := ParseFallback(tt.in, set)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseFallback(%q) error = %v, want %q", tt.in, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseFallback(%q) error = %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseFallback(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	n := NewNegotiator(Set{"ru", "en", "uk"}, nil)
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", "ru"},
+		{"en", "en"},
+		{"en-US,en;q=0.9", "en"},
+		{"de-DE,uk;q=0.8,en;q=0.5", "uk"},
+		{"en;q=0.5,uk;q=0.9", "uk"},
+		{"en;q=0.8,uk;q=0.8", "en"},
+		{"EN-gb", "en"},
+		{"en;q=0", "ru"},
+		{"en;q=abc,uk", "uk"},
+		{"de,fr", "ru"},
+		{"*,en;q=0.5", "ru"},
+	}
+	for _, tt := range tests {
+		if got := n.Negotiate(tt.header); got != tt.want {
+			t.Errorf("Negotiate(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	n := NewNegotiator(Set{"ru", "en"}, nil)
+	tests := map[string]string{
+		"ru":    "ru",
+		" EN ":  "en",
+		"en-AU": "en",
+		"de":    "",
+		"de-en": "",
+	}
+	for in, want := range tests {
+		if got := n.Match(in); got != want {
+			t.Errorf("Match(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestTranslator(t *testing.T) {
+	n := NewNegotiator(Set{"ru", "en", "uk", "de"}, Fallback{"uk": {"ru"}, "de": {"en"}})
+	tests := []struct {
+		name string
+		tag  string
+		m    map[string]string
+		want string
+	}{
+		{name: "own translation", tag: "uk", m: map[string]string{"uk": "Привіт", "ru": "Привет"}, want: "Привіт"},
+		{name: "configured fallback", tag: "uk", m: map[string]string{"ru": "Привет", "en": "Hi"}, want: "Привет"},
+		{name: "other fallback", tag: "de", m: map[string]string{"ru": "Привет", "en": "Hi"}, want: "Hi"},
+		{name: "remaining locales in order", tag: "en", m: map[string]string{"de": "Hallo", "uk": "Привіт"}, want: "Привіт"},
+		{name: "default locale last resort", tag: "de", m: map[string]string{"ru": "Привет"}, want: "Привет"},
+		{name: "empty strings are missing", tag: "en", m: map[string]string{"en": "", "ru": "Привет"}, want: "Привет"},
+		{name: "nothing at all", tag: "en", m: nil, want: ""},
+	}
+	for _, tt := range tests {
+		if got := n.Translator(tt.tag)(tt.m); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestChain(t *testing.T) {
+	n := NewNegotiator(Set{"ru", "en", "uk"}, Fallback{"uk": {"en"}})
+	if got, want := n.Chain("uk"), []string{"uk", "en", "ru"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Chain(uk) = %v, want %v", got, want)
+	}
+	if got, want := n.Chain("en"), []string{"en", "ru", "uk"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Chain(en) = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,140 @@
+package locale
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Fallback lists, per locale, which locales to try when a translation is
+// missing, e.g. uk -> ru -> en
+type Fallback map[string][]string
+
+// ParseFallback reads chains such as "uk:ru,en;de:en"
+func ParseFallback(s string, set Set) (Fallback, error) {
+	fallback := make(Fallback)
+	for _, rule := range strings.Split(s, ";") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		from, to, ok := strings.Cut(rule, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid fallback rule %q, want locale:locale,...", rule)
+		}
+		from = strings.ToLower(strings.TrimSpace(from))
+		if !set.Contains(from) {
+			return nil, fmt.Errorf("fallback for unsupported locale %q", from)
+		}
+		chain, err := Parse(to)
+		if err != nil {
+			return nil, fmt.Errorf("fallback for %q: %w", from, err)
+		}
+		for _, tag := range chain {
+			if !set.Contains(tag) {
+				return nil, fmt.Errorf("fallback for %q uses unsupported locale %q", from, tag)
+			}
+		}
+		fallback[from] = chain
+	}
+	return fallback, nil
+}
+
+// Negotiator picks a response locale and resolves localized strings with
+// the configured fallback chains
+type Negotiator struct {
+	set      Set
+	fallback Fallback
+}
+
+func NewNegotiator(set Set, fallback Fallback) *Negotiator {
+	return &Negotiator{set: set, fallback: fallback}
+}
+
+// Locales returns the supported locales
+func (n *Negotiator) Locales() Set {
+	return n.set
+}
+
+// Match returns the supported locale for a tag such as "uk" or "de-AT",
+// or "" if there is none
+func (n *Negotiator) Match(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if n.set.Contains(tag) {
+		return tag
+	}
+	if base, _, ok := strings.Cut(tag, "-"); ok && n.set.Contains(base) {
+		return base
+	}
+	return ""
+}
+
+// Negotiate picks the best supported locale for an Accept-Language header,
+// or the default locale if nothing matches
+func (n *Negotiator) Negotiate(acceptLanguage string) string {
+	type candidate struct {
+		tag string
+		q   float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{tag: tag, q: q})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+
+	for _, c := range candidates {
+		if c.tag == "*" {
+			break
+		}
+		if tag := n.Match(c.tag); tag != "" {
+			return tag
+		}
+	}
+	return n.set[0]
+}
+
+// Chain returns the locales to try for tag, in order: the tag itself, its
+// configured fallbacks, then the remaining supported locales
+func (n *Negotiator) Chain(tag string) []string {
+	chain := []string{tag}
+	seen := map[string]bool{tag: true}
+	for _, list := range [][]string{n.fallback[tag], n.set} {
+		for _, l := range list {
+			if !seen[l] {
+				seen[l] = true
+				chain = append(chain, l)
+			}
+		}
+	}
+	return chain
+}
+
+// Translator returns a function that picks the first non-empty translation
+// along the chain of tag
+func (n *Negotiator) Translator(tag string) func(map[string]string) string {
+	chain := n.Chain(tag)
+	return func(m map[string]string) string {
+		for _, l := range chain {
+			if v := m[l]; v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+}
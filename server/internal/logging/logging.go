@@ -0,0 +1,41 @@
+// Package logging builds the slog logger of the API and carries a
+// request-scoped logger in the context, so everything logged while serving
+// a request shares its request id.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+)
+
+const (
+	Text = "text"
+	JSON = "json"
+)
+
+type contextKey struct{}
+
+// New returns a logger writing format (text or json) to w; level can be a
+// *slog.LevelVar to change it while running
+func New(w io.Writer, format string, level slog.Leveler) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: level}
+	if format == JSON {
+		return slog.New(slog.NewJSONHandler(w, opts))
+	}
+	return slog.New(slog.NewTextHandler(w, opts))
+}
+
+// WithContext returns ctx carrying logger
+func WithContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger of the request, or the default logger
+// outside of one
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
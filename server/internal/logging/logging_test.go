@@ -0,0 +1,49 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	logger := New(&buf, JSON, level)
+
+	logger.Debug("hidden")
+	logger.Info("shown", "key", "value")
+	level.Set(slog.LevelDebug)
+	logger.Debug("shown after the level change")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("logged %d lines, want 2: %s", len(lines), buf.String())
+	}
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("line is not JSON: %v", err)
+	}
+	if entry["msg"] != "shown" || entry["key"] != "value" {
+		t.Errorf("entry = %v", entry)
+	}
+
+	buf.Reset()
+	New(&buf, Text, slog.LevelInfo).Info("plain", "key", "value")
+	if got := buf.String(); !strings.Contains(got, "msg=plain key=value") {
+		t.Errorf("text line = %q", got)
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if FromContext(context.Background()) != slog.Default() {
+		t.Error("FromContext() without a logger is not the default logger")
+	}
+	logger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+	if FromContext(WithContext(context.Background(), logger)) != logger {
+		t.Error("FromContext() lost the logger of the context")
+	}
+}
@@ -0,0 +1,113 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"server/internal/repository"
+)
+
+// RegisterPool exports the connection pool statistics of stat, read on
+// every scrape
+func (m *Metrics) RegisterPool(stat func() *pgxpool.Stat) {
+	m.registry.MustRegister(&poolCollector{stat: stat})
+}
+
+var (
+	poolAcquired = prometheus.NewDesc("db_pool_acquired_conns", "Connections currently in use.", nil, nil)
+	poolIdle     = prometheus.NewDesc("db_pool_idle_conns", "Idle connections.", nil, nil)
+	poolTotal    = prometheus.NewDesc("db_pool_total_conns", "Open connections, including ones being established.", nil, nil)
+	poolMax      = prometheus.NewDesc("db_pool_max_conns", "Pool size limit.", nil, nil)
+	poolAcquires = prometheus.NewDesc("db_pool_acquires_total", "Successful connection acquires.", nil, nil)
+	poolWaits    = prometheus.NewDesc("db_pool_empty_acquires_total", "Acquires that had to wait for a connection.", nil, nil)
+	poolWait     = prometheus.NewDesc("db_pool_acquire_duration_seconds_total", "Time spent acquiring connections.", nil, nil)
+)
+
+type poolCollector struct {
+	stat func() *pgxpool.Stat
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{poolAcquired, poolIdle, poolTotal, poolMax, poolAcquires, poolWaits, poolWait} {
+		ch <- d
+	}
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.stat()
+	ch <- prometheus.MustNewConstMetric(poolAcquired, prometheus.GaugeValue, float64(s.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(poolIdle, prometheus.GaugeValue, float64(s.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(poolTotal, prometheus.GaugeValue, float64(s.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(poolMax, prometheus.GaugeValue, float64(s.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(poolAcquires, prometheus.CounterValue, float64(s.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolWaits, prometheus.CounterValue, float64(s.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolWait, prometheus.CounterValue, s.AcquireDuration().Seconds())
+}
+
+// QueryTracer times every query of the pool it is set on, including the
+// queries of a batch. Queries are labeled by repository.QueryName rather
+// than by SQL text.
+func (m *Metrics) QueryTracer() pgx.QueryTracer {
+	return queryTracer{m: m}
+}
+
+type queryTracer struct {
+	m *Metrics
+}
+
+var _ pgx.BatchTracer = queryTracer{}
+
+type queryStart struct {
+	query string
+	start time.Time
+}
+
+type queryStartKey struct{}
+
+func (t queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryStartKey{}, queryStart{query: repository.QueryName(data.SQL), start: time.Now()})
+}
+
+func (t queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(queryStartKey{}).(queryStart)
+	if !ok {
+		return
+	}
+	t.m.queries.WithLabelValues(start.query, outcome(data.Err)).Observe(time.Since(start.start).Seconds())
+}
+
+// batchProgress is when the previous result of a batch was read. Results
+// come back one after another, so the time since then is what the next
+// query took.
+type batchProgress struct {
+	last time.Time
+}
+
+type batchKey struct{}
+
+func (t queryTracer) TraceBatchStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceBatchStartData) context.Context {
+	return context.WithValue(ctx, batchKey{}, &batchProgress{last: time.Now()})
+}
+
+func (t queryTracer) TraceBatchQuery(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchQueryData) {
+	progress, ok := ctx.Value(batchKey{}).(*batchProgress)
+	if !ok {
+		return
+	}
+	now := time.Now()
+	t.m.queries.WithLabelValues(repository.QueryName(data.SQL), outcome(data.Err)).Observe(now.Sub(progress.last).Seconds())
+	progress.last = now
+}
+
+func (t queryTracer) TraceBatchEnd(context.Context, *pgx.Conn, pgx.TraceBatchEndData) {}
+
+func outcome(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
@@ -0,0 +1,119 @@
+// Package metrics collects Prometheus metrics of HTTP requests, the
+// database pool, repository queries and analytics ingestion, all in one
+// registry served by Handler.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// devices are the device labels of analytics counters; anything else the
+// client sends is counted as "other" to keep the label set small
+var devices = map[string]bool{"desktop": true, "mobile": true, "tablet": true}
+
+type Metrics struct {
+	registry     *prometheus.Registry
+	requests     *prometheus.CounterVec
+	duration     *prometheus.HistogramVec
+	queries      *prometheus.HistogramVec
+	pageViews    *prometheus.CounterVec
+	sessions     *prometheus.CounterVec
+	botsFiltered prometheus.Counter
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "HTTP requests by method, chi route pattern and status.",
+		}, []string{"method", "route", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency by method, chi route pattern and status.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		queries: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "db_query_duration_seconds",
+			Help:    "Database query latency by statement and table, e.g. \"select about\", and outcome.",
+			Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"query", "outcome"}),
+		pageViews: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "analytics_page_views_total",
+			Help: "Tracked page views by device.",
+		}, []string{"device"}),
+		sessions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "analytics_sessions_total",
+			Help: "Tracked sessions by device.",
+		}, []string{"device"}),
+		botsFiltered: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "analytics_bot_events_total",
+			Help: "Tracking events dropped because of a bot User-Agent.",
+		}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests, m.duration, m.queries, m.pageViews, m.sessions, m.botsFiltered,
+	)
+	return m
+}
+
+// Handler serves the registry in the Prometheus text format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// Middleware counts and times every request. Requests are labeled by route
+// pattern, never by path, so ids in URLs do not create new series; requests
+// that match no route are labeled "unmatched". It goes before Recoverer so
+// panics are counted as 500.
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		route := "unmatched"
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		labels := prometheus.Labels{"method": r.Method, "route": route, "status": strconv.Itoa(status)}
+		m.requests.With(labels).Inc()
+		m.duration.With(labels).Observe(time.Since(start).Seconds())
+	})
+}
+
+func (m *Metrics) PageView(device string) {
+	m.pageViews.WithLabelValues(deviceLabel(device)).Inc()
+}
+
+func (m *Metrics) Session(device string) {
+	m.sessions.WithLabelValues(deviceLabel(device)).Inc()
+}
+
+func (m *Metrics) BotFiltered() {
+	m.botsFiltered.Inc()
+}
+
+func deviceLabel(device string) string {
+	if device == "" {
+		return "unknown"
+	}
+	if !devices[device] {
+		return "other"
+	}
+	return device
+}
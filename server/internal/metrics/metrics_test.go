@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+// scrape returns the metric lines of m without comments
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("scrape = %d", rec.Code)
+	}
+	return rec.Body.String()
+}
+
+func wantLines(t *testing.T, body string, lines ...string) {
+	t.Helper()
+	for _, line := range lines {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("metrics miss %q", line)
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	m := New()
+	r := chi.NewRouter()
+	r.Use(m.Middleware)
+	r.Get("/api/content/{section}/{id}", func(w http.ResponseWriter, r *http.Request) {})
+	r.Post("/api/analytics/track", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	for _, path := range []string{"/api/content/projects/a", "/api/content/projects/b", "/nope"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/analytics/track", nil))
+
+	body := scrape(t, m)
+	wantLines(t, body,
+		`http_requests_total{method="GET",route="/api/content/{section}/{id}",status="200"} 2`,
+		`http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`http_requests_total{method="POST",route="/api/analytics/track",status="202"} 1`,
+		`http_request_duration_seconds_count{method="GET",route="/api/content/{section}/{id}",status="200"} 2`,
+	)
+	if strings.Contains(body, "projects/a") {
+		t.Error("metrics are labeled by path")
+	}
+}
+
+func TestAnalyticsCounters(t *testing.T) {
+	m := New()
+	for _, device := range []string{"mobile", "mobile", "toaster", ""} {
+		m.PageView(device)
+	}
+	m.Session("desktop")
+	m.BotFiltered()
+
+	wantLines(t, scrape(t, m),
+		`analytics_page_views_total{device="mobile"} 2`,
+		`analytics_page_views_total{device="other"} 1`,
+		`analytics_page_views_total{device="unknown"} 1`,
+		`analytics_sessions_total{device="desktop"} 1`,
+		`analytics_bot_events_total 1`,
+	)
+}
+
+func TestQueryTracer(t *testing.T) {
+	m := New()
+	tracer := m.QueryTracer().(queryTracer)
+	ctx := context.Background()
+
+	qctx := tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "SELECT * FROM about WHERE id = $1"})
+	tracer.TraceQueryEnd(qctx, nil, pgx.TraceQueryEndData{})
+	qctx = tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "INSERT INTO page_views (page) VALUES ($1)"})
+	tracer.TraceQueryEnd(qctx, nil, pgx.TraceQueryEndData{Err: errors.New("boom")})
+
+	bctx := tracer.TraceBatchStart(ctx, nil, pgx.TraceBatchStartData{})
+	tracer.TraceBatchQuery(bctx, nil, pgx.TraceBatchQueryData{SQL: "DELETE FROM skills"})
+	tracer.TraceBatchQuery(bctx, nil, pgx.TraceBatchQueryData{SQL: "INSERT INTO skills (id) VALUES ($1)"})
+	tracer.TraceBatchEnd(bctx, nil, pgx.TraceBatchEndData{})
+
+	// Without a start there is nothing to time
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+
+	wantLines(t, scrape(t, m),
+		`db_query_duration_seconds_count{outcome="ok",query="select about"} 1`,
+		`db_query_duration_seconds_count{outcome="error",query="insert page_views"} 1`,
+		`db_query_duration_seconds_count{outcome="ok",query="delete skills"} 1`,
+		`db_query_duration_seconds_count{outcome="ok",query="insert skills"} 1`,
+	)
+}
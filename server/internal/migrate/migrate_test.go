@@ -0,0 +1,140 @@
+package migrate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"server/migrations"
+)
+
+func file(body string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(body)}
+}
+
+func checksum(body string) string {
+	sum := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		fsys    fstest.MapFS
+		want    []Migration
+		wantErr string
+	}{
+		{
+			name: "pairs sorted by version",
+			fsys: fstest.MapFS{
+				"010_second.up.sql":  file("CREATE TABLE b ();"),
+				"001_first.up.sql":   file("CREATE TABLE a ();"),
+				"001_first.down.sql": file("DROP TABLE a;"),
+			},
+			want: []Migration{
+				{Version: 1, Name: "first", Up: "CREATE TABLE a ();", Down: "DROP TABLE a;", Checksum: checksum("CREATE TABLE a ();")},
+				{Version: 10, Name: "second", Up: "CREATE TABLE b ();", Checksum: checksum("CREATE TABLE b ();")},
+			},
+		},
+		{
+			name: "other files and directories are ignored",
+			fsys: fstest.MapFS{
+				"001_first.up.sql":       file("SELECT 1;"),
+				"README.md":              file("notes"),
+				"002_Upper.up.sql":       file("SELECT 2;"),
+				"003_third.sideways.sql": file("SELECT 3;"),
+				"old/004_old.up.sql":     file("SELECT 4;"),
+			},
+			want: []Migration{
+				{Version: 1, Name: "first", Up: "SELECT 1;", Checksum: checksum("SELECT 1;")},
+			},
+		},
+		{
+			name: "down without up",
+			fsys: fstest.MapFS{
+				"001_first.down.sql": file("DROP TABLE a;"),
+			},
+			wantErr: "has no up file",
+		},
+		{
+			name: "conflicting names",
+			fsys: fstest.MapFS{
+				"001_first.up.sql": file("SELECT 1;"),
+				"001_other.up.sql": file("SELECT 1;"),
+			},
+			wantErr: "conflicting names",
+		},
+		{
+			name: "empty",
+			fsys: fstest.MapFS{},
+			want: []Migration{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Load(tt.fsys)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Load() = %d migrations, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("migration %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestChecksumCoversUpOnly(t *testing.T) {
+	load := func(up, down string) Migration {
+		t.Helper()
+		got, err := Load(fstest.MapFS{
+			"001_first.up.sql":   file(up),
+			"001_first.down.sql": file(down),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return got[0]
+	}
+
+	base := load("CREATE TABLE a ();", "DROP TABLE a;")
+	if got := load("CREATE TABLE a ();", "DROP TABLE IF EXISTS a;"); got.Checksum != base.Checksum {
+		t.Error("editing the down file changed the checksum")
+	}
+	if got := load("CREATE TABLE a (id INT);", "DROP TABLE a;"); got.Checksum == base.Checksum {
+		t.Error("editing the up file kept the checksum")
+	}
+}
+
+// TestEmbedded guards the shipped migrations: they must load, be numbered
+// without gaps and all be reversible.
+func TestEmbedded(t *testing.T) {
+	got, err := Load(migrations.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) == 0 {
+		t.Fatal("no migrations embedded")
+	}
+	for i, m := range got {
+		if m.Version != int64(i+1) {
+			t.Errorf("migration %d_%s: want version %d", m.Version, m.Name, i+1)
+		}
+		if m.Down == "" {
+			t.Errorf("migration %d_%s has no down file", m.Version, m.Name)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package preview
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestToken(t *testing.T) {
+	s := NewSigner([]byte("secret"), time.Hour)
+	now := time.Unix(1_700_000_000, 0)
+	token, expires := s.Issue(now)
+	if !expires.Equal(now.Add(time.Hour)) {
+		t.Errorf("expires = %v, want %v", expires, now.Add(time.Hour))
+	}
+
+	payload, signature, _ := strings.Cut(token, ".")
+	tests := []struct {
+		name  string
+		token string
+		at    time.Time
+		want  bool
+	}{
+		{"fresh", token, now, true},
+		{"just before expiry", token, expires.Add(-time.Second), true},
+		{"expired", token, expires, false},
+		{"other secret", func() string { t, _ := NewSigner([]byte("other"), time.Hour).Issue(now); return t }(), now, false},
+		{"extended expiry", "9999999999." + signature, now, false},
+		{"no signature", payload, now, false},
+		{"empty", "", now, false},
+	}
+	for _, tt := range tests {
+		if got := s.Verify(tt.token, tt.at); got != tt.want {
+			t.Errorf("%s: Verify() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Lockout blocks a key for base after threshold failures in a row, and
+// doubles the time with every further failure up to max. A success resets
+// the count; so does a day without failures.
+type Lockout struct {
+	store     Store
+	threshold int
+	base, max time.Duration
+}
+
+func NewLockout(store Store, threshold int, base, maxDelay time.Duration) *Lockout {
+	return &Lockout{store: store, threshold: threshold, base: base, max: maxDelay}
+}
+
+// Locked returns how long key stays locked, zero if it is not
+func (l *Lockout) Locked(ctx context.Context, key string, now time.Time) (time.Duration, error) {
+	failures, last, err := l.store.AuthFailures(ctx, key, now)
+	if err != nil {
+		return 0, err
+	}
+	return max(last.Add(l.delay(failures)).Sub(now), 0), nil
+}
+
+// Fail records a failed attempt and returns how long key is locked now
+func (l *Lockout) Fail(ctx context.Context, key string, now time.Time) (time.Duration, error) {
+	failures, err := l.store.AddAuthFailure(ctx, key, now, l.forget())
+	if err != nil {
+		return 0, err
+	}
+	return l.delay(failures), nil
+}
+
+// Reset forgets the failures of key after a successful attempt
+func (l *Lockout) Reset(ctx context.Context, key string) error {
+	return l.store.ResetAuthFailures(ctx, key)
+}
+
+func (l *Lockout) delay(failures int) time.Duration {
+	if l.threshold < 1 || failures < l.threshold {
+		return 0
+	}
+	d := l.base
+	for i := l.threshold; i < failures && d < l.max; i++ {
+		d *= 2
+	}
+	return min(d, l.max)
+}
+
+// forget is how long failures are remembered, at least twice the longest
+// lock so a locked attacker does not start over right after it ends
+func (l *Lockout) forget() time.Duration {
+	return max(24*time.Hour, 2*l.max)
+}
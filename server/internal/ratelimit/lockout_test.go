@@ -0,0 +1,105 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLockoutDelay(t *testing.T) {
+	l := NewLockout(NewMemoryStore(), 3, time.Minute, 10*time.Minute)
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{2, 0},
+		{3, time.Minute},
+		{4, 2 * time.Minute},
+		{5, 4 * time.Minute},
+		{6, 8 * time.Minute},
+		{7, 10 * time.Minute},
+		{100, 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := l.delay(tt.failures); got != tt.want {
+			t.Errorf("delay(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+
+	if got := NewLockout(NewMemoryStore(), 0, time.Minute, time.Hour).delay(10); got != 0 {
+		t.Errorf("threshold 0 locks for %v", got)
+	}
+}
+
+func TestLockout(t *testing.T) {
+	ctx := context.Background()
+	l := NewLockout(NewMemoryStore(), 2, time.Minute, time.Hour)
+	now := time.Unix(1000, 0)
+
+	locked := func(key string, at time.Time) time.Duration {
+		t.Helper()
+		wait, err := l.Locked(ctx, key, at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return wait
+	}
+	fail := func(key string, at time.Time) time.Duration {
+		t.Helper()
+		wait, err := l.Fail(ctx, key, at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return wait
+	}
+
+	if wait := fail("k", now); wait != 0 {
+		t.Fatalf("locked after one failure: %v", wait)
+	}
+	if wait := fail("k", now); wait != time.Minute {
+		t.Fatalf("Fail() = %v, want 1m", wait)
+	}
+	if wait := locked("k", now.Add(20*time.Second)); wait != 40*time.Second {
+		t.Errorf("Locked() = %v, want 40s", wait)
+	}
+	if wait := locked("other", now); wait != 0 {
+		t.Errorf("another key is locked for %v", wait)
+	}
+	if wait := locked("k", now.Add(time.Minute)); wait != 0 {
+		t.Errorf("still locked after the delay: %v", wait)
+	}
+
+	// The next failure doubles the lock
+	if wait := fail("k", now.Add(time.Minute)); wait != 2*time.Minute {
+		t.Errorf("Fail() = %v, want 2m", wait)
+	}
+
+	if err := l.Reset(ctx, "k"); err != nil {
+		t.Fatal(err)
+	}
+	if wait := locked("k", now.Add(time.Minute)); wait != 0 {
+		t.Errorf("locked after reset: %v", wait)
+	}
+	if wait := fail("k", now.Add(time.Minute)); wait != 0 {
+		t.Errorf("reset kept the count: %v", wait)
+	}
+}
+
+func TestLockoutForgets(t *testing.T) {
+	ctx := context.Background()
+	l := NewLockout(NewMemoryStore(), 2, time.Minute, time.Hour)
+	now := time.Unix(1000, 0)
+
+	l.Fail(ctx, "k", now)
+	// A day without failures starts the count over
+	if wait, _ := l.Fail(ctx, "k", now.Add(25*time.Hour)); wait != 0 {
+		t.Errorf("old failure still counts: locked for %v", wait)
+	}
+
+	// Failures are remembered for at least twice the longest lock
+	long := NewLockout(NewMemoryStore(), 1, time.Hour, 20*time.Hour)
+	if got := long.forget(); got != 40*time.Hour {
+		t.Errorf("forget() = %v, want 40h", got)
+	}
+}
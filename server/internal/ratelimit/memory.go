@@ -0,0 +1,94 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps limits in process; every replica counts on its own
+type MemoryStore struct {
+	mu       sync.Mutex
+	buckets  map[string]memoryBucket
+	failures map[string]memoryFailures
+}
+
+type memoryBucket struct {
+	Bucket
+	fullAt time.Time
+}
+
+type memoryFailures struct {
+	count     int
+	last      time.Time
+	expiresAt time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:  make(map[string]memoryBucket),
+		failures: make(map[string]memoryFailures),
+	}
+}
+
+func (s *MemoryStore) TakeToken(_ context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, res := limit.Take(s.buckets[key].Bucket, now)
+	s.buckets[key] = memoryBucket{Bucket: b, fullAt: limit.FullAt(b)}
+	return res, nil
+}
+
+func (s *MemoryStore) AddAuthFailure(_ context.Context, key string, now time.Time, forget time.Duration) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f := s.failures[key]
+	if !now.Before(f.expiresAt) {
+		f.count = 0
+	}
+	f.count++
+	f.last = now
+	f.expiresAt = now.Add(forget)
+	s.failures[key] = f
+	return f.count, nil
+}
+
+func (s *MemoryStore) AuthFailures(_ context.Context, key string, now time.Time) (int, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, ok := s.failures[key]
+	if !ok || !now.Before(f.expiresAt) {
+		return 0, time.Time{}, nil
+	}
+	return f.count, f.last, nil
+}
+
+func (s *MemoryStore) ResetAuthFailures(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.failures, key)
+	return nil
+}
+
+func (s *MemoryStore) PruneRateLimits(_ context.Context, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, b := range s.buckets {
+		if !now.Before(b.fullAt) {
+			delete(s.buckets, key)
+		}
+	}
+	for key, f := range s.failures {
+		if !now.Before(f.expiresAt) {
+			delete(s.failures, key)
+		}
+	}
+	return nil
+}
+
+var _ Store = (*MemoryStore)(nil)
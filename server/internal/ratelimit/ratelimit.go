@@ -0,0 +1,157 @@
+// Package ratelimit implements token bucket limits and progressive lockout
+// after failed logins. State lives in a Store: in memory for a single
+// instance, or in PostgreSQL when several replicas share the limits.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Store keeps buckets and failure counters. Keys are opaque strings chosen
+// by the caller, e.g. "track:ip:203.0.113.7".
+type Store interface {
+	// TakeToken takes one request out of the bucket of key
+	TakeToken(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+	// AddAuthFailure counts a failed attempt and returns the number of
+	// failures in a row. Counters not touched for forget start over.
+	AddAuthFailure(ctx context.Context, key string, now time.Time, forget time.Duration) (int, error)
+	// AuthFailures returns the failures in a row and the time of the last one
+	AuthFailures(ctx context.Context, key string, now time.Time) (int, time.Time, error)
+	ResetAuthFailures(ctx context.Context, key string) error
+	// PruneRateLimits drops full buckets and forgotten counters
+	PruneRateLimits(ctx context.Context, now time.Time) error
+}
+
+// Limit allows Requests per Per on average, with bursts of up to Requests.
+// The zero Limit is disabled.
+type Limit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// ParseLimit reads a limit written as "10/1m"; "off" or "0" disable it
+func ParseLimit(s string) (Limit, error) {
+	s = strings.TrimSpace(s)
+	if s == "off" || s == "0" {
+		return Limit{}, nil
+	}
+	count, per, ok := strings.Cut(s, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("limit %q is not requests/duration", s)
+	}
+	n, err := strconv.Atoi(count)
+	if err != nil || n < 1 {
+		return Limit{}, fmt.Errorf("limit %q: invalid number of requests", s)
+	}
+	d, err := time.ParseDuration(per)
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("limit %q: invalid duration", s)
+	}
+	return Limit{Requests: n, Per: d}, nil
+}
+
+func (l Limit) Enabled() bool {
+	return l.Requests > 0 && l.Per > 0
+}
+
+func (l Limit) String() string {
+	if !l.Enabled() {
+		return "off"
+	}
+	return fmt.Sprintf("%d/%s", l.Requests, l.Per)
+}
+
+// MarshalText writes the limit the way ParseLimit reads it
+func (l Limit) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+func (l *Limit) UnmarshalText(text []byte) error {
+	parsed, err := ParseLimit(string(text))
+	if err != nil {
+		return err
+	}
+	*l = parsed
+	return nil
+}
+
+// rate is the refill speed in requests per second
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Per.Seconds()
+}
+
+// Bucket is the stored state of one key. A zero Updated means a full bucket.
+type Bucket struct {
+	Tokens  float64
+	Updated time.Time
+}
+
+// Result tells whether a request may go through and how the bucket looks
+// after it
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is the time until the bucket is full again
+	Reset time.Duration
+	// RetryAfter is the time until the next request is allowed, zero if
+	// this one was
+	RetryAfter time.Duration
+}
+
+// Take refills b for the time since its last update and takes one token
+// from it if there is one. Stores call it with the bucket locked.
+func (l Limit) Take(b Bucket, now time.Time) (Bucket, Result) {
+	burst, rate := float64(l.Requests), l.rate()
+
+	tokens := burst
+	if !b.Updated.IsZero() {
+		elapsed := max(now.Sub(b.Updated).Seconds(), 0)
+		tokens = min(burst, b.Tokens+elapsed*rate)
+	}
+
+	res := Result{Limit: l.Requests}
+	if tokens >= 1 {
+		tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = seconds((1 - tokens) / rate)
+	}
+	res.Remaining = int(tokens)
+	res.Reset = seconds((burst - tokens) / rate)
+
+	return Bucket{Tokens: tokens, Updated: now}, res
+}
+
+// FullAt is when a bucket left at b is full again and can be forgotten
+func (l Limit) FullAt(b Bucket) time.Time {
+	return b.Updated.Add(seconds((float64(l.Requests) - b.Tokens) / l.rate()))
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
+
+// Prune removes stale state from store every interval until ctx is
+// cancelled
+func Prune(ctx context.Context, store Store, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := store.PruneRateLimits(ctx, now); err != nil {
+				slog.Error("Failed to prune rate limits", "err", err)
+			}
+		}
+	}
+}
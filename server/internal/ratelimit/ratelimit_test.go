@@ -0,0 +1,125 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Limit
+		wantErr bool
+	}{
+		{in: "10/1m", want: Limit{Requests: 10, Per: time.Minute}},
+		{in: " 5/30s ", want: Limit{Requests: 5, Per: 30 * time.Second}},
+		{in: "off", want: Limit{}},
+		{in: "0", want: Limit{}},
+		{in: "10", wantErr: true},
+		{in: "0/1m", wantErr: true},
+		{in: "-1/1m", wantErr: true},
+		{in: "x/1m", wantErr: true},
+		{in: "10/0s", wantErr: true},
+		{in: "10/minute", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLimit(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseLimit(%q) = %v, %v, want %v, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestLimitText(t *testing.T) {
+	for _, l := range []Limit{{Requests: 10, Per: time.Minute}, {}} {
+		text, err := l.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var parsed Limit
+		if err := parsed.UnmarshalText(text); err != nil || parsed != l {
+			t.Errorf("%v round-trips to %v, %v", l, parsed, err)
+		}
+	}
+	if (Limit{}).Enabled() || (Limit{Requests: 1}).Enabled() {
+		t.Error("incomplete limit is enabled")
+	}
+}
+
+func TestTake(t *testing.T) {
+	limit := Limit{Requests: 3, Per: 3 * time.Second}
+	start := time.Unix(1000, 0)
+
+	steps := []struct {
+		after      time.Duration
+		allowed    bool
+		remaining  int
+		retryAfter time.Duration
+	}{
+		{after: 0, allowed: true, remaining: 2},
+		{after: 0, allowed: true, remaining: 1},
+		{after: 0, allowed: true, remaining: 0},
+		{after: 0, allowed: false, remaining: 0, retryAfter: time.Second},
+		{after: 500 * time.Millisecond, allowed: false, remaining: 0, retryAfter: 500 * time.Millisecond},
+		{after: 500 * time.Millisecond, allowed: true, remaining: 0},
+		// A long pause refills the bucket only up to the burst
+		{after: time.Hour, allowed: true, remaining: 2},
+	}
+
+	var b Bucket
+	now := start
+	for i, s := range steps {
+		now = now.Add(s.after)
+		var res Result
+		b, res = limit.Take(b, now)
+		if res.Allowed != s.allowed || res.Remaining != s.remaining || res.RetryAfter != s.retryAfter {
+			t.Fatalf("step %d: %+v, want allowed %v, remaining %d, retry after %v", i, res, s.allowed, s.remaining, s.retryAfter)
+		}
+		if res.Limit != 3 {
+			t.Errorf("step %d: limit %d", i, res.Limit)
+		}
+	}
+	if got := limit.FullAt(b); !got.Equal(now.Add(time.Second)) {
+		t.Errorf("FullAt() = %v, want %v", got, now.Add(time.Second))
+	}
+}
+
+func TestTakeReset(t *testing.T) {
+	limit := Limit{Requests: 2, Per: 10 * time.Second}
+	_, res := limit.Take(Bucket{}, time.Unix(1000, 0))
+	if res.Reset != 5*time.Second {
+		t.Errorf("Reset = %v, want 5s", res.Reset)
+	}
+}
+
+func TestMemoryStoreKeys(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	limit := Limit{Requests: 1, Per: time.Minute}
+	now := time.Unix(1000, 0)
+
+	if res, _ := store.TakeToken(ctx, "a", limit, now); !res.Allowed {
+		t.Fatal("first request of a denied")
+	}
+	if res, _ := store.TakeToken(ctx, "a", limit, now); res.Allowed {
+		t.Error("second request of a allowed")
+	}
+	if res, _ := store.TakeToken(ctx, "b", limit, now); !res.Allowed {
+		t.Error("key b shares the bucket of a")
+	}
+
+	// Full buckets are forgotten, partly used ones are kept
+	if err := store.PruneRateLimits(ctx, now.Add(30*time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.buckets) != 2 {
+		t.Errorf("pruned used buckets: %d left", len(store.buckets))
+	}
+	if err := store.PruneRateLimits(ctx, now.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.buckets) != 0 {
+		t.Errorf("kept full buckets: %d left", len(store.buckets))
+	}
+}
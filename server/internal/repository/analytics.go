@@ -28,7 +28,7 @@ func (r *PostgresRepository) TrackPageView(ctx context.Context, page, visitorID,
 		VALUES ($1, $2, $3)
 	`, page, visitorID, device)
 	if err != nil {
-		return err
+		return mapPgError(err)
 	}
 
 	// Update daily stats
@@ -83,7 +83,7 @@ func (r *PostgresRepository) TrackSession(ctx context.Context, visitorID string,
 	`, visitorID, duration, pages, theme, language)
 
 	if err != nil {
-		return err
+		return mapPgError(err)
 	}
 
 	// Only update theme/language stats for new sessions
@@ -186,6 +186,9 @@ func (r *PostgresRepository) GetAnalytics(ctx context.Context) (*entity.Analytic
 		data.TopPages = append(data.TopPages, tp)
 		data.PageViews[tp.Page] = tp.Views
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
 	// Visits by day (last 30 days)
 	dayRows, err := r.pool.Query(ctx, `
@@ -208,6 +211,9 @@ func (r *PostgresRepository) GetAnalytics(ctx context.Context) (*entity.Analytic
 		dv.Date = date.Format("2006-01-02")
 		data.VisitsByDay = append(data.VisitsByDay, dv)
 	}
+	if err := dayRows.Err(); err != nil {
+		return nil, err
+	}
 
 	// Visits by hour (today)
 	hourRows, err := r.pool.Query(ctx, `
@@ -231,6 +237,9 @@ func (r *PostgresRepository) GetAnalytics(ctx context.Context) (*entity.Analytic
 			data.VisitsByHour[hour] = count
 		}
 	}
+	if err := hourRows.Err(); err != nil {
+		return nil, err
+	}
 
 	// Device stats
 	err = r.pool.QueryRow(ctx, `
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"server/internal/entity"
+)
+
+const apiKeyColumns = `id, name, scopes, secret_hash, created_by, created_at, expires_at, last_used_at, revoked_at`
+
+func apiKeyFields(k *entity.APIKey) []interface{} {
+	return []interface{}{
+		&k.ID, &k.Name, &k.Scopes, &k.SecretHash, &k.CreatedBy,
+		&k.CreatedAt, &k.ExpiresAt, &k.LastUsedAt, &k.RevokedAt,
+	}
+}
+
+// CreateAPIKey stores a new key
+func (r *PostgresRepository) CreateAPIKey(ctx context.Context, key entity.APIKey) (entity.APIKey, error) {
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO api_keys (id, name, scopes, secret_hash, created_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at
+	`, key.ID, key.Name, nonNil(key.Scopes), key.SecretHash, key.CreatedBy, key.ExpiresAt).Scan(&key.CreatedAt)
+	return key, mapPgError(err)
+}
+
+// ListAPIKeys returns every key, revoked ones included, newest first
+func (r *PostgresRepository) ListAPIKeys(ctx context.Context) ([]entity.APIKey, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+apiKeyColumns+` FROM api_keys ORDER BY created_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []entity.APIKey{}
+	for rows.Next() {
+		var k entity.APIKey
+		if err := rows.Scan(apiKeyFields(&k)...); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+
+	return keys, rows.Err()
+}
+
+// GetAPIKey returns a key by id
+func (r *PostgresRepository) GetAPIKey(ctx context.Context, id string) (entity.APIKey, error) {
+	var k entity.APIKey
+	err := r.pool.QueryRow(ctx, `SELECT `+apiKeyColumns+` FROM api_keys WHERE id = $1`, id).Scan(apiKeyFields(&k)...)
+	return k, mapPgError(err)
+}
+
+// TouchAPIKey records that the key was used at t
+func (r *PostgresRepository) TouchAPIKey(ctx context.Context, id string, t time.Time) error {
+	_, err := r.pool.Exec(ctx, "UPDATE api_keys SET last_used_at = $2 WHERE id = $1", id, t)
+	return err
+}
+
+// RevokeAPIKey disables a key for good; the row stays for the record
+func (r *PostgresRepository) RevokeAPIKey(ctx context.Context, id string) error {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE api_keys SET revoked_at = COALESCE(revoked_at, CURRENT_TIMESTAMP)
+		WHERE id = $1
+	`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
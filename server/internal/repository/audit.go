@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"server/internal/entity"
+)
+
+// CreateAuditEntry appends an entry to the audit log
+func (r *PostgresRepository) CreateAuditEntry(ctx context.Context, e entity.AuditEntry) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO audit_log (actor, action, section, entity_ids, before_hash, after_hash, status, ip, user_agent, request_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`, e.Actor, e.Action, e.Section, nonNil(e.EntityIDs), e.BeforeHash, e.AfterHash, e.Status, e.IP, e.UserAgent, e.RequestID)
+	return mapPgError(err)
+}
+
+// ListAuditEntries returns entries matching f, newest first
+func (r *PostgresRepository) ListAuditEntries(ctx context.Context, f entity.AuditFilter) ([]entity.AuditEntry, error) {
+	var where []string
+	var args []interface{}
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		where = append(where, fmt.Sprintf(cond, len(args)))
+	}
+	if f.Actor != "" {
+		add("actor = $%d", f.Actor)
+	}
+	if f.Action != "" {
+		add("action = $%d", f.Action)
+	}
+	if f.Section != "" {
+		add("section = $%d", f.Section)
+	}
+	if f.Status != 0 {
+		add("status = $%d", f.Status)
+	}
+	if f.Since != nil {
+		add("created_at >= $%d", *f.Since)
+	}
+	if f.Until != nil {
+		add("created_at < $%d", *f.Until)
+	}
+	if f.BeforeID > 0 {
+		add("id < $%d", f.BeforeID)
+	}
+
+	query := `
+		SELECT id, created_at, actor, action, section, entity_ids, before_hash, after_hash, status, ip, user_agent, request_id
+		FROM audit_log`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	args = append(args, f.Limit)
+	query += fmt.Sprintf(" ORDER BY id DESC LIMIT $%d", len(args))
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []entity.AuditEntry{}
+	for rows.Next() {
+		var e entity.AuditEntry
+		if err := rows.Scan(&e.ID, &e.CreatedAt, &e.Actor, &e.Action, &e.Section, &e.EntityIDs,
+			&e.BeforeHash, &e.AfterHash, &e.Status, &e.IP, &e.UserAgent, &e.RequestID); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+// PruneAuditLog removes entries older than t and returns how many
+func (r *PostgresRepository) PruneAuditLog(ctx context.Context, t time.Time) (int64, error) {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM audit_log WHERE created_at < $1`, t)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"server/internal/entity"
+)
+
+// adminUserFields lists the scan targets of an admin_users row
+func adminUserFields(u *entity.AdminUser) []interface{} {
+	return []interface{}{
+		&u.ID, &u.Username, &u.Role, &u.PasswordHash, &u.CreatedAt,
+		&u.TOTPSecret, &u.TOTPEnabled, &u.TOTPLastStep, &u.RecoveryCodes,
+	}
+}
+
+// CountAdminUsers returns the number of admin accounts
+func (r *PostgresRepository) CountAdminUsers(ctx context.Context) (int, error) {
+	var n int
+	err := r.pool.QueryRow(ctx, "SELECT count(*) FROM admin_users").Scan(&n)
+	return n, err
+}
+
+// ListAdminUsers returns every account by id
+func (r *PostgresRepository) ListAdminUsers(ctx context.Context) ([]entity.AdminUser, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, username, role, password_hash, created_at,
+			totp_secret, totp_enabled, totp_last_step, recovery_codes
+		FROM admin_users ORDER BY id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []entity.AdminUser{}
+	for rows.Next() {
+		var u entity.AdminUser
+		if err := rows.Scan(adminUserFields(&u)...); err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+
+	return users, rows.Err()
+}
+
+// CreateAdminUser adds an account; a taken username is ErrConflict
+func (r *PostgresRepository) CreateAdminUser(ctx context.Context, user entity.AdminUser) (entity.AdminUser, error) {
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO admin_users (username, role, password_hash)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`, user.Username, user.Role, user.PasswordHash).Scan(&user.ID, &user.CreatedAt)
+	return user, mapPgError(err)
+}
+
+// GetAdminUser returns an account by id
+func (r *PostgresRepository) GetAdminUser(ctx context.Context, id int64) (entity.AdminUser, error) {
+	var u entity.AdminUser
+	err := r.pool.QueryRow(ctx, `
+		SELECT id, username, role, password_hash, created_at,
+			totp_secret, totp_enabled, totp_last_step, recovery_codes
+		FROM admin_users WHERE id = $1
+	`, id).Scan(adminUserFields(&u)...)
+	return u, mapPgError(err)
+}
+
+// GetAdminUserByName returns an account by username
+func (r *PostgresRepository) GetAdminUserByName(ctx context.Context, username string) (entity.AdminUser, error) {
+	var u entity.AdminUser
+	err := r.pool.QueryRow(ctx, `
+		SELECT id, username, role, password_hash, created_at,
+			totp_secret, totp_enabled, totp_last_step, recovery_codes
+		FROM admin_users WHERE username = $1
+	`, username).Scan(adminUserFields(&u)...)
+	return u, mapPgError(err)
+}
+
+// UpdateAdminUser saves the role and password hash of an account
+func (r *PostgresRepository) UpdateAdminUser(ctx context.Context, user entity.AdminUser) error {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE admin_users SET role = $2, password_hash = $3 WHERE id = $1
+	`, user.ID, user.Role, user.PasswordHash)
+	if err != nil {
+		return mapPgError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// UpdateAdminUserTOTP saves the TOTP secret, state and recovery codes
+func (r *PostgresRepository) UpdateAdminUserTOTP(ctx context.Context, user entity.AdminUser) error {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE admin_users
+		SET totp_secret = $2, totp_enabled = $3, totp_last_step = $4, recovery_codes = $5
+		WHERE id = $1
+	`, user.ID, user.TOTPSecret, user.TOTPEnabled, user.TOTPLastStep, nonNil(user.RecoveryCodes))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// UseTOTPStep records a login with the code of a time step. The condition
+// makes two logins with the same code race safely.
+func (r *PostgresRepository) UseTOTPStep(ctx context.Context, userID, step int64) error {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE admin_users SET totp_last_step = $2
+		WHERE id = $1 AND totp_last_step < $2
+	`, userID, step)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: one-time code already used", ErrConflict)
+	}
+	return nil
+}
+
+// UseRecoveryCode removes a recovery code
+func (r *PostgresRepository) UseRecoveryCode(ctx context.Context, userID int64, hash string) error {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE admin_users SET recovery_codes = array_remove(recovery_codes, $2)
+		WHERE id = $1 AND $2 = ANY(recovery_codes)
+	`, userID, hash)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// DeleteAdminUser removes an account together with its sessions
+func (r *PostgresRepository) DeleteAdminUser(ctx context.Context, id int64) error {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM admin_users WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// CreateAuthSession stores a new login session
+func (r *PostgresRepository) CreateAuthSession(ctx context.Context, s entity.AuthSession) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO auth_sessions (id, user_id, refresh_hash, expires_at)
+		VALUES ($1, $2, $3, $4)
+	`, s.ID, s.UserID, s.RefreshHash, s.ExpiresAt)
+	return mapPgError(err)
+}
+
+// GetAuthSession returns a session, revoked and expired ones included
+func (r *PostgresRepository) GetAuthSession(ctx context.Context, id string) (entity.AuthSession, error) {
+	var s entity.AuthSession
+	err := r.pool.QueryRow(ctx, `
+		SELECT id, user_id, refresh_hash, expires_at, revoked_at, created_at
+		FROM auth_sessions WHERE id = $1
+	`, id).Scan(&s.ID, &s.UserID, &s.RefreshHash, &s.ExpiresAt, &s.RevokedAt, &s.CreatedAt)
+	return s, mapPgError(err)
+}
+
+// RotateAuthSession replaces the refresh token of an active session. The
+// old hash is part of the condition, so of two concurrent refreshes with
+// the same token only one wins.
+func (r *PostgresRepository) RotateAuthSession(ctx context.Context, id, oldHash, newHash string, expiresAt time.Time) error {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE auth_sessions SET refresh_hash = $3, expires_at = $4
+		WHERE id = $1 AND refresh_hash = $2
+			AND revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+	`, id, oldHash, newHash, expiresAt)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// RevokeAuthSession ends a session; revoking it twice is not an error
+func (r *PostgresRepository) RevokeAuthSession(ctx context.Context, id string) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE auth_sessions SET revoked_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND revoked_at IS NULL
+	`, id)
+	return err
+}
+
+// RevokeAdminUserSessions ends every session of a user
+func (r *PostgresRepository) RevokeAdminUserSessions(ctx context.Context, userID int64) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE auth_sessions SET revoked_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND revoked_at IS NULL
+	`, userID)
+	return err
+}
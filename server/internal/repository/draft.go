@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"server/internal/entity"
+)
+
+// GetDrafts returns all pending drafts
+func (r *PostgresRepository) GetDrafts(ctx context.Context) ([]entity.ContentDraft, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT section, data, author, updated_at
+		FROM content_drafts ORDER BY section
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	drafts := []entity.ContentDraft{}
+	for rows.Next() {
+		var d entity.ContentDraft
+		if err := rows.Scan(&d.Section, &d.Data, &d.Author, &d.UpdatedAt); err != nil {
+			return nil, err
+		}
+		drafts = append(drafts, d)
+	}
+
+	return drafts, rows.Err()
+}
+
+// GetDraft returns the pending draft of a section
+func (r *PostgresRepository) GetDraft(ctx context.Context, section string) (entity.ContentDraft, error) {
+	var d entity.ContentDraft
+	err := r.pool.QueryRow(ctx, `
+		SELECT section, data, author, updated_at
+		FROM content_drafts WHERE section = $1
+	`, section).Scan(&d.Section, &d.Data, &d.Author, &d.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return d, ErrNotFound
+	}
+	return d, err
+}
+
+// SectionVersions returns the version of every section
+func (r *PostgresRepository) SectionVersions(ctx context.Context) (map[string]int64, error) {
+	rows, err := r.pool.Query(ctx, "SELECT section, version FROM content_versions")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := make(map[string]int64, len(entity.Sections))
+	for rows.Next() {
+		var section string
+		var version int64
+		if err := rows.Scan(&section, &version); err != nil {
+			return nil, err
+		}
+		versions[section] = version
+	}
+	return versions, rows.Err()
+}
+
+// SaveDraft stores a new draft of a section, replacing the previous one,
+// if the section is still at version
+func (r *PostgresRepository) SaveDraft(ctx context.Context, draft entity.ContentDraft, version int64) error {
+	if err := checkDraft(draft); err != nil {
+		return err
+	}
+
+	return r.inTx(ctx, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			UPDATE content_versions SET version = version + 1
+			WHERE section = $1 AND version = $2
+		`, draft.Section, version)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("%s: %w", draft.Section, ErrStale)
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO content_drafts (section, data, author)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (section) DO UPDATE SET
+				data = EXCLUDED.data,
+				author = EXCLUDED.author,
+				updated_at = CURRENT_TIMESTAMP
+		`, draft.Section, draft.Data, draft.Author)
+		return err
+	})
+}
+
+// DiscardDraft drops the pending draft of a section
+func (r *PostgresRepository) DiscardDraft(ctx context.Context, section string) error {
+	return r.inTx(ctx, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, "DELETE FROM content_drafts WHERE section = $1", section)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrNotFound
+		}
+		return bumpVersion(ctx, tx, section)
+	})
+}
+
+// bumpVersion moves the version of a section after an unconditional change
+func bumpVersion(ctx context.Context, tx pgx.Tx, section string) error {
+	_, err := tx.Exec(ctx, "UPDATE content_versions SET version = version + 1 WHERE section = $1", section)
+	return err
+}
+
+// PublishDrafts replaces the live content with every pending draft in one
+// transaction and returns the published sections
+func (r *PostgresRepository) PublishDrafts(ctx context.Context) ([]string, error) {
+	var published []string
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, "SELECT section, data FROM content_drafts ORDER BY section FOR UPDATE")
+		if err != nil {
+			return err
+		}
+		drafts, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.ContentDraft, error) {
+			var d entity.ContentDraft
+			err := row.Scan(&d.Section, &d.Data)
+			return d, err
+		})
+		if err != nil {
+			return err
+		}
+
+		var content entity.SiteContent
+		for _, d := range drafts {
+			if err := content.SetSection(d.Section, d.Data); err != nil {
+				return fmt.Errorf("corrupt %s draft: %w", d.Section, err)
+			}
+			if err := checkSection(&content, d.Section); err != nil {
+				return err
+			}
+			if err := r.applySection(ctx, tx, &content, d.Section); err != nil {
+				return err
+			}
+			published = append(published, d.Section)
+		}
+
+		_, err = tx.Exec(ctx, "DELETE FROM content_drafts")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return published, nil
+}
+
+// applySection writes one section of content to the live tables
+func (r *PostgresRepository) applySection(ctx context.Context, tx pgx.Tx, content *entity.SiteContent, section string) error {
+	if err := bumpVersion(ctx, tx, section); err != nil {
+		return err
+	}
+	switch section {
+	case entity.SectionAbout:
+		return r.updateAbout(ctx, tx, content.About)
+	case entity.SectionProjects:
+		return r.updateProjects(ctx, tx, content.Projects)
+	case entity.SectionSkills:
+		return r.updateSkills(ctx, tx, content.Skills)
+	case entity.SectionContacts:
+		return r.updateContacts(ctx, tx, content.Contacts)
+	}
+	return fmt.Errorf("unknown section %q", section)
+}
+
+// checkDraft rejects drafts that could not be published later
+func checkDraft(draft entity.ContentDraft) error {
+	var content entity.SiteContent
+	if err := content.SetSection(draft.Section, draft.Data); err != nil {
+		return err
+	}
+	return checkSection(&content, draft.Section)
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"server/internal/entity"
+)
+
+// ErrNotFound is returned when the requested record does not exist
+var ErrNotFound = errors.New("not found")
+
+// ErrConflict is returned when an update collides with existing rows
+var ErrConflict = errors.New("conflicting update")
+
+// ErrInvalid is returned when the data itself is unacceptable, e.g. it
+// breaks a constraint of the schema
+var ErrInvalid = errors.New("invalid data")
+
+// ErrStale is returned by conditional writes when the record changed after
+// the version they were given was read
+var ErrStale = errors.New("changed by someone else")
+
+// The messages of errors wrapping the four above are meant for the client;
+// anything else is internal and must not leave the server.
+
+// DuplicateIDError is returned when a list update contains the same id twice
+type DuplicateIDError struct {
+	Kind string
+	ID   string
+}
+
+func (e *DuplicateIDError) Error() string {
+	return fmt.Sprintf("duplicate %s id %q", e.Kind, e.ID)
+}
+
+func (e *DuplicateIDError) Unwrap() error {
+	return ErrInvalid
+}
+
+// checkUniqueIDs fails on the first id that appears more than once
+func checkUniqueIDs(kind string, ids []string) error {
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return &DuplicateIDError{Kind: kind, ID: id}
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// ConstraintError is a violated schema constraint. The message is worded
+// for the client; Constraint keeps the raw name for the server log only.
+type ConstraintError struct {
+	Kind error // ErrConflict or ErrInvalid
+	// Field names the offending request field, if there is one
+	Field      string
+	Message    string
+	Constraint string
+}
+
+func (e *ConstraintError) Error() string {
+	return fmt.Sprintf("%v: %s", e.Kind, e.Message)
+}
+
+func (e *ConstraintError) Unwrap() error {
+	return e.Kind
+}
+
+// constraintMessages words the constraints a request can run into.
+// Content ids are checked before writing, so their keys only fire on races.
+var constraintMessages = map[string]struct{ field, message string }{
+	"admin_users_username_key":   {"username", "username is taken"},
+	"admin_users_role_check":     {"role", "unknown role"},
+	"projects_pkey":              {"", "project id already exists"},
+	"skill_categories_pkey":      {"", "skill category id already exists"},
+	"skills_pkey":                {"", "skill id already exists"},
+	"skills_category_id_fkey":    {"", "skill category does not exist"},
+	"contacts_pkey":              {"", "contact id already exists"},
+	"api_keys_pkey":              {"", "key id already exists"},
+	"api_keys_created_by_fkey":   {"", "account does not exist"},
+	"auth_sessions_user_id_fkey": {"", "account does not exist"},
+}
+
+// pgCodeMessages word the violations of constraints without a known name
+var pgCodeMessages = map[string]string{
+	"23505": "value already exists",
+	"23502": "a required value is missing",
+	"23503": "a referenced record does not exist",
+	"23514": "a value is not allowed",
+	"22001": "a value is too long",
+	"22P02": "a value has the wrong format",
+}
+
+// mapPgError converts missing rows and constraint violations into
+// repository errors. Neither the server-side detail, which may echo stored
+// values, nor the constraint name reaches the message.
+func mapPgError(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	var kind error
+	switch pgErr.Code {
+	case "23505": // unique_violation
+		kind = ErrConflict
+	case "23502", "23503", "23514", "22001", "22P02": // not null, foreign key, check, too long, bad text representation
+		kind = ErrInvalid
+	default:
+		return err
+	}
+	cerr := &ConstraintError{Kind: kind, Message: pgCodeMessages[pgErr.Code], Constraint: pgErr.ConstraintName}
+	if m, ok := constraintMessages[pgErr.ConstraintName]; ok {
+		cerr.Field, cerr.Message = m.field, m.message
+	}
+	return cerr
+}
+
+func checkProjectIDs(projects []entity.Project) error {
+	ids := make([]string, len(projects))
+	for i, p := range projects {
+		ids[i] = p.ID
+	}
+	return checkUniqueIDs("project", ids)
+}
+
+func checkContactIDs(contacts []entity.Contact) error {
+	ids := make([]string, len(contacts))
+	for i, c := range contacts {
+		ids[i] = c.ID
+	}
+	return checkUniqueIDs("contact", ids)
+}
+
+// checkSkillIDs checks category ids and skill ids, which share one table each
+func checkSkillIDs(categories []entity.SkillCategory) error {
+	var categoryIDs, skillIDs []string
+	for _, c := range categories {
+		categoryIDs = append(categoryIDs, c.ID)
+		for _, s := range c.Skills {
+			skillIDs = append(skillIDs, s.ID)
+		}
+	}
+	if err := checkUniqueIDs("skill category", categoryIDs); err != nil {
+		return err
+	}
+	return checkUniqueIDs("skill", skillIDs)
+}
+
+// checkSection runs the id checks for one section of c
+func checkSection(c *entity.SiteContent, section string) error {
+	switch section {
+	case entity.SectionProjects:
+		return checkProjectIDs(c.Projects)
+	case entity.SectionSkills:
+		return checkSkillIDs(c.Skills)
+	case entity.SectionContacts:
+		return checkContactIDs(c.Contacts)
+	}
+	return nil
+}
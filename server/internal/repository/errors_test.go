@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestMapPgError(t *testing.T) {
+	if err := mapPgError(fmt.Errorf("about: %w", pgx.ErrNoRows)); !errors.Is(err, ErrNotFound) {
+		t.Errorf("no rows = %v, want ErrNotFound", err)
+	}
+
+	tests := []struct {
+		name      string
+		pgErr     *pgconn.PgError
+		wantKind  error
+		wantField string
+		wantMsg   string
+	}{
+		{
+			name:      "known unique constraint",
+			pgErr:     &pgconn.PgError{Code: "23505", ConstraintName: "admin_users_username_key", Detail: "Key (username)=(alice) already exists."},
+			wantKind:  ErrConflict,
+			wantField: "username",
+			wantMsg:   "username is taken",
+		},
+		{
+			name:     "unknown foreign key",
+			pgErr:    &pgconn.PgError{Code: "23503", ConstraintName: "something_fkey"},
+			wantKind: ErrInvalid,
+			wantMsg:  "a referenced record does not exist",
+		},
+		{
+			name:     "value too long",
+			pgErr:    &pgconn.PgError{Code: "22001"},
+			wantKind: ErrInvalid,
+			wantMsg:  "a value is too long",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := mapPgError(fmt.Errorf("insert: %w", tt.pgErr))
+			var cerr *ConstraintError
+			if !errors.As(err, &cerr) {
+				t.Fatalf("mapPgError() = %v, want *ConstraintError", err)
+			}
+			if !errors.Is(err, tt.wantKind) || cerr.Field != tt.wantField || cerr.Message != tt.wantMsg {
+				t.Errorf("mapPgError() = %+v, want %v %q %q", cerr, tt.wantKind, tt.wantField, tt.wantMsg)
+			}
+			if cerr.Constraint != tt.pgErr.ConstraintName {
+				t.Errorf("Constraint = %q, want %q", cerr.Constraint, tt.pgErr.ConstraintName)
+			}
+			if strings.Contains(err.Error(), "alice") || (tt.pgErr.ConstraintName != "" && strings.Contains(err.Error(), tt.pgErr.ConstraintName)) {
+				t.Errorf("message leaks server detail: %q", err)
+			}
+		})
+	}
+
+	// Other failures stay internal errors
+	other := &pgconn.PgError{Code: "42P01"}
+	if err := mapPgError(other); err != other {
+		t.Errorf("mapPgError(42P01) = %v, want it unchanged", err)
+	}
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"server/internal/entity"
+)
+
+// The benchmarks compare the old per-section/per-category read path with
+// the aggregated single query on a real database, with and without
+// synthetic skill categories:
+//
+//	TEST_DB_URL=postgres://... go test ./internal/repository -run '^$' -bench GetAll -count 10 | benchstat -
+//
+// Use a scratch database: migrations are applied and synthetic categories
+// are added (and removed again afterwards).
+
+const benchPrefix = "bench-"
+
+func BenchmarkGetAll(b *testing.B) {
+	repo := benchRepository(b)
+	ctx := context.Background()
+
+	for _, categories := range []int{0, 50} {
+		addBenchCategories(b, repo.pool, categories, 10)
+
+		b.Run(fmt.Sprintf("legacy/categories=%d", categories), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := legacyGetAll(ctx, repo.pool); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("aggregated/categories=%d", categories), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := repo.GetAll(ctx); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func benchRepository(b *testing.B) *PostgresRepository {
+	b.Helper()
+	repo := testPostgres(b)
+	b.Cleanup(func() {
+		// Their skills go with them through ON DELETE CASCADE
+		if _, err := repo.pool.Exec(context.Background(), "DELETE FROM skill_categories WHERE id LIKE $1", benchPrefix+"%"); err != nil {
+			b.Errorf("remove synthetic categories: %v", err)
+		}
+	})
+	return repo
+}
+
+// addBenchCategories tops the synthetic categories up to total
+func addBenchCategories(b *testing.B, pool *pgxpool.Pool, total, skills int) {
+	b.Helper()
+	ctx := context.Background()
+	for i := 0; i < total; i++ {
+		id := fmt.Sprintf("%s%03d", benchPrefix, i)
+		tag, err := pool.Exec(ctx, `
+			INSERT INTO skill_categories (id, title, badge, sort_order)
+			VALUES ($1, $2, '{}', 1000 + $3)
+			ON CONFLICT (id) DO NOTHING
+		`, id, map[string]string{"ru": id, "en": id}, i)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if tag.RowsAffected() == 0 {
+			continue
+		}
+		for j := 0; j < skills; j++ {
+			_, err := pool.Exec(ctx, `
+				INSERT INTO skills (id, category_id, name, icon, color, sort_order)
+				VALUES ($1, $2, $1, 'SiGo', '#00ADD8', $3)
+			`, fmt.Sprintf("%s-%02d", id, j), id, j)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// legacyGetAll is the read path before aggregation: about, stats, projects
+// and contacts one query each, plus one skills query per category
+func legacyGetAll(ctx context.Context, pool *pgxpool.Pool) (*entity.SiteContent, error) {
+	var content entity.SiteContent
+
+	about := &content.About
+	err := pool.QueryRow(ctx, `
+		SELECT id, name, username, title, bio, photo, updated_at FROM about LIMIT 1
+	`).Scan(&about.ID, &about.Name, &about.Username, &about.Title, &about.Bio, &about.Photo, &about.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := pool.Query(ctx, "SELECT value, label, icon, color FROM stats ORDER BY sort_order")
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var s entity.Stat
+		if err := rows.Scan(&s.Value, &s.Label, &s.Icon, &s.Color); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		about.Stats = append(about.Stats, s)
+	}
+	rows.Close()
+
+	rows, err = pool.Query(ctx, `
+		SELECT id, title, description, categories, image, tags, url, glow_color, featured, sort_order, publish_at, unpublish_at
+		FROM projects ORDER BY sort_order
+	`)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var p entity.Project
+		if err := rows.Scan(&p.ID, &p.Title, &p.Description, &p.Categories, &p.Image, &p.Tags, &p.Url, &p.GlowColor, &p.Featured, &p.Order, &p.PublishAt, &p.UnpublishAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		content.Projects = append(content.Projects, p)
+	}
+	rows.Close()
+
+	rows, err = pool.Query(ctx, `
+		SELECT id, title, badge, is_learning, sort_order, publish_at, unpublish_at
+		FROM skill_categories ORDER BY sort_order
+	`)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var c entity.SkillCategory
+		if err := rows.Scan(&c.ID, &c.Title, &c.Badge, &c.IsLearning, &c.Order, &c.PublishAt, &c.UnpublishAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		content.Skills = append(content.Skills, c)
+	}
+	rows.Close()
+
+	for i := range content.Skills {
+		rows, err := pool.Query(ctx, `
+			SELECT id, name, icon, color FROM skills WHERE category_id = $1 ORDER BY sort_order
+		`, content.Skills[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var s entity.Skill
+			if err := rows.Scan(&s.ID, &s.Name, &s.Icon, &s.Color); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			content.Skills[i].Skills = append(content.Skills[i].Skills, s)
+		}
+		rows.Close()
+	}
+
+	rows, err = pool.Query(ctx, `
+		SELECT id, type, label, value, link, icon, color, sort_order, publish_at, unpublish_at
+		FROM contacts ORDER BY sort_order
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var c entity.Contact
+		if err := rows.Scan(&c.ID, &c.Type, &c.Label, &c.Value, &c.Link, &c.Icon, &c.Color, &c.Order, &c.PublishAt, &c.UnpublishAt); err != nil {
+			return nil, err
+		}
+		content.Contacts = append(content.Contacts, c)
+	}
+
+	return &content, rows.Err()
+}
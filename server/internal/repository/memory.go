@@ -0,0 +1,376 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"server/internal/entity"
+	"server/internal/locale"
+)
+
+// MemoryRepository keeps everything in process memory. It mirrors the
+// semantics of PostgresRepository and is meant for tests and local demos.
+type MemoryRepository struct {
+	mu      sync.RWMutex
+	locales locale.Set
+
+	about    *entity.AboutContent
+	projects []entity.Project
+	skills   []entity.SkillCategory
+	contacts []entity.Contact
+
+	revisions []entity.ContentRevision
+	drafts    map[string]entity.ContentDraft
+	versions  map[string]int64
+
+	pageViews  []memPageView
+	sessions   map[string]*entity.Session
+	dailyStats map[string]*entity.DailyStats
+
+	adminUsers      []entity.AdminUser
+	lastAdminUserID int64
+	authSessions    map[string]entity.AuthSession
+	apiKeys         map[string]entity.APIKey
+
+	auditLog    []entity.AuditEntry
+	lastAuditID int64
+}
+
+type memPageView struct {
+	page      string
+	visitorID string
+	device    string
+	createdAt time.Time
+}
+
+// NewMemoryRepository returns a store seeded with the default site content
+func NewMemoryRepository(locales locale.Set) *MemoryRepository {
+	r := &MemoryRepository{
+		locales:    locales,
+		sessions:   make(map[string]*entity.Session),
+		dailyStats: make(map[string]*entity.DailyStats),
+		drafts:     make(map[string]entity.ContentDraft),
+		versions:   make(map[string]int64),
+
+		authSessions: make(map[string]entity.AuthSession),
+		apiKeys:      make(map[string]entity.APIKey),
+	}
+
+	content := defaultContent()
+	content.About.ID = 1
+	r.about = &entity.AboutContent{ID: 1}
+	r.UpdateAbout(context.Background(), content.About)
+	r.UpdateProjects(context.Background(), content.Projects)
+	r.UpdateSkills(context.Background(), content.Skills)
+	r.UpdateContacts(context.Background(), content.Contacts)
+
+	return r
+}
+
+func (r *MemoryRepository) Close() {}
+
+// GetAbout returns about content
+func (r *MemoryRepository) GetAbout(ctx context.Context) (entity.AboutContent, error) {
+	return r.getAbout(true)
+}
+
+func (r *MemoryRepository) getAbout(visibleOnly bool) (entity.AboutContent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.about == nil {
+		return entity.AboutContent{}, fmt.Errorf("about: %w", ErrNotFound)
+	}
+	about := copyAbout(*r.about)
+	about.MapLocalized(readLocales(r.locales, visibleOnly))
+	return about, nil
+}
+
+// UpdateAbout updates about content
+func (r *MemoryRepository) UpdateAbout(ctx context.Context, about entity.AboutContent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkAbout(about); err != nil {
+		return err
+	}
+	r.setAbout(about)
+	return nil
+}
+
+// checkAbout fails like UPDATE ... WHERE id = $n affecting no rows.
+// Caller must hold r.mu.
+func (r *MemoryRepository) checkAbout(about entity.AboutContent) error {
+	if r.about == nil || r.about.ID != about.ID {
+		return fmt.Errorf("about %d: %w", about.ID, ErrNotFound)
+	}
+	return nil
+}
+
+// setAbout replaces the about row checked by checkAbout. Caller must hold r.mu.
+func (r *MemoryRepository) setAbout(about entity.AboutContent) {
+	updated := r.localizeAbout(about)
+	updated.UpdatedAt = time.Now()
+	r.about = &updated
+	r.versions[entity.SectionAbout]++
+}
+
+// GetProjects returns all currently published projects
+func (r *MemoryRepository) GetProjects(ctx context.Context) ([]entity.Project, error) {
+	return r.getProjects(true), nil
+}
+
+func (r *MemoryRepository) getProjects(visibleOnly bool) []entity.Project {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	var projects []entity.Project
+	for _, p := range r.projects {
+		if visibleOnly && !p.VisibleAt(now) {
+			continue
+		}
+		p = copyProject(p)
+		p.MapLocalized(readLocales(r.locales, visibleOnly))
+		projects = append(projects, p)
+	}
+	sort.SliceStable(projects, func(i, j int) bool { return projects[i].Order < projects[j].Order })
+
+	return projects
+}
+
+// UpdateProjects updates all projects
+func (r *MemoryRepository) UpdateProjects(ctx context.Context, projects []entity.Project) error {
+	if err := checkProjectIDs(projects); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.setProjects(projects)
+	return nil
+}
+
+// Caller must hold r.mu
+func (r *MemoryRepository) setProjects(projects []entity.Project) {
+	stored := make([]entity.Project, 0, len(projects))
+	for i, p := range projects {
+		p = copyProject(p)
+		p.MapLocalized(r.locales.Normalize)
+		p.Order = i + 1
+		stored = append(stored, p)
+	}
+	r.projects = stored
+	r.versions[entity.SectionProjects]++
+}
+
+// GetSkills returns all currently published skill categories with skills
+func (r *MemoryRepository) GetSkills(ctx context.Context) ([]entity.SkillCategory, error) {
+	return r.getSkills(true), nil
+}
+
+func (r *MemoryRepository) getSkills(visibleOnly bool) []entity.SkillCategory {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	var categories []entity.SkillCategory
+	for _, c := range r.skills {
+		if visibleOnly && !c.VisibleAt(now) {
+			continue
+		}
+		c = copySkillCategory(c)
+		c.MapLocalized(readLocales(r.locales, visibleOnly))
+		categories = append(categories, c)
+	}
+	sort.SliceStable(categories, func(i, j int) bool { return categories[i].Order < categories[j].Order })
+
+	return categories
+}
+
+// UpdateSkills updates all skill categories and skills
+func (r *MemoryRepository) UpdateSkills(ctx context.Context, categories []entity.SkillCategory) error {
+	if err := checkSkillIDs(categories); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.setSkills(categories)
+	return nil
+}
+
+// Caller must hold r.mu
+func (r *MemoryRepository) setSkills(categories []entity.SkillCategory) {
+	stored := make([]entity.SkillCategory, 0, len(categories))
+	for i, c := range categories {
+		c = copySkillCategory(c)
+		c.MapLocalized(r.locales.Normalize)
+		c.Order = i + 1
+		stored = append(stored, c)
+	}
+	r.skills = stored
+	r.versions[entity.SectionSkills]++
+}
+
+// GetContacts returns all currently published contacts
+func (r *MemoryRepository) GetContacts(ctx context.Context) ([]entity.Contact, error) {
+	return r.getContacts(true), nil
+}
+
+func (r *MemoryRepository) getContacts(visibleOnly bool) []entity.Contact {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	var contacts []entity.Contact
+	for _, c := range r.contacts {
+		if visibleOnly && !c.VisibleAt(now) {
+			continue
+		}
+		c = copyContact(c)
+		c.MapLocalized(readLocales(r.locales, visibleOnly))
+		contacts = append(contacts, c)
+	}
+	sort.SliceStable(contacts, func(i, j int) bool { return contacts[i].Order < contacts[j].Order })
+
+	return contacts
+}
+
+// UpdateContacts updates all contacts
+func (r *MemoryRepository) UpdateContacts(ctx context.Context, contacts []entity.Contact) error {
+	if err := checkContactIDs(contacts); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.setContacts(contacts)
+	return nil
+}
+
+// Caller must hold r.mu
+func (r *MemoryRepository) setContacts(contacts []entity.Contact) {
+	stored := make([]entity.Contact, 0, len(contacts))
+	for i, c := range contacts {
+		c = copyContact(c)
+		c.MapLocalized(r.locales.Normalize)
+		c.Order = i + 1
+		stored = append(stored, c)
+	}
+	r.contacts = stored
+	r.versions[entity.SectionContacts]++
+}
+
+// GetAll returns all currently published content
+func (r *MemoryRepository) GetAll(ctx context.Context) (*entity.SiteContent, error) {
+	return r.getAll(ctx, true)
+}
+
+// GetEditable returns all content including items outside their publish window
+func (r *MemoryRepository) GetEditable(ctx context.Context) (*entity.SiteContent, error) {
+	return r.getAll(ctx, false)
+}
+
+func (r *MemoryRepository) getAll(ctx context.Context, visibleOnly bool) (*entity.SiteContent, error) {
+	about, err := r.getAbout(visibleOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.SiteContent{
+		About:    about,
+		Projects: r.getProjects(visibleOnly),
+		Skills:   r.getSkills(visibleOnly),
+		Contacts: r.getContacts(visibleOnly),
+	}, nil
+}
+
+// NextScheduleChange returns the earliest publish_at/unpublish_at after t,
+// or nil if nothing is scheduled
+func (r *MemoryRepository) NextScheduleChange(ctx context.Context, t time.Time) (*time.Time, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var next *time.Time
+	consider := func(s entity.Schedule) {
+		for _, at := range []*time.Time{s.PublishAt, s.UnpublishAt} {
+			if at != nil && at.After(t) && (next == nil || at.Before(*next)) {
+				v := *at
+				next = &v
+			}
+		}
+	}
+	for _, p := range r.projects {
+		consider(p.Schedule)
+	}
+	for _, c := range r.skills {
+		consider(c.Schedule)
+	}
+	for _, c := range r.contacts {
+		consider(c.Schedule)
+	}
+
+	return next, nil
+}
+
+// localizeAbout fills in every configured locale, like the Postgres
+// repository does on write
+func (r *MemoryRepository) localizeAbout(a entity.AboutContent) entity.AboutContent {
+	a = copyAbout(a)
+	a.MapLocalized(r.locales.Normalize)
+	return a
+}
+
+func copyLocalized(m map[string]string) map[string]string {
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+func copyAbout(a entity.AboutContent) entity.AboutContent {
+	a.Name = copyLocalized(a.Name)
+	a.Title = copyLocalized(a.Title)
+	a.Bio = copyLocalized(a.Bio)
+	stats := a.Stats
+	a.Stats = nil
+	for _, s := range stats {
+		a.Stats = append(a.Stats, entity.Stat{
+			Value: copyLocalized(s.Value),
+			Label: copyLocalized(s.Label),
+			Icon:  s.Icon,
+			Color: s.Color,
+		})
+	}
+	return a
+}
+
+func copyProject(p entity.Project) entity.Project {
+	p.Title = copyLocalized(p.Title)
+	p.Description = copyLocalized(p.Description)
+	p.Categories = append([]string{}, p.Categories...)
+	p.Tags = append([]string{}, p.Tags...)
+	return p
+}
+
+func copySkillCategory(c entity.SkillCategory) entity.SkillCategory {
+	c.Title = copyLocalized(c.Title)
+	c.Badge = copyLocalized(c.Badge)
+	skills := make([]entity.Skill, len(c.Skills))
+	copy(skills, c.Skills)
+	c.Skills = skills
+	return c
+}
+
+func copyContact(c entity.Contact) entity.Contact {
+	c.Label = copyLocalized(c.Label)
+	return c
+}
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"server/internal/entity"
+)
+
+// TrackPageView records a page view
+func (r *MemoryRepository) TrackPageView(ctx context.Context, page, visitorID, device string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	today := now.Format("2006-01-02")
+
+	isNewVisitor := true
+	for _, pv := range r.pageViews {
+		if pv.visitorID == visitorID && pv.createdAt.Format("2006-01-02") == today {
+			isNewVisitor = false
+			break
+		}
+	}
+
+	r.pageViews = append(r.pageViews, memPageView{
+		page:      page,
+		visitorID: visitorID,
+		device:    device,
+		createdAt: now,
+	})
+
+	stats := r.dailyStatsFor(today)
+	stats.Visits++
+	if isNewVisitor {
+		stats.UniqueVisitors++
+		switch device {
+		case "mobile":
+			stats.MobileCount++
+		case "tablet":
+			stats.TabletCount++
+		default:
+			stats.DesktopCount++
+		}
+	}
+
+	return nil
+}
+
+// TrackSession updates or creates a session
+func (r *MemoryRepository) TrackSession(ctx context.Context, visitorID string, duration, pages int, theme, language string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now().Format(time.RFC3339)
+
+	session, exists := r.sessions[visitorID]
+	if !exists {
+		r.sessions[visitorID] = &entity.Session{
+			ID:              int64(len(r.sessions) + 1),
+			VisitorID:       visitorID,
+			DurationSeconds: duration,
+			PagesCount:      pages,
+			Theme:           theme,
+			Language:        language,
+			CreatedAt:       now,
+			UpdatedAt:       now,
+		}
+	} else {
+		session.DurationSeconds = max(session.DurationSeconds, duration)
+		session.PagesCount = max(session.PagesCount, pages)
+		session.Theme = theme
+		session.Language = language
+		session.UpdatedAt = now
+	}
+
+	// Only update theme/language stats for new sessions
+	if !exists {
+		stats := r.dailyStatsFor(time.Now().Format("2006-01-02"))
+		if theme == "dark" {
+			stats.DarkTheme++
+		} else {
+			stats.LightTheme++
+		}
+		if language == "en" {
+			stats.LangEn++
+		} else {
+			stats.LangRu++
+		}
+	}
+
+	return nil
+}
+
+// GetAnalytics returns aggregated analytics data
+func (r *MemoryRepository) GetAnalytics(ctx context.Context) (*entity.AnalyticsData, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	data := &entity.AnalyticsData{
+		PageViews:    make(map[string]int),
+		TopPages:     []entity.TopPage{},
+		VisitsByDay:  []entity.DayVisits{},
+		VisitsByHour: make([]int, 24),
+	}
+
+	now := time.Now()
+	today := now.Format("2006-01-02")
+	weekAgo := now.AddDate(0, 0, -7).Format("2006-01-02")
+
+	visitors := make(map[string]bool)
+	views := make(map[string]int)
+	for _, pv := range r.pageViews {
+		day := pv.createdAt.Format("2006-01-02")
+		data.TotalVisits++
+		visitors[pv.visitorID] = true
+		views[pv.page]++
+		if day == today {
+			data.TodayVisits++
+			data.VisitsByHour[pv.createdAt.Hour()]++
+		}
+		if day >= weekAgo {
+			data.WeekVisits++
+		}
+	}
+	data.UniqueVisitors = len(visitors)
+
+	// Sessions: average duration and bounce rate (sessions with only 1 page)
+	var totalDuration, bounceSessions int
+	for _, s := range r.sessions {
+		totalDuration += s.DurationSeconds
+		if s.PagesCount <= 1 {
+			bounceSessions++
+		}
+	}
+	if len(r.sessions) > 0 {
+		data.AvgSessionDuration = float64(totalDuration) / float64(len(r.sessions))
+		data.BounceRate = float64(bounceSessions) / float64(len(r.sessions)) * 100
+	}
+
+	// Top pages
+	for page, count := range views {
+		data.TopPages = append(data.TopPages, entity.TopPage{Page: page, Views: count})
+	}
+	sort.Slice(data.TopPages, func(i, j int) bool {
+		if data.TopPages[i].Views != data.TopPages[j].Views {
+			return data.TopPages[i].Views > data.TopPages[j].Views
+		}
+		return data.TopPages[i].Page < data.TopPages[j].Page
+	})
+	if len(data.TopPages) > 10 {
+		data.TopPages = data.TopPages[:10]
+	}
+	for _, tp := range data.TopPages {
+		data.PageViews[tp.Page] = tp.Views
+	}
+
+	// Visits by day (last 30 days) and device/theme/language totals
+	monthAgo := now.AddDate(0, 0, -30).Format("2006-01-02")
+	for date, s := range r.dailyStats {
+		if date >= monthAgo {
+			data.VisitsByDay = append(data.VisitsByDay, entity.DayVisits{Date: date, Visits: s.Visits})
+		}
+		data.Devices.Desktop += s.DesktopCount
+		data.Devices.Mobile += s.MobileCount
+		data.Devices.Tablet += s.TabletCount
+		data.Themes.Light += s.LightTheme
+		data.Themes.Dark += s.DarkTheme
+		data.Languages.Ru += s.LangRu
+		data.Languages.En += s.LangEn
+	}
+	sort.Slice(data.VisitsByDay, func(i, j int) bool {
+		return data.VisitsByDay[i].Date < data.VisitsByDay[j].Date
+	})
+
+	return data, nil
+}
+
+// dailyStatsFor returns the stats row for date, creating it if needed.
+// Caller must hold r.mu.
+func (r *MemoryRepository) dailyStatsFor(date string) *entity.DailyStats {
+	stats, ok := r.dailyStats[date]
+	if !ok {
+		stats = &entity.DailyStats{ID: int64(len(r.dailyStats) + 1), Date: date}
+		r.dailyStats[date] = stats
+	}
+	return stats
+}
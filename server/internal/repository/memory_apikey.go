@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"server/internal/entity"
+)
+
+// CreateAPIKey stores a new key
+func (r *MemoryRepository) CreateAPIKey(ctx context.Context, key entity.APIKey) (entity.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.apiKeys[key.ID]; ok {
+		return key, fmt.Errorf("%w: key %q exists", ErrConflict, key.ID)
+	}
+	key.CreatedAt = time.Now()
+	r.apiKeys[key.ID] = key
+	return key, nil
+}
+
+// ListAPIKeys returns every key, revoked ones included, newest first
+func (r *MemoryRepository) ListAPIKeys(ctx context.Context) ([]entity.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := []entity.APIKey{}
+	for _, k := range r.apiKeys {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.After(keys[j].CreatedAt) })
+
+	return keys, nil
+}
+
+// GetAPIKey returns a key by id
+func (r *MemoryRepository) GetAPIKey(ctx context.Context, id string) (entity.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	k, ok := r.apiKeys[id]
+	if !ok {
+		return k, ErrNotFound
+	}
+	return k, nil
+}
+
+// TouchAPIKey records that the key was used at t
+func (r *MemoryRepository) TouchAPIKey(ctx context.Context, id string, t time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if k, ok := r.apiKeys[id]; ok {
+		k.LastUsedAt = &t
+		r.apiKeys[id] = k
+	}
+	return nil
+}
+
+// RevokeAPIKey disables a key for good; it stays listed for the record
+func (r *MemoryRepository) RevokeAPIKey(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k, ok := r.apiKeys[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if k.RevokedAt == nil {
+		now := time.Now()
+		k.RevokedAt = &now
+		r.apiKeys[id] = k
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"server/internal/entity"
+)
+
+// CreateAuditEntry appends an entry to the audit log
+func (r *MemoryRepository) CreateAuditEntry(ctx context.Context, e entity.AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastAuditID++
+	e.ID = r.lastAuditID
+	e.CreatedAt = time.Now()
+	e.EntityIDs = append([]string{}, e.EntityIDs...)
+	r.auditLog = append(r.auditLog, e)
+
+	return nil
+}
+
+// ListAuditEntries returns entries matching f, newest first
+func (r *MemoryRepository) ListAuditEntries(ctx context.Context, f entity.AuditFilter) ([]entity.AuditEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := []entity.AuditEntry{}
+	for i := len(r.auditLog) - 1; i >= 0 && len(entries) < f.Limit; i-- {
+		e := r.auditLog[i]
+		switch {
+		case f.Actor != "" && e.Actor != f.Actor,
+			f.Action != "" && e.Action != f.Action,
+			f.Section != "" && e.Section != f.Section,
+			f.Status != 0 && e.Status != f.Status,
+			f.Since != nil && e.CreatedAt.Before(*f.Since),
+			f.Until != nil && !e.CreatedAt.Before(*f.Until),
+			f.BeforeID > 0 && e.ID >= f.BeforeID:
+			continue
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+// PruneAuditLog removes entries older than t and returns how many
+func (r *MemoryRepository) PruneAuditLog(ctx context.Context, t time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.auditLog[:0]
+	for _, e := range r.auditLog {
+		if !e.CreatedAt.Before(t) {
+			kept = append(kept, e)
+		}
+	}
+	removed := int64(len(r.auditLog) - len(kept))
+	r.auditLog = kept
+
+	return removed, nil
+}
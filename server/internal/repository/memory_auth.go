@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"server/internal/entity"
+)
+
+// CountAdminUsers returns the number of admin accounts
+func (r *MemoryRepository) CountAdminUsers(ctx context.Context) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.adminUsers), nil
+}
+
+// ListAdminUsers returns every account by id
+func (r *MemoryRepository) ListAdminUsers(ctx context.Context) ([]entity.AdminUser, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]entity.AdminUser{}, r.adminUsers...), nil
+}
+
+// CreateAdminUser adds an account; a taken username is ErrConflict
+func (r *MemoryRepository) CreateAdminUser(ctx context.Context, user entity.AdminUser) (entity.AdminUser, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, u := range r.adminUsers {
+		if u.Username == user.Username {
+			return user, &ConstraintError{Kind: ErrConflict, Field: "username", Message: "username is taken", Constraint: "admin_users_username_key"}
+		}
+	}
+	r.lastAdminUserID++
+	user.ID = r.lastAdminUserID
+	user.CreatedAt = time.Now()
+	r.adminUsers = append(r.adminUsers, user)
+	return user, nil
+}
+
+// GetAdminUser returns an account by id
+func (r *MemoryRepository) GetAdminUser(ctx context.Context, id int64) (entity.AdminUser, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, u := range r.adminUsers {
+		if u.ID == id {
+			return u, nil
+		}
+	}
+	return entity.AdminUser{}, ErrNotFound
+}
+
+// GetAdminUserByName returns an account by username
+func (r *MemoryRepository) GetAdminUserByName(ctx context.Context, username string) (entity.AdminUser, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, u := range r.adminUsers {
+		if u.Username == username {
+			return u, nil
+		}
+	}
+	return entity.AdminUser{}, ErrNotFound
+}
+
+// UpdateAdminUser saves the role and password hash of an account
+func (r *MemoryRepository) UpdateAdminUser(ctx context.Context, user entity.AdminUser) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, u := range r.adminUsers {
+		if u.ID == user.ID {
+			r.adminUsers[i].Role = user.Role
+			r.adminUsers[i].PasswordHash = user.PasswordHash
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// UpdateAdminUserTOTP saves the TOTP secret, state and recovery codes
+func (r *MemoryRepository) UpdateAdminUserTOTP(ctx context.Context, user entity.AdminUser) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, u := range r.adminUsers {
+		if u.ID == user.ID {
+			r.adminUsers[i].TOTPSecret = user.TOTPSecret
+			r.adminUsers[i].TOTPEnabled = user.TOTPEnabled
+			r.adminUsers[i].TOTPLastStep = user.TOTPLastStep
+			r.adminUsers[i].RecoveryCodes = append([]string{}, user.RecoveryCodes...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// UseTOTPStep records a login with the code of a time step
+func (r *MemoryRepository) UseTOTPStep(ctx context.Context, userID, step int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, u := range r.adminUsers {
+		if u.ID == userID {
+			if u.TOTPLastStep >= step {
+				return fmt.Errorf("%w: one-time code already used", ErrConflict)
+			}
+			r.adminUsers[i].TOTPLastStep = step
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// UseRecoveryCode removes a recovery code
+func (r *MemoryRepository) UseRecoveryCode(ctx context.Context, userID int64, hash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, u := range r.adminUsers {
+		if u.ID != userID {
+			continue
+		}
+		for j, h := range u.RecoveryCodes {
+			if h == hash {
+				codes := append([]string{}, u.RecoveryCodes[:j]...)
+				r.adminUsers[i].RecoveryCodes = append(codes, u.RecoveryCodes[j+1:]...)
+				return nil
+			}
+		}
+	}
+	return ErrNotFound
+}
+
+// DeleteAdminUser removes an account together with its sessions
+func (r *MemoryRepository) DeleteAdminUser(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, u := range r.adminUsers {
+		if u.ID == id {
+			r.adminUsers = append(r.adminUsers[:i], r.adminUsers[i+1:]...)
+			for sid, s := range r.authSessions {
+				if s.UserID == id {
+					delete(r.authSessions, sid)
+				}
+			}
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// CreateAuthSession stores a new login session
+func (r *MemoryRepository) CreateAuthSession(ctx context.Context, s entity.AuthSession) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.authSessions[s.ID]; ok {
+		return fmt.Errorf("%w: session %q exists", ErrConflict, s.ID)
+	}
+	s.CreatedAt = time.Now()
+	r.authSessions[s.ID] = s
+	return nil
+}
+
+// GetAuthSession returns a session, revoked and expired ones included
+func (r *MemoryRepository) GetAuthSession(ctx context.Context, id string) (entity.AuthSession, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, ok := r.authSessions[id]
+	if !ok {
+		return s, ErrNotFound
+	}
+	return s, nil
+}
+
+// RotateAuthSession replaces the refresh token of an active session
+func (r *MemoryRepository) RotateAuthSession(ctx context.Context, id, oldHash, newHash string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.authSessions[id]
+	if !ok || s.RefreshHash != oldHash || !s.Active(time.Now()) {
+		return ErrNotFound
+	}
+	s.RefreshHash, s.ExpiresAt = newHash, expiresAt
+	r.authSessions[id] = s
+	return nil
+}
+
+// RevokeAuthSession ends a session; revoking it twice is not an error
+func (r *MemoryRepository) RevokeAuthSession(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if s, ok := r.authSessions[id]; ok && s.RevokedAt == nil {
+		now := time.Now()
+		s.RevokedAt = &now
+		r.authSessions[id] = s
+	}
+	return nil
+}
+
+// RevokeAdminUserSessions ends every session of a user
+func (r *MemoryRepository) RevokeAdminUserSessions(ctx context.Context, userID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for id, s := range r.authSessions {
+		if s.UserID == userID && s.RevokedAt == nil {
+			s.RevokedAt = &now
+			r.authSessions[id] = s
+		}
+	}
+	return nil
+}
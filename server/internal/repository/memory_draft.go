@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"server/internal/entity"
+)
+
+// GetDrafts returns all pending drafts
+func (r *MemoryRepository) GetDrafts(ctx context.Context) ([]entity.ContentDraft, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	drafts := []entity.ContentDraft{}
+	for _, d := range r.drafts {
+		drafts = append(drafts, d)
+	}
+	sort.Slice(drafts, func(i, j int) bool { return drafts[i].Section < drafts[j].Section })
+
+	return drafts, nil
+}
+
+// GetDraft returns the pending draft of a section
+func (r *MemoryRepository) GetDraft(ctx context.Context, section string) (entity.ContentDraft, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	d, ok := r.drafts[section]
+	if !ok {
+		return d, ErrNotFound
+	}
+	return d, nil
+}
+
+// SectionVersions returns the version of every section
+func (r *MemoryRepository) SectionVersions(ctx context.Context) (map[string]int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	versions := make(map[string]int64, len(entity.Sections))
+	for _, section := range entity.Sections {
+		versions[section] = r.versions[section]
+	}
+	return versions, nil
+}
+
+// SaveDraft stores a new draft of a section, replacing the previous one,
+// if the section is still at version
+func (r *MemoryRepository) SaveDraft(ctx context.Context, draft entity.ContentDraft, version int64) error {
+	if err := checkDraft(draft); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.versions[draft.Section] != version {
+		return fmt.Errorf("%s: %w", draft.Section, ErrStale)
+	}
+	r.versions[draft.Section]++
+	draft.Data = append(json.RawMessage{}, draft.Data...)
+	draft.UpdatedAt = time.Now()
+	r.drafts[draft.Section] = draft
+
+	return nil
+}
+
+// DiscardDraft drops the pending draft of a section
+func (r *MemoryRepository) DiscardDraft(ctx context.Context, section string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.drafts[section]; !ok {
+		return ErrNotFound
+	}
+	delete(r.drafts, section)
+	r.versions[section]++
+
+	return nil
+}
+
+// PublishDrafts replaces the live content with every pending draft at once
+// and returns the published sections
+func (r *MemoryRepository) PublishDrafts(ctx context.Context) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Validate everything before touching live content
+	var content entity.SiteContent
+	var published []string
+	for section, d := range r.drafts {
+		if err := content.SetSection(section, d.Data); err != nil {
+			return nil, fmt.Errorf("corrupt %s draft: %w", section, err)
+		}
+		if err := checkSection(&content, section); err != nil {
+			return nil, err
+		}
+		if section == entity.SectionAbout {
+			if err := r.checkAbout(content.About); err != nil {
+				return nil, err
+			}
+		}
+		published = append(published, section)
+	}
+	sort.Strings(published)
+
+	for _, section := range published {
+		switch section {
+		case entity.SectionAbout:
+			r.setAbout(content.About)
+		case entity.SectionProjects:
+			r.setProjects(content.Projects)
+		case entity.SectionSkills:
+			r.setSkills(content.Skills)
+		case entity.SectionContacts:
+			r.setContacts(content.Contacts)
+		}
+	}
+	r.drafts = make(map[string]entity.ContentDraft)
+
+	return published, nil
+}
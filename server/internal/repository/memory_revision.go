@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"server/internal/entity"
+)
+
+// CreateRevision stores a snapshot of a content section
+func (r *MemoryRepository) CreateRevision(ctx context.Context, rev entity.ContentRevision) (entity.ContentRevision, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rev.ID = int64(len(r.revisions) + 1)
+	rev.CreatedAt = time.Now()
+	rev.Snapshot = append(json.RawMessage{}, rev.Snapshot...)
+	r.revisions = append(r.revisions, rev)
+
+	return rev, nil
+}
+
+// ListRevisions returns the latest revisions of a section without snapshots
+func (r *MemoryRepository) ListRevisions(ctx context.Context, section string, limit int) ([]entity.ContentRevision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	revisions := []entity.ContentRevision{}
+	for i := len(r.revisions) - 1; i >= 0 && len(revisions) < limit; i-- {
+		rev := r.revisions[i]
+		if rev.Section != section {
+			continue
+		}
+		rev.Snapshot = nil
+		revisions = append(revisions, rev)
+	}
+
+	return revisions, nil
+}
+
+// GetRevision returns a single revision including its snapshot
+func (r *MemoryRepository) GetRevision(ctx context.Context, section string, id int64) (entity.ContentRevision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, rev := range r.revisions {
+		if rev.ID == id && rev.Section == section {
+			return rev, nil
+		}
+	}
+
+	return entity.ContentRevision{}, ErrNotFound
+}
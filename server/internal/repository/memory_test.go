@@ -0,0 +1,396 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"server/internal/entity"
+	"server/internal/locale"
+)
+
+func TestMemoryKeepsListOrder(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRepository(locale.Default)
+
+	// Order fields of the input are ignored, like sort_order is rewritten
+	// from the position on every update
+	projects := []entity.Project{{ID: "c", Order: 1}, {ID: "a", Order: 7}, {ID: "b", Order: 3}}
+	if err := repo.UpdateProjects(ctx, projects); err != nil {
+		t.Fatal(err)
+	}
+	got, err := repo.GetProjects(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"c", "a", "b"}
+	if len(got) != len(want) {
+		t.Fatalf("got %d projects, want %d", len(got), len(want))
+	}
+	for i, p := range got {
+		if p.ID != want[i] || p.Order != i+1 {
+			t.Errorf("project %d = %s with order %d, want %s with order %d", i, p.ID, p.Order, want[i], i+1)
+		}
+	}
+
+	contacts := []entity.Contact{{ID: "mail"}, {ID: "github"}}
+	if err := repo.UpdateContacts(ctx, contacts); err != nil {
+		t.Fatal(err)
+	}
+	all, err := repo.GetAll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all.Contacts) != 2 || all.Contacts[0].ID != "mail" || all.Contacts[1].Order != 2 {
+		t.Errorf("GetAll contacts = %+v", all.Contacts)
+	}
+}
+
+func TestMemoryHidesScheduledItems(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRepository(locale.Default)
+
+	later := time.Now().Add(time.Hour)
+	projects := []entity.Project{{ID: "live"}, {ID: "later", Schedule: entity.Schedule{PublishAt: &later}}}
+	if err := repo.UpdateProjects(ctx, projects); err != nil {
+		t.Fatal(err)
+	}
+
+	visible, _ := repo.GetAll(ctx)
+	editable, _ := repo.GetEditable(ctx)
+	if len(visible.Projects) != 1 || visible.Projects[0].ID != "live" {
+		t.Errorf("visible projects = %+v", visible.Projects)
+	}
+	if len(editable.Projects) != 2 {
+		t.Errorf("editable projects = %+v", editable.Projects)
+	}
+	if next, _ := repo.NextScheduleChange(ctx, time.Now()); next == nil || !next.Equal(later) {
+		t.Errorf("NextScheduleChange() = %v, want %v", next, later)
+	}
+}
+
+func TestMemoryReturnsCopies(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRepository(locale.Default)
+
+	projects, _ := repo.GetProjects(ctx)
+	if len(projects) == 0 {
+		t.Fatal("no seeded projects")
+	}
+	title := projects[0].Title["en"]
+	projects[0].Title["en"] = "changed"
+	projects[0].Tags = append(projects[0].Tags[:0], "changed")
+
+	again, _ := repo.GetProjects(ctx)
+	if again[0].Title["en"] != title {
+		t.Error("changing a returned project changed the store")
+	}
+}
+
+func TestMemoryNotFound(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRepository(locale.Default)
+
+	about, err := repo.GetAbout(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	about.ID++
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"UpdateAbout with another id", func() error { return repo.UpdateAbout(ctx, about) }},
+		{"GetDraft", func() error { _, err := repo.GetDraft(ctx, entity.SectionProjects); return err }},
+		{"DiscardDraft", func() error { return repo.DiscardDraft(ctx, entity.SectionProjects) }},
+		{"GetRevision", func() error { _, err := repo.GetRevision(ctx, entity.SectionAbout, 1); return err }},
+		{"GetAdminUser", func() error { _, err := repo.GetAdminUser(ctx, 42); return err }},
+		{"DeleteAdminUser", func() error { return repo.DeleteAdminUser(ctx, 42) }},
+		{"RevokeAPIKey", func() error { return repo.RevokeAPIKey(ctx, "missing") }},
+	}
+	for _, tt := range tests {
+		if err := tt.call(); !errors.Is(err, ErrNotFound) {
+			t.Errorf("%s: %v, want ErrNotFound", tt.name, err)
+		}
+	}
+}
+
+func TestMemoryDuplicateIDs(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRepository(locale.Default)
+	before, _ := repo.GetProjects(ctx)
+
+	err := repo.UpdateProjects(ctx, []entity.Project{{ID: "a"}, {ID: "a"}})
+	var dup *DuplicateIDError
+	if !errors.As(err, &dup) || dup.ID != "a" || !errors.Is(err, ErrInvalid) {
+		t.Fatalf("UpdateProjects() = %v, want a duplicate id error", err)
+	}
+	after, _ := repo.GetProjects(ctx)
+	if len(after) != len(before) {
+		t.Errorf("failed update changed the projects: %d -> %d", len(before), len(after))
+	}
+}
+
+func TestMemoryTrackSession(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRepository(locale.Default)
+
+	// The second call updates the same session: duration and pages only
+	// grow, theme and language follow the latest value
+	if err := repo.TrackSession(ctx, "v1", 30, 3, "dark", "en"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.TrackSession(ctx, "v1", 10, 1, "light", "ru"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.TrackSession(ctx, "v2", 50, 1, "light", "ru"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(repo.sessions) != 2 {
+		t.Fatalf("%d sessions, want 2", len(repo.sessions))
+	}
+	s := repo.sessions["v1"]
+	if s.DurationSeconds != 30 || s.PagesCount != 3 || s.Theme != "light" || s.Language != "ru" {
+		t.Errorf("session v1 = %+v", s)
+	}
+
+	data, err := repo.GetAnalytics(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.AvgSessionDuration != 40 {
+		t.Errorf("AvgSessionDuration = %v, want 40", data.AvgSessionDuration)
+	}
+	if data.BounceRate != 50 {
+		t.Errorf("BounceRate = %v, want 50", data.BounceRate)
+	}
+	// Theme and language are counted once per session, when it starts
+	if data.Themes.Dark != 1 || data.Themes.Light != 1 || data.Languages.En != 1 || data.Languages.Ru != 1 {
+		t.Errorf("themes %+v, languages %+v", data.Themes, data.Languages)
+	}
+}
+
+func TestMemoryTrackPageView(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRepository(locale.Default)
+
+	views := []struct{ page, visitor, device string }{
+		{"/", "v1", "mobile"},
+		{"/projects", "v1", "mobile"},
+		{"/", "v2", "desktop"},
+		{"/", "v3", "tablet"},
+	}
+	for _, v := range views {
+		if err := repo.TrackPageView(ctx, v.page, v.visitor, v.device); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := repo.GetAnalytics(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.TotalVisits != 4 || data.UniqueVisitors != 3 || data.TodayVisits != 4 {
+		t.Errorf("visits %d, unique %d, today %d", data.TotalVisits, data.UniqueVisitors, data.TodayVisits)
+	}
+	// Devices count visitors, not page views
+	if data.Devices.Mobile != 1 || data.Devices.Desktop != 1 || data.Devices.Tablet != 1 {
+		t.Errorf("devices = %+v", data.Devices)
+	}
+	if len(data.TopPages) == 0 || data.TopPages[0].Page != "/" || data.TopPages[0].Views != 3 {
+		t.Errorf("top pages = %+v", data.TopPages)
+	}
+}
+
+func TestMemoryPublishDraftsIsAtomic(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRepository(locale.Default)
+	before, _ := repo.GetProjects(ctx)
+
+	save := func(section string, v interface{}) {
+		t.Helper()
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.SaveDraft(ctx, entity.ContentDraft{Section: section, Data: data}, sectionVersion(t, repo, section)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	about, _ := repo.GetAbout(ctx)
+	about.Username = "@draft"
+	about.ID++
+	save(entity.SectionProjects, []entity.Project{{ID: "only"}})
+	save(entity.SectionAbout, about)
+
+	// The about draft names a row that does not exist, so nothing goes live
+	if _, err := repo.PublishDrafts(ctx); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("PublishDrafts() = %v, want ErrNotFound", err)
+	}
+	if after, _ := repo.GetProjects(ctx); len(after) != len(before) {
+		t.Error("failed publish changed the projects")
+	}
+	if drafts, _ := repo.GetDrafts(ctx); len(drafts) != 2 {
+		t.Errorf("failed publish dropped drafts: %d left", len(drafts))
+	}
+
+	about.ID--
+	save(entity.SectionAbout, about)
+	published, err := repo.PublishDrafts(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(published) != 2 || published[0] != entity.SectionAbout || published[1] != entity.SectionProjects {
+		t.Errorf("published %v", published)
+	}
+	if got, _ := repo.GetAbout(ctx); got.Username != "@draft" {
+		t.Errorf("username %q after publish", got.Username)
+	}
+	if drafts, _ := repo.GetDrafts(ctx); len(drafts) != 0 {
+		t.Errorf("%d drafts left after publish", len(drafts))
+	}
+}
+
+func TestMemorySaveDraftChecksIDs(t *testing.T) {
+	repo := NewMemoryRepository(locale.Default)
+	draft := entity.ContentDraft{Section: entity.SectionContacts, Data: []byte(`[{"id":"a"},{"id":"a"}]`)}
+	if err := repo.SaveDraft(context.Background(), draft, 0); !errors.Is(err, ErrInvalid) {
+		t.Errorf("SaveDraft() = %v, want ErrInvalid", err)
+	}
+}
+
+func sectionVersion(t *testing.T, repo DraftStore, section string) int64 {
+	t.Helper()
+	versions, err := repo.SectionVersions(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return versions[section]
+}
+
+func TestMemorySaveDraftChecksVersion(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRepository(locale.Default)
+	draft := entity.ContentDraft{Section: entity.SectionContacts, Data: []byte(`[]`)}
+
+	version := sectionVersion(t, repo, entity.SectionContacts)
+	if err := repo.SaveDraft(ctx, draft, version); err != nil {
+		t.Fatal(err)
+	}
+	// A second writer that read the same version lost the race
+	if err := repo.SaveDraft(ctx, draft, version); !errors.Is(err, ErrStale) {
+		t.Fatalf("SaveDraft() with a used version = %v, want ErrStale", err)
+	}
+
+	// Every other change moves the version as well
+	version = sectionVersion(t, repo, entity.SectionContacts)
+	changes := []struct {
+		name string
+		call func() error
+	}{
+		{"DiscardDraft", func() error { return repo.DiscardDraft(ctx, entity.SectionContacts) }},
+		{"UpdateContacts", func() error { return repo.UpdateContacts(ctx, nil) }},
+		{"PublishDrafts", func() error {
+			if err := repo.SaveDraft(ctx, draft, sectionVersion(t, repo, entity.SectionContacts)); err != nil {
+				return err
+			}
+			_, err := repo.PublishDrafts(ctx)
+			return err
+		}},
+	}
+	for _, c := range changes {
+		if err := c.call(); err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		next := sectionVersion(t, repo, entity.SectionContacts)
+		if next <= version {
+			t.Errorf("%s kept version %d", c.name, version)
+		}
+		version = next
+	}
+	if v := sectionVersion(t, repo, entity.SectionAbout); v != 1 {
+		t.Errorf("about version = %d, want 1 from the seed", v)
+	}
+}
+
+func TestMemoryKeepsOtherLocales(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRepository(locale.Default)
+
+	// "de" is not configured: saving keeps it for the editors, the site
+	// does not see it
+	projects := []entity.Project{{ID: "a", Title: map[string]string{"ru": "Проект", "de": "Projekt"}}}
+	if err := repo.UpdateProjects(ctx, projects); err != nil {
+		t.Fatal(err)
+	}
+
+	editable, _ := repo.GetEditable(ctx)
+	if got, want := editable.Projects[0].Title, map[string]string{"ru": "Проект", "en": "", "de": "Projekt"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("editable title = %v, want %v", got, want)
+	}
+	visible, _ := repo.GetProjects(ctx)
+	if got, want := visible[0].Title, map[string]string{"ru": "Проект", "en": ""}; !reflect.DeepEqual(got, want) {
+		t.Errorf("published title = %v, want %v", got, want)
+	}
+}
+
+func TestMemoryAuditLog(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRepository(locale.Default)
+	for _, e := range []entity.AuditEntry{
+		{Actor: "alice", Action: "PUT /api/content/about", Section: "about", Status: 200},
+		{Actor: "bob", Action: "POST /api/auth/login", Status: 401},
+		{Actor: "alice", Action: "DELETE /api/content/{section}/{id}", Section: "projects", Status: 204},
+	} {
+		if err := repo.CreateAuditEntry(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ids := func(f entity.AuditFilter) []int64 {
+		t.Helper()
+		if f.Limit == 0 {
+			f.Limit = 10
+		}
+		entries, err := repo.ListAuditEntries(ctx, f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out := []int64{}
+		for _, e := range entries {
+			out = append(out, e.ID)
+		}
+		return out
+	}
+	tests := []struct {
+		name   string
+		filter entity.AuditFilter
+		want   []int64
+	}{
+		{name: "newest first", want: []int64{3, 2, 1}},
+		{name: "actor", filter: entity.AuditFilter{Actor: "alice"}, want: []int64{3, 1}},
+		{name: "section", filter: entity.AuditFilter{Section: "about"}, want: []int64{1}},
+		{name: "status", filter: entity.AuditFilter{Status: 401}, want: []int64{2}},
+		{name: "page", filter: entity.AuditFilter{BeforeID: 3, Limit: 1}, want: []int64{2}},
+	}
+	for _, tt := range tests {
+		if got := ids(tt.filter); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: ids = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if removed, err := repo.PruneAuditLog(ctx, time.Now().Add(-time.Hour)); err != nil || removed != 0 {
+		t.Errorf("prune of older entries = %d, %v, want 0", removed, err)
+	}
+	if removed, err := repo.PruneAuditLog(ctx, time.Now().Add(time.Second)); err != nil || removed != 3 {
+		t.Errorf("prune of all entries = %d, %v, want 3", removed, err)
+	}
+	if got := ids(entity.AuditFilter{}); len(got) != 0 {
+		t.Errorf("ids after prune = %v", got)
+	}
+}
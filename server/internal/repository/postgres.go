@@ -3,18 +3,55 @@ package repository
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"server/internal/entity"
+	"server/internal/locale"
+	"server/internal/logging"
+	"server/internal/migrate"
+	"server/migrations"
 )
 
 type PostgresRepository struct {
-	pool *pgxpool.Pool
+	pool    *pgxpool.Pool
+	locales locale.Set
 }
 
-func NewPostgresRepository(ctx context.Context, connString string) (*PostgresRepository, error) {
-	pool, err := pgxpool.New(ctx, connString)
+// PoolOptions sizes the connection pool; zero fields keep the pgx defaults
+type PoolOptions struct {
+	MaxConns        int32
+	MinConns        int32
+	MaxConnLifetime time.Duration
+	MaxConnIdleTime time.Duration
+	// Tracer observes every query, e.g. for metrics
+	Tracer pgx.QueryTracer
+}
+
+func NewPostgresRepository(ctx context.Context, connString string, options PoolOptions, locales locale.Set) (*PostgresRepository, error) {
+	config, err := pgxpool.ParseConfig(connString)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse database url: %w", err)
+	}
+	if options.MaxConns > 0 {
+		config.MaxConns = options.MaxConns
+	}
+	if options.MinConns > 0 {
+		config.MinConns = options.MinConns
+	}
+	if options.MaxConnLifetime > 0 {
+		config.MaxConnLifetime = options.MaxConnLifetime
+	}
+	if options.MaxConnIdleTime > 0 {
+		config.MaxConnIdleTime = options.MaxConnIdleTime
+	}
+	if options.Tracer != nil {
+		config.ConnConfig.Tracer = options.Tracer
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create pool: %w", err)
 	}
@@ -24,8 +61,8 @@ func NewPostgresRepository(ctx context.Context, connString string) (*PostgresRep
 		return nil, fmt.Errorf("failed to ping: %w", err)
 	}
 
-	repo := &PostgresRepository{pool: pool}
-	
+	repo := &PostgresRepository{pool: pool, locales: locales}
+
 	if err := repo.migrate(ctx); err != nil {
 		pool.Close()
 		return nil, fmt.Errorf("failed to migrate: %w", err)
@@ -35,122 +72,15 @@ func NewPostgresRepository(ctx context.Context, connString string) (*PostgresRep
 }
 
 func (r *PostgresRepository) migrate(ctx context.Context) error {
-	schema := `
-	CREATE TABLE IF NOT EXISTS about (
-		id SERIAL PRIMARY KEY,
-		name_ru TEXT NOT NULL DEFAULT '',
-		name_en TEXT NOT NULL DEFAULT '',
-		username TEXT NOT NULL DEFAULT '',
-		title_ru TEXT NOT NULL DEFAULT '',
-		title_en TEXT NOT NULL DEFAULT '',
-		bio_ru TEXT NOT NULL DEFAULT '',
-		bio_en TEXT NOT NULL DEFAULT '',
-		photo TEXT NOT NULL DEFAULT '/logo.png',
-		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-	);
-
-	CREATE TABLE IF NOT EXISTS stats (
-		id SERIAL PRIMARY KEY,
-		value_ru TEXT NOT NULL,
-		value_en TEXT NOT NULL,
-		label_ru TEXT NOT NULL,
-		label_en TEXT NOT NULL,
-		icon TEXT NOT NULL,
-		color TEXT NOT NULL,
-		sort_order INT NOT NULL DEFAULT 0
-	);
-
-	CREATE TABLE IF NOT EXISTS projects (
-		id TEXT PRIMARY KEY,
-		title_ru TEXT NOT NULL,
-		title_en TEXT NOT NULL,
-		description_ru TEXT NOT NULL DEFAULT '',
-		description_en TEXT NOT NULL DEFAULT '',
-		categories TEXT[] NOT NULL DEFAULT '{}',
-		image TEXT NOT NULL DEFAULT '',
-		tags TEXT[] NOT NULL DEFAULT '{}',
-		url TEXT NOT NULL DEFAULT '',
-		glow_color TEXT NOT NULL DEFAULT '#3178C6',
-		featured BOOLEAN NOT NULL DEFAULT false,
-		sort_order INT NOT NULL DEFAULT 0,
-		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-	);
-
-	CREATE TABLE IF NOT EXISTS skill_categories (
-		id TEXT PRIMARY KEY,
-		title_ru TEXT NOT NULL,
-		title_en TEXT NOT NULL,
-		badge_ru TEXT DEFAULT '',
-		badge_en TEXT DEFAULT '',
-		is_learning BOOLEAN NOT NULL DEFAULT false,
-		sort_order INT NOT NULL DEFAULT 0
-	);
-
-	CREATE TABLE IF NOT EXISTS skills (
-		id TEXT PRIMARY KEY,
-		category_id TEXT NOT NULL REFERENCES skill_categories(id) ON DELETE CASCADE,
-		name TEXT NOT NULL,
-		icon TEXT NOT NULL,
-		color TEXT NOT NULL,
-		sort_order INT NOT NULL DEFAULT 0
-	);
-
-	CREATE TABLE IF NOT EXISTS contacts (
-		id TEXT PRIMARY KEY,
-		type TEXT NOT NULL DEFAULT 'social',
-		label_ru TEXT NOT NULL,
-		label_en TEXT NOT NULL,
-		value TEXT NOT NULL,
-		link TEXT NOT NULL,
-		icon TEXT NOT NULL,
-		color TEXT NOT NULL,
-		sort_order INT NOT NULL DEFAULT 0
-	);
-
-	CREATE TABLE IF NOT EXISTS page_views (
-		id SERIAL PRIMARY KEY,
-		page TEXT NOT NULL,
-		visitor_id TEXT NOT NULL,
-		device TEXT NOT NULL DEFAULT 'desktop',
-		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-	);
-
-	CREATE TABLE IF NOT EXISTS sessions (
-		id SERIAL PRIMARY KEY,
-		visitor_id TEXT NOT NULL UNIQUE,
-		duration_seconds INT NOT NULL DEFAULT 0,
-		pages_count INT NOT NULL DEFAULT 1,
-		theme TEXT DEFAULT 'light',
-		language TEXT DEFAULT 'ru',
-		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-	);
-
-	CREATE TABLE IF NOT EXISTS daily_stats (
-		id SERIAL PRIMARY KEY,
-		date DATE NOT NULL UNIQUE,
-		visits INT NOT NULL DEFAULT 0,
-		unique_visitors INT NOT NULL DEFAULT 0,
-		desktop_count INT NOT NULL DEFAULT 0,
-		mobile_count INT NOT NULL DEFAULT 0,
-		tablet_count INT NOT NULL DEFAULT 0,
-		light_theme INT NOT NULL DEFAULT 0,
-		dark_theme INT NOT NULL DEFAULT 0,
-		lang_ru INT NOT NULL DEFAULT 0,
-		lang_en INT NOT NULL DEFAULT 0
-	);
-
-	CREATE INDEX IF NOT EXISTS idx_page_views_created_at ON page_views(created_at);
-	CREATE INDEX IF NOT EXISTS idx_page_views_page ON page_views(page);
-	CREATE INDEX IF NOT EXISTS idx_page_views_visitor ON page_views(visitor_id);
-	CREATE INDEX IF NOT EXISTS idx_daily_stats_date ON daily_stats(date);
-	`
-
-	_, err := r.pool.Exec(ctx, schema)
+	migrator, err := migrate.New(r.pool, migrations.FS)
 	if err != nil {
 		return err
 	}
 
+	if _, err := migrator.Up(ctx); err != nil {
+		return err
+	}
+
 	// Insert default data if about is empty
 	var count int
 	err = r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM about").Scan(&count)
@@ -168,163 +98,49 @@ func (r *PostgresRepository) migrate(ctx context.Context) error {
 }
 
 func (r *PostgresRepository) seedData(ctx context.Context) error {
-	// About
-	_, err := r.pool.Exec(ctx, `
-		INSERT INTO about (name_ru, name_en, username, title_ru, title_en, bio_ru, bio_en, photo) VALUES (
-			'Георгий', 'Georgy', '@Kyureno',
-			'Full-stack разработчик', 'Full-stack developer',
-			'Я full-stack разработчик, в IT с 2021 года — уже более 4 лет в разработке. На фронтенде использую TypeScript, React, Next.js, Vue.js и Nuxt, стилизую через Tailwind и Sass. Бэкенды пишу на Go, Python и Node.js с Express и Fastify. Работаю с PostgreSQL, MongoDB, Redis. Также изучаю Rust и Java для расширения стека. Использую Docker, GitHub Actions, Nginx для CI/CD и деплоя. Учусь в РАНХиГС, дополнительно углубленно изучаю Data Engineering — Apache Spark, Kafka, Airflow и ClickHouse.',
-			'I''m a full-stack developer, in IT since 2021 — over 4 years in development. On the frontend I use TypeScript, React, Next.js, Vue.js and Nuxt, styling with Tailwind and Sass. Backends are written in Go, Python and Node.js with Express and Fastify. I work with PostgreSQL, MongoDB, Redis. Also learning Rust and Java to expand my stack. Using Docker, GitHub Actions, Nginx for CI/CD and deployment. Studying at RANEPA, additionally deep diving into Data Engineering — Apache Spark, Kafka, Airflow and ClickHouse.',
-			'/logo.png'
-		)
-	`)
-	if err != nil {
-		return err
-	}
-
-	// Stats
-	_, err = r.pool.Exec(ctx, `
-		INSERT INTO stats (value_ru, value_en, label_ru, label_en, icon, color, sort_order) VALUES
-			('4+ лет', '4+ years', 'в разработке', 'in development', 'calendar', '#3178C6', 1),
-			('10+', '10+', 'проектов', 'projects', 'rocket', '#8B5CF6', 2),
-			('40+', '40+', 'технологий', 'technologies', 'code', '#00ADD8', 3),
-			('РАНХиГС', 'RANEPA', 'обучение', 'studying', 'graduation', '#10B981', 4)
-	`)
-	if err != nil {
-		return err
-	}
-
-	// Contacts
-	_, err = r.pool.Exec(ctx, `
-		INSERT INTO contacts (id, type, label_ru, label_en, value, link, icon, color, sort_order) VALUES
-			('telegram', 'social', 'Telegram', 'Telegram', '@kyurenodev', 'https://t.me/kyurenodev', 'FaTelegram', '#0088cc', 1),
-			('discord', 'social', 'Discord', 'Discord', 'kyureno', 'https://discord.com/users/kyureno', 'FaDiscord', '#5865F2', 2),
-			('github', 'social', 'GitHub', 'GitHub', 'Kyureno2374', 'https://github.com/Kyureno2374', 'FaGithub', '#333333', 3)
-	`)
-	if err != nil {
-		return err
-	}
-
-	// Projects
-	_, err = r.pool.Exec(ctx, `
-		INSERT INTO projects (id, title_ru, title_en, description_ru, description_en, categories, image, tags, url, glow_color, featured, sort_order) VALUES
-			('portfolio', 'Портфолио', 'Portfolio', 
-			 'Персональный сайт-портфолио с Liquid Glass UI, адаптивным дизайном и поддержкой темной темы.',
-			 'Personal portfolio website with Liquid Glass UI, responsive design and dark theme support.',
-			 ARRAY['fullstack', 'design'], '/portfolio.jpg', 
-			 ARRAY['Next.js', 'TypeScript', 'Tailwind', 'Go', 'PostgreSQL'],
-			 'https://kyureno.dev', '#3178C6', true, 1),
-			('oll', 'OsuServerLauncher', 'OsuServerLauncher',
-			 'Современный лаунчер для приватных серверов osu! с управлением серверами, темами и мультиязычностью.',
-			 'Modern launcher for osu! private servers with server management, themes and multilingual support.',
-			 ARRAY['frontend', 'design'], '/oll.jpg',
-			 ARRAY['Electron', 'React', 'TypeScript', 'Tailwind', 'Vite'],
-			 'https://github.com/Kyureno2374/OsuServerLauncher', '#FF66AA', true, 2)
-	`)
-	if err != nil {
-		return err
-	}
-
-	// Skill categories
-	_, err = r.pool.Exec(ctx, `
-		INSERT INTO skill_categories (id, title_ru, title_en, is_learning, sort_order) VALUES
-			('frontend', 'Фронтенд', 'Frontend', false, 1),
-			('backend', 'Бэкенд разработка', 'Backend Development', false, 2),
-			('devops', 'Окружение и CI/CD', 'Environment & CI/CD', false, 3),
-			('data-engineering', 'Data Engineering (изучаю углубленно)', 'Data Engineering (learning)', true, 4)
-	`)
-	if err != nil {
-		return err
-	}
+	content := defaultContent()
 
-	// Skills - Frontend
-	_, err = r.pool.Exec(ctx, `
-		INSERT INTO skills (id, category_id, name, icon, color, sort_order) VALUES
-			('typescript', 'frontend', 'TypeScript', 'SiTypescript', '#3178C6', 1),
-			('javascript', 'frontend', 'JavaScript', 'SiJavascript', '#F7DF1E', 2),
-			('react', 'frontend', 'React', 'SiReact', '#61DAFB', 3),
-			('nextjs', 'frontend', 'Next.js', 'SiNextdotjs', '#808080', 4),
-			('vue', 'frontend', 'Vue.js', 'SiVuedotjs', '#4FC08D', 5),
-			('nuxt', 'frontend', 'Nuxt', 'SiNuxtdotjs', '#00DC82', 6),
-			('tailwind', 'frontend', 'Tailwind CSS', 'SiTailwindcss', '#06B6D4', 7),
-			('sass', 'frontend', 'Sass', 'SiSass', '#CC6699', 8),
-			('framer', 'frontend', 'Framer Motion', 'SiFramer', '#0055FF', 9),
-			('redux', 'frontend', 'Redux', 'SiRedux', '#764ABC', 10),
-			('zustand', 'frontend', 'Zustand', 'SiReact', '#443E38', 11),
-			('reactquery', 'frontend', 'React Query', 'SiReactquery', '#FF4154', 12),
-			('html5', 'frontend', 'HTML5', 'SiHtml5', '#E34F26', 13),
-			('css3', 'frontend', 'CSS3', 'SiCss3', '#1572B6', 14),
-			('electron', 'frontend', 'Electron', 'SiElectron', '#47848F', 15)
-	`)
-	if err != nil {
-		return err
-	}
+	return r.inTx(ctx, func(tx pgx.Tx) error {
+		// About row must exist before updateAbout can fill it
+		err := tx.QueryRow(ctx, "INSERT INTO about DEFAULT VALUES RETURNING id").Scan(&content.About.ID)
+		if err != nil {
+			return err
+		}
 
-	// Skills - Backend
-	_, err = r.pool.Exec(ctx, `
-		INSERT INTO skills (id, category_id, name, icon, color, sort_order) VALUES
-			('go', 'backend', 'Go', 'SiGo', '#00ADD8', 1),
-			('python', 'backend', 'Python', 'SiPython', '#3776AB', 2),
-			('nodejs', 'backend', 'Node.js', 'SiNodedotjs', '#339933', 3),
-			('rust', 'backend', 'Rust', 'SiRust', '#DEA584', 4),
-			('java', 'backend', 'Java', 'FaJava', '#ED8B00', 5),
-			('postgresql', 'backend', 'PostgreSQL', 'SiPostgresql', '#4169E1', 6),
-			('mongodb', 'backend', 'MongoDB', 'SiMongodb', '#47A248', 7),
-			('redis', 'backend', 'Redis', 'SiRedis', '#DC382D', 8),
-			('mysql', 'backend', 'MySQL', 'SiMysql', '#4479A1', 9),
-			('graphql', 'backend', 'GraphQL', 'SiGraphql', '#E10098', 10),
-			('prisma', 'backend', 'Prisma', 'SiPrisma', '#2D3748', 11),
-			('express', 'backend', 'Express', 'SiExpress', '#808080', 12),
-			('fastify', 'backend', 'Fastify', 'SiFastify', '#808080', 13),
-			('gin', 'backend', 'Gin', 'SiGo', '#00ADD8', 14),
-			('fastapi', 'backend', 'FastAPI', 'SiFastapi', '#009688', 15)
-	`)
-	if err != nil {
-		return err
-	}
+		if err := r.updateAbout(ctx, tx, content.About); err != nil {
+			return err
+		}
+		if err := r.updateProjects(ctx, tx, content.Projects); err != nil {
+			return err
+		}
+		if err := r.updateSkills(ctx, tx, content.Skills); err != nil {
+			return err
+		}
+		return r.updateContacts(ctx, tx, content.Contacts)
+	})
+}
 
-	// Skills - DevOps
-	_, err = r.pool.Exec(ctx, `
-		INSERT INTO skills (id, category_id, name, icon, color, sort_order) VALUES
-			('docker', 'devops', 'Docker', 'SiDocker', '#2496ED', 1),
-			('git', 'devops', 'Git', 'SiGit', '#F05032', 2),
-			('github', 'devops', 'GitHub', 'SiGithub', '#808080', 3),
-			('github-actions', 'devops', 'GitHub Actions', 'SiGithubactions', '#2088FF', 4),
-			('linux', 'devops', 'Linux', 'SiLinux', '#FCC624', 5),
-			('bash', 'devops', 'Bash', 'SiGnubash', '#4EAA25', 6),
-			('nginx', 'devops', 'Nginx', 'SiNginx', '#009639', 7),
-			('vercel', 'devops', 'Vercel', 'SiVercel', '#808080', 8),
-			('aws', 'devops', 'AWS', 'SiAmazonaws', '#FF9900', 9),
-			('cloudflare', 'devops', 'Cloudflare', 'SiCloudflare', '#F38020', 10),
-			('vite', 'devops', 'Vite', 'SiVite', '#646CFF', 11),
-			('webpack', 'devops', 'Webpack', 'SiWebpack', '#8DD6F9', 12),
-			('eslint', 'devops', 'ESLint', 'SiEslint', '#4B32C3', 13),
-			('prettier', 'devops', 'Prettier', 'SiPrettier', '#F7B93E', 14),
-			('jest', 'devops', 'Jest', 'SiJest', '#C21325', 15)
-	`)
+// inTx runs fn in a single transaction, rolling back on any error. The
+// rollback is logged at debug level with the request id of ctx.
+func (r *PostgresRepository) inTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	err := pgx.BeginFunc(ctx, r.pool, fn)
 	if err != nil {
-		return err
+		logging.FromContext(ctx).Debug("Transaction rolled back", "err", err)
 	}
+	return mapPgError(err)
+}
 
-	// Skills - Data Engineering
-	_, err = r.pool.Exec(ctx, `
-		INSERT INTO skills (id, category_id, name, icon, color, sort_order) VALUES
-			('sql', 'data-engineering', 'SQL', 'SiPostgresql', '#4169E1', 1),
-			('python-data', 'data-engineering', 'Python', 'SiPython', '#3776AB', 2),
-			('spark', 'data-engineering', 'Apache Spark', 'SiApachespark', '#E25A1C', 3),
-			('kafka', 'data-engineering', 'Apache Kafka', 'SiApachekafka', '#231F20', 4),
-			('airflow', 'data-engineering', 'Apache Airflow', 'SiApacheairflow', '#017CEE', 5),
-			('clickhouse', 'data-engineering', 'ClickHouse', 'SiClickhouse', '#FFCC01', 6),
-			('etl', 'data-engineering', 'ETL', 'SiDatabricks', '#FF3621', 7),
-			('dwh', 'data-engineering', 'DWH', 'SiSnowflake', '#29B5E8', 8),
-			('data-modeling', 'data-engineering', 'Data Modeling', 'SiDiagramsdotnet', '#F08705', 9),
-			('data-quality', 'data-engineering', 'Data Quality', 'SiApacheairflow', '#017CEE', 10)
-	`)
-	if err != nil {
-		return err
+// nonNil keeps NOT NULL array columns from receiving NULL
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
 	}
+	return s
+}
 
-	return nil
+// PoolStat returns the current connection pool statistics
+func (r *PostgresRepository) PoolStat() *pgxpool.Stat {
+	return r.pool.Stat()
 }
 
 func (r *PostgresRepository) Close() {
@@ -336,32 +152,21 @@ func (r *PostgresRepository) Close() {
 // GetAbout returns about content
 func (r *PostgresRepository) GetAbout(ctx context.Context) (entity.AboutContent, error) {
 	var about entity.AboutContent
-	about.Name = make(map[string]string)
-	about.Title = make(map[string]string)
-	about.Bio = make(map[string]string)
-
-	var nameRu, nameEn, titleRu, titleEn, bioRu, bioEn string
 	err := r.pool.QueryRow(ctx, `
-		SELECT id, name_ru, name_en, username, title_ru, title_en, bio_ru, bio_en, photo, updated_at
+		SELECT id, name, username, title, bio, photo, updated_at
 		FROM about LIMIT 1
 	`).Scan(
-		&about.ID, &nameRu, &nameEn,
-		&about.Username, &titleRu, &titleEn,
-		&bioRu, &bioEn, &about.Photo, &about.UpdatedAt,
+		&about.ID, &about.Name,
+		&about.Username, &about.Title,
+		&about.Bio, &about.Photo, &about.UpdatedAt,
 	)
 	if err != nil {
-		return about, err
+		return about, mapPgError(err)
 	}
-	about.Name["ru"] = nameRu
-	about.Name["en"] = nameEn
-	about.Title["ru"] = titleRu
-	about.Title["en"] = titleEn
-	about.Bio["ru"] = bioRu
-	about.Bio["en"] = bioEn
 
 	// Get stats
 	rows, err := r.pool.Query(ctx, `
-		SELECT value_ru, value_en, label_ru, label_en, icon, color
+		SELECT value, label, icon, color
 		FROM stats ORDER BY sort_order
 	`)
 	if err != nil {
@@ -371,64 +176,71 @@ func (r *PostgresRepository) GetAbout(ctx context.Context) (entity.AboutContent,
 
 	for rows.Next() {
 		var stat entity.Stat
-		stat.Value = make(map[string]string)
-		stat.Label = make(map[string]string)
-		var valueRu, valueEn, labelRu, labelEn string
-		if err := rows.Scan(&valueRu, &valueEn, &labelRu, &labelEn, &stat.Icon, &stat.Color); err != nil {
+		if err := rows.Scan(&stat.Value, &stat.Label, &stat.Icon, &stat.Color); err != nil {
 			return about, err
 		}
-		stat.Value["ru"] = valueRu
-		stat.Value["en"] = valueEn
-		stat.Label["ru"] = labelRu
-		stat.Label["en"] = labelEn
 		about.Stats = append(about.Stats, stat)
 	}
+	about.MapLocalized(readLocales(r.locales, true))
 
-	return about, nil
+	return about, rows.Err()
 }
 
 // UpdateAbout updates about content
 func (r *PostgresRepository) UpdateAbout(ctx context.Context, about entity.AboutContent) error {
-	_, err := r.pool.Exec(ctx, `
+	return r.inTx(ctx, func(tx pgx.Tx) error {
+		if err := bumpVersion(ctx, tx, entity.SectionAbout); err != nil {
+			return err
+		}
+		return r.updateAbout(ctx, tx, about)
+	})
+}
+
+func (r *PostgresRepository) updateAbout(ctx context.Context, tx pgx.Tx, about entity.AboutContent) error {
+	tag, err := tx.Exec(ctx, `
 		UPDATE about SET
-			name_ru = $1, name_en = $2, username = $3,
-			title_ru = $4, title_en = $5,
-			bio_ru = $6, bio_en = $7, photo = $8,
+			name = $1, username = $2, title = $3,
+			bio = $4, photo = $5,
 			updated_at = CURRENT_TIMESTAMP
-		WHERE id = $9
+		WHERE id = $6
 	`,
-		about.Name["ru"], about.Name["en"], about.Username,
-		about.Title["ru"], about.Title["en"],
-		about.Bio["ru"], about.Bio["en"], about.Photo, about.ID,
+		r.locales.Normalize(about.Name), about.Username, r.locales.Normalize(about.Title),
+		r.locales.Normalize(about.Bio), about.Photo, about.ID,
 	)
 	if err != nil {
 		return err
 	}
+	// A missing or stale id would otherwise publish nothing and still succeed
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("about %d: %w", about.ID, ErrNotFound)
+	}
 
 	// Update stats - delete and reinsert
-	_, err = r.pool.Exec(ctx, "DELETE FROM stats")
+	_, err = tx.Exec(ctx, "DELETE FROM stats")
 	if err != nil {
 		return err
 	}
 
+	batch := &pgx.Batch{}
 	for i, stat := range about.Stats {
-		_, err = r.pool.Exec(ctx, `
-			INSERT INTO stats (value_ru, value_en, label_ru, label_en, icon, color, sort_order)
-			VALUES ($1, $2, $3, $4, $5, $6, $7)
-		`, stat.Value["ru"], stat.Value["en"], stat.Label["ru"], stat.Label["en"], stat.Icon, stat.Color, i+1)
-		if err != nil {
-			return err
-		}
+		batch.Queue(`
+			INSERT INTO stats (value, label, icon, color, sort_order)
+			VALUES ($1, $2, $3, $4, $5)
+		`, r.locales.Normalize(stat.Value), r.locales.Normalize(stat.Label), stat.Icon, stat.Color, i+1)
 	}
 
-	return nil
+	return tx.SendBatch(ctx, batch).Close()
 }
 
-// GetProjects returns all projects
+// GetProjects returns all currently published projects
 func (r *PostgresRepository) GetProjects(ctx context.Context) ([]entity.Project, error) {
+	return r.getProjects(ctx, true)
+}
+
+func (r *PostgresRepository) getProjects(ctx context.Context, visibleOnly bool) ([]entity.Project, error) {
 	rows, err := r.pool.Query(ctx, `
-		SELECT id, title_ru, title_en, description_ru, description_en, categories, image, tags, url, glow_color, featured, sort_order
-		FROM projects ORDER BY sort_order
+		SELECT id, title, description, categories, image, tags, url, glow_color, featured, sort_order, publish_at, unpublish_at
+		FROM projects `+visibilityFilter(visibleOnly)+` ORDER BY sort_order
 	`)
 	if err != nil {
 		return nil, err
@@ -438,93 +250,60 @@ func (r *PostgresRepository) GetProjects(ctx context.Context) ([]entity.Project,
 	var projects []entity.Project
 	for rows.Next() {
 		var p entity.Project
-		p.Title = make(map[string]string)
-		p.Description = make(map[string]string)
-		var titleRu, titleEn, descRu, descEn string
-		if err := rows.Scan(&p.ID, &titleRu, &titleEn, &descRu, &descEn, &p.Categories, &p.Image, &p.Tags, &p.Url, &p.GlowColor, &p.Featured, &p.Order); err != nil {
+		if err := rows.Scan(&p.ID, &p.Title, &p.Description, &p.Categories, &p.Image, &p.Tags, &p.Url, &p.GlowColor, &p.Featured, &p.Order, &p.PublishAt, &p.UnpublishAt); err != nil {
 			return nil, err
 		}
-		p.Title["ru"] = titleRu
-		p.Title["en"] = titleEn
-		p.Description["ru"] = descRu
-		p.Description["en"] = descEn
+		p.MapLocalized(readLocales(r.locales, visibleOnly))
 		projects = append(projects, p)
 	}
 
-	return projects, nil
+	return projects, rows.Err()
 }
 
 // UpdateProjects updates all projects
 func (r *PostgresRepository) UpdateProjects(ctx context.Context, projects []entity.Project) error {
-	_, err := r.pool.Exec(ctx, "DELETE FROM projects")
+	if err := checkProjectIDs(projects); err != nil {
+		return err
+	}
+
+	return r.inTx(ctx, func(tx pgx.Tx) error {
+		if err := bumpVersion(ctx, tx, entity.SectionProjects); err != nil {
+			return err
+		}
+		return r.updateProjects(ctx, tx, projects)
+	})
+}
+
+func (r *PostgresRepository) updateProjects(ctx context.Context, tx pgx.Tx, projects []entity.Project) error {
+	_, err := tx.Exec(ctx, "DELETE FROM projects")
 	if err != nil {
 		return err
 	}
 
+	batch := &pgx.Batch{}
 	for i, p := range projects {
-		_, err = r.pool.Exec(ctx, `
-			INSERT INTO projects (id, title_ru, title_en, description_ru, description_en, categories, image, tags, url, glow_color, featured, sort_order)
+		batch.Queue(`
+			INSERT INTO projects (id, title, description, categories, image, tags, url, glow_color, featured, sort_order, publish_at, unpublish_at)
 			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
-		`, p.ID, p.Title["ru"], p.Title["en"], p.Description["ru"], p.Description["en"], p.Categories, p.Image, p.Tags, p.Url, p.GlowColor, p.Featured, i+1)
-		if err != nil {
-			return err
-		}
+		`, p.ID, r.locales.Normalize(p.Title), r.locales.Normalize(p.Description), nonNil(p.Categories), p.Image, nonNil(p.Tags), p.Url, p.GlowColor, p.Featured, i+1, p.PublishAt, p.UnpublishAt)
 	}
 
-	return nil
+	return tx.SendBatch(ctx, batch).Close()
 }
 
-// GetSkills returns all skill categories with skills
+// GetSkills returns all currently published skill categories with skills
 func (r *PostgresRepository) GetSkills(ctx context.Context) ([]entity.SkillCategory, error) {
-	rows, err := r.pool.Query(ctx, `
-		SELECT id, title_ru, title_en, badge_ru, badge_en, is_learning, sort_order
-		FROM skill_categories ORDER BY sort_order
-	`)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+	return r.getSkills(ctx, true)
+}
 
+func (r *PostgresRepository) getSkills(ctx context.Context, visibleOnly bool) ([]entity.SkillCategory, error) {
 	var categories []entity.SkillCategory
-	for rows.Next() {
-		var c entity.SkillCategory
-		c.Title = make(map[string]string)
-		c.Badge = make(map[string]string)
-		var titleRu, titleEn string
-		var badgeRu, badgeEn *string
-		if err := rows.Scan(&c.ID, &titleRu, &titleEn, &badgeRu, &badgeEn, &c.IsLearning, &c.Order); err != nil {
-			return nil, err
-		}
-		c.Title["ru"] = titleRu
-		c.Title["en"] = titleEn
-		if badgeRu != nil {
-			c.Badge["ru"] = *badgeRu
-		}
-		if badgeEn != nil {
-			c.Badge["en"] = *badgeEn
-		}
-		categories = append(categories, c)
+	if err := r.pool.QueryRow(ctx, `SELECT `+skillsJSON(visibleOnly)).Scan(&categories); err != nil {
+		return nil, err
 	}
 
-	// Get skills for each category
 	for i := range categories {
-		categories[i].Skills = []entity.Skill{}
-		skillRows, err := r.pool.Query(ctx, `
-			SELECT id, name, icon, color FROM skills WHERE category_id = $1 ORDER BY sort_order
-		`, categories[i].ID)
-		if err != nil {
-			return nil, err
-		}
-
-		for skillRows.Next() {
-			var s entity.Skill
-			if err := skillRows.Scan(&s.ID, &s.Name, &s.Icon, &s.Color); err != nil {
-				skillRows.Close()
-				return nil, err
-			}
-			categories[i].Skills = append(categories[i].Skills, s)
-		}
-		skillRows.Close()
+		categories[i].MapLocalized(readLocales(r.locales, visibleOnly))
 	}
 
 	return categories, nil
@@ -532,45 +311,55 @@ func (r *PostgresRepository) GetSkills(ctx context.Context) ([]entity.SkillCateg
 
 // UpdateSkills updates all skill categories and skills
 func (r *PostgresRepository) UpdateSkills(ctx context.Context, categories []entity.SkillCategory) error {
-	_, err := r.pool.Exec(ctx, "DELETE FROM skills")
+	if err := checkSkillIDs(categories); err != nil {
+		return err
+	}
+
+	return r.inTx(ctx, func(tx pgx.Tx) error {
+		if err := bumpVersion(ctx, tx, entity.SectionSkills); err != nil {
+			return err
+		}
+		return r.updateSkills(ctx, tx, categories)
+	})
+}
+
+func (r *PostgresRepository) updateSkills(ctx context.Context, tx pgx.Tx, categories []entity.SkillCategory) error {
+	_, err := tx.Exec(ctx, "DELETE FROM skills")
 	if err != nil {
 		return err
 	}
-	_, err = r.pool.Exec(ctx, "DELETE FROM skill_categories")
+	_, err = tx.Exec(ctx, "DELETE FROM skill_categories")
 	if err != nil {
 		return err
 	}
 
+	batch := &pgx.Batch{}
 	for i, c := range categories {
-		badgeRu := c.Badge["ru"]
-		badgeEn := c.Badge["en"]
-		_, err = r.pool.Exec(ctx, `
-			INSERT INTO skill_categories (id, title_ru, title_en, badge_ru, badge_en, is_learning, sort_order)
+		batch.Queue(`
+			INSERT INTO skill_categories (id, title, badge, is_learning, sort_order, publish_at, unpublish_at)
 			VALUES ($1, $2, $3, $4, $5, $6, $7)
-		`, c.ID, c.Title["ru"], c.Title["en"], badgeRu, badgeEn, c.IsLearning, i+1)
-		if err != nil {
-			return err
-		}
+		`, c.ID, r.locales.Normalize(c.Title), r.locales.Normalize(c.Badge), c.IsLearning, i+1, c.PublishAt, c.UnpublishAt)
 
 		for j, s := range c.Skills {
-			_, err = r.pool.Exec(ctx, `
+			batch.Queue(`
 				INSERT INTO skills (id, category_id, name, icon, color, sort_order)
 				VALUES ($1, $2, $3, $4, $5, $6)
 			`, s.ID, c.ID, s.Name, s.Icon, s.Color, j+1)
-			if err != nil {
-				return err
-			}
 		}
 	}
 
-	return nil
+	return tx.SendBatch(ctx, batch).Close()
 }
 
-// GetContacts returns all contacts
+// GetContacts returns all currently published contacts
 func (r *PostgresRepository) GetContacts(ctx context.Context) ([]entity.Contact, error) {
+	return r.getContacts(ctx, true)
+}
+
+func (r *PostgresRepository) getContacts(ctx context.Context, visibleOnly bool) ([]entity.Contact, error) {
 	rows, err := r.pool.Query(ctx, `
-		SELECT id, type, label_ru, label_en, value, link, icon, color, sort_order
-		FROM contacts ORDER BY sort_order
+		SELECT id, type, label, value, link, icon, color, sort_order, publish_at, unpublish_at
+		FROM contacts `+visibilityFilter(visibleOnly)+` ORDER BY sort_order
 	`)
 	if err != nil {
 		return nil, err
@@ -580,65 +369,121 @@ func (r *PostgresRepository) GetContacts(ctx context.Context) ([]entity.Contact,
 	var contacts []entity.Contact
 	for rows.Next() {
 		var c entity.Contact
-		c.Label = make(map[string]string)
-		var labelRu, labelEn string
-		if err := rows.Scan(&c.ID, &c.Type, &labelRu, &labelEn, &c.Value, &c.Link, &c.Icon, &c.Color, &c.Order); err != nil {
+		if err := rows.Scan(&c.ID, &c.Type, &c.Label, &c.Value, &c.Link, &c.Icon, &c.Color, &c.Order, &c.PublishAt, &c.UnpublishAt); err != nil {
 			return nil, err
 		}
-		c.Label["ru"] = labelRu
-		c.Label["en"] = labelEn
+		c.MapLocalized(readLocales(r.locales, visibleOnly))
 		contacts = append(contacts, c)
 	}
 
-	return contacts, nil
+	return contacts, rows.Err()
 }
 
 // UpdateContacts updates all contacts
 func (r *PostgresRepository) UpdateContacts(ctx context.Context, contacts []entity.Contact) error {
-	_, err := r.pool.Exec(ctx, "DELETE FROM contacts")
-	if err != nil {
+	if err := checkContactIDs(contacts); err != nil {
 		return err
 	}
 
-	for i, c := range contacts {
-		_, err = r.pool.Exec(ctx, `
-			INSERT INTO contacts (id, type, label_ru, label_en, value, link, icon, color, sort_order)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-		`, c.ID, c.Type, c.Label["ru"], c.Label["en"], c.Value, c.Link, c.Icon, c.Color, i+1)
-		if err != nil {
+	return r.inTx(ctx, func(tx pgx.Tx) error {
+		if err := bumpVersion(ctx, tx, entity.SectionContacts); err != nil {
 			return err
 		}
+		return r.updateContacts(ctx, tx, contacts)
+	})
+}
+
+func (r *PostgresRepository) updateContacts(ctx context.Context, tx pgx.Tx, contacts []entity.Contact) error {
+	_, err := tx.Exec(ctx, "DELETE FROM contacts")
+	if err != nil {
+		return err
 	}
 
-	return nil
+	batch := &pgx.Batch{}
+	for i, c := range contacts {
+		batch.Queue(`
+			INSERT INTO contacts (id, type, label, value, link, icon, color, sort_order, publish_at, unpublish_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		`, c.ID, c.Type, r.locales.Normalize(c.Label), c.Value, c.Link, c.Icon, c.Color, i+1, c.PublishAt, c.UnpublishAt)
+	}
+
+	return tx.SendBatch(ctx, batch).Close()
 }
 
-// GetAll returns all content
+// GetAll returns all currently published content
 func (r *PostgresRepository) GetAll(ctx context.Context) (*entity.SiteContent, error) {
-	about, err := r.GetAbout(ctx)
-	if err != nil {
-		return nil, err
-	}
+	return r.getAll(ctx, true)
+}
+
+// GetEditable returns all content including items outside their publish window
+func (r *PostgresRepository) GetEditable(ctx context.Context) (*entity.SiteContent, error) {
+	return r.getAll(ctx, false)
+}
 
-	projects, err := r.GetProjects(ctx)
+// getAll reads the whole content in one query, see query.go
+func (r *PostgresRepository) getAll(ctx context.Context, visibleOnly bool) (*entity.SiteContent, error) {
+	var row struct {
+		About    *entity.AboutContent   `json:"about"`
+		Projects []entity.Project       `json:"projects"`
+		Skills   []entity.SkillCategory `json:"skills"`
+		Contacts []entity.Contact       `json:"contacts"`
+	}
+	err := r.pool.QueryRow(ctx, `
+		SELECT json_build_object(
+			'about', `+aboutJSON()+`,
+			'projects', `+projectsJSON(visibleOnly)+`,
+			'skills', `+skillsJSON(visibleOnly)+`,
+			'contacts', `+contactsJSON(visibleOnly)+`
+		)
+	`).Scan(&row)
 	if err != nil {
 		return nil, err
 	}
+	if row.About == nil {
+		return nil, fmt.Errorf("about: %w", ErrNotFound)
+	}
 
-	skills, err := r.GetSkills(ctx)
-	if err != nil {
-		return nil, err
+	content := &entity.SiteContent{
+		About:    *row.About,
+		Projects: row.Projects,
+		Skills:   row.Skills,
+		Contacts: row.Contacts,
 	}
+	content.MapLocalized(readLocales(r.locales, visibleOnly))
+	return content, nil
+}
 
-	contacts, err := r.GetContacts(ctx)
-	if err != nil {
-		return nil, err
+// readLocales picks how localized maps are read. The site gets exactly the
+// configured locales; editors also get translations into locales taken out
+// of the config, so saving the content back keeps them.
+func readLocales(locales locale.Set, visibleOnly bool) func(map[string]string) map[string]string {
+	if visibleOnly {
+		return locales.Filter
 	}
+	return locales.Normalize
+}
 
-	return &entity.SiteContent{
-		About:    about,
-		Projects: projects,
-		Skills:   skills,
-		Contacts: contacts,
-	}, nil
+// NextScheduleChange returns the earliest publish_at/unpublish_at after t,
+// or nil if nothing is scheduled
+func (r *PostgresRepository) NextScheduleChange(ctx context.Context, t time.Time) (*time.Time, error) {
+	var next *time.Time
+	err := r.pool.QueryRow(ctx, `
+		SELECT MIN(at) FROM (
+			SELECT publish_at AS at FROM projects WHERE publish_at > $1
+			UNION ALL SELECT unpublish_at FROM projects WHERE unpublish_at > $1
+			UNION ALL SELECT publish_at FROM skill_categories WHERE publish_at > $1
+			UNION ALL SELECT unpublish_at FROM skill_categories WHERE unpublish_at > $1
+			UNION ALL SELECT publish_at FROM contacts WHERE publish_at > $1
+			UNION ALL SELECT unpublish_at FROM contacts WHERE unpublish_at > $1
+		) changes
+	`, t).Scan(&next)
+	return next, err
+}
+
+// visibilityFilter limits a list query to items inside their publish window
+func visibilityFilter(visibleOnly bool) string {
+	if !visibleOnly {
+		return ""
+	}
+	return "WHERE (publish_at IS NULL OR publish_at <= now()) AND (unpublish_at IS NULL OR unpublish_at > now())"
 }
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+
+	"server/internal/entity"
+	"server/internal/locale"
+)
+
+// The Postgres tests need a scratch database in TEST_DB_URL. Migrations
+// are applied and the content is put back after each test.
+
+func testPostgres(tb testing.TB) *PostgresRepository {
+	tb.Helper()
+	dbURL := os.Getenv("TEST_DB_URL")
+	if dbURL == "" {
+		tb.Skip("TEST_DB_URL is not set")
+	}
+	repo, err := NewPostgresRepository(context.Background(), dbURL, PoolOptions{}, locale.Default)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(repo.Close)
+	return repo
+}
+
+// keepContent restores the content of repo when the test ends
+func keepContent(t *testing.T, repo *PostgresRepository) *entity.SiteContent {
+	t.Helper()
+	ctx := context.Background()
+	content, err := repo.GetEditable(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		err := repo.inTx(ctx, func(tx pgx.Tx) error {
+			for _, section := range entity.Sections {
+				if err := repo.applySection(ctx, tx, content, section); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Errorf("restore content: %v", err)
+		}
+	})
+	return content
+}
+
+func TestPostgresUpdateRollsBack(t *testing.T) {
+	repo := testPostgres(t)
+	before := keepContent(t, repo)
+	ctx := context.Background()
+
+	// Postgres rejects NUL in text, so the second insert of the batch fails
+	// after the DELETE and the first insert already ran
+	projects := []entity.Project{
+		{ID: "first", Title: map[string]string{"ru": "a", "en": "a"}},
+		{ID: "second", Title: map[string]string{"ru": "b", "en": "b"}, Url: "bad\x00url"},
+	}
+	if err := repo.UpdateProjects(ctx, projects); err == nil {
+		t.Fatal("UpdateProjects() succeeded")
+	}
+
+	after, err := repo.GetEditable(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(after.Projects, before.Projects) {
+		t.Errorf("failed update changed the projects:\n%+v\n%+v", before.Projects, after.Projects)
+	}
+}
+
+func TestPostgresDuplicateIDs(t *testing.T) {
+	repo := testPostgres(t)
+	before := keepContent(t, repo)
+	ctx := context.Background()
+
+	skills := []entity.SkillCategory{
+		{ID: "one", Title: map[string]string{"ru": "1", "en": "1"}, Skills: []entity.Skill{{ID: "go", Name: "Go"}}},
+		{ID: "two", Title: map[string]string{"ru": "2", "en": "2"}, Skills: []entity.Skill{{ID: "go", Name: "Go"}}},
+	}
+	err := repo.UpdateSkills(ctx, skills)
+	var dup *DuplicateIDError
+	if !errors.As(err, &dup) || dup.Kind != "skill" || !errors.Is(err, ErrInvalid) {
+		t.Fatalf("UpdateSkills() = %v, want a duplicate skill id", err)
+	}
+
+	after, err := repo.GetEditable(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(after.Skills, before.Skills) {
+		t.Error("rejected update changed the skills")
+	}
+}
+
+func TestPostgresUpdateKeepsOrder(t *testing.T) {
+	repo := testPostgres(t)
+	keepContent(t, repo)
+	ctx := context.Background()
+
+	contacts := []entity.Contact{
+		{ID: "b", Label: map[string]string{"ru": "b", "en": "b"}, Value: "b", Link: "https://b", Icon: "b", Color: "#000"},
+		{ID: "a", Label: map[string]string{"ru": "a", "en": "a"}, Value: "a", Link: "https://a", Icon: "a", Color: "#000"},
+	}
+	if err := repo.UpdateContacts(ctx, contacts); err != nil {
+		t.Fatal(err)
+	}
+	got, err := repo.GetContacts(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].ID != "b" || got[0].Order != 1 || got[1].ID != "a" || got[1].Order != 2 {
+		t.Errorf("contacts = %+v", got)
+	}
+}
+
+func TestPostgresSaveDraftChecksVersion(t *testing.T) {
+	repo := testPostgres(t)
+	ctx := context.Background()
+	if _, err := repo.GetDraft(ctx, entity.SectionContacts); err == nil {
+		t.Skip("the database has a pending contacts draft")
+	}
+	t.Cleanup(func() { repo.DiscardDraft(ctx, entity.SectionContacts) })
+
+	draft := entity.ContentDraft{Section: entity.SectionContacts, Data: []byte(`[]`)}
+	version := sectionVersion(t, repo, entity.SectionContacts)
+	if err := repo.SaveDraft(ctx, draft, version); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.SaveDraft(ctx, draft, version); !errors.Is(err, ErrStale) {
+		t.Fatalf("SaveDraft() with a used version = %v, want ErrStale", err)
+	}
+	if got := sectionVersion(t, repo, entity.SectionContacts); got != version+1 {
+		t.Errorf("version = %d, want %d", got, version+1)
+	}
+}
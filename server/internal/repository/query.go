@@ -0,0 +1,97 @@
+package repository
+
+// The functions below build SQL expressions that return one content section
+// as JSON shaped like its entity type, so the whole SiteContent is read in
+// a single round trip instead of one query per section and per skill
+// category.
+
+func aboutJSON() string {
+	return `(
+		SELECT json_build_object(
+			'id', a.id,
+			'name', a.name,
+			'username', a.username,
+			'title', a.title,
+			'bio', a.bio,
+			'photo', a.photo,
+			'updated_at', a.updated_at AT TIME ZONE 'UTC',
+			'stats', COALESCE((
+				SELECT json_agg(json_build_object(
+					'value', s.value,
+					'label', s.label,
+					'icon', s.icon,
+					'color', s.color
+				) ORDER BY s.sort_order)
+				FROM stats s
+			), '[]')
+		)
+		FROM about a ORDER BY a.id LIMIT 1
+	)`
+}
+
+func projectsJSON(visibleOnly bool) string {
+	return `COALESCE((
+		SELECT json_agg(json_build_object(
+			'id', id,
+			'title', title,
+			'description', description,
+			'categories', categories,
+			'image', image,
+			'tags', tags,
+			'url', url,
+			'glowColor', glow_color,
+			'featured', featured,
+			'order', sort_order,
+			'publishAt', publish_at,
+			'unpublishAt', unpublish_at
+		) ORDER BY sort_order)
+		FROM projects ` + visibilityFilter(visibleOnly) + `
+	), '[]')`
+}
+
+// skillsJSON aggregates skills per category once and joins the result, so
+// the cost does not grow with the number of categories
+func skillsJSON(visibleOnly bool) string {
+	return `COALESCE((
+		SELECT json_agg(json_build_object(
+			'id', id,
+			'title', title,
+			'skills', COALESCE(grouped.skills, '[]'),
+			'badge', badge,
+			'isLearning', is_learning,
+			'order', sort_order,
+			'publishAt', publish_at,
+			'unpublishAt', unpublish_at
+		) ORDER BY sort_order)
+		FROM skill_categories
+		LEFT JOIN (
+			SELECT category_id, json_agg(json_build_object(
+				'id', s.id,
+				'name', s.name,
+				'icon', s.icon,
+				'color', s.color
+			) ORDER BY s.sort_order) AS skills
+			FROM skills s
+			GROUP BY category_id
+		) grouped ON grouped.category_id = skill_categories.id
+		` + visibilityFilter(visibleOnly) + `
+	), '[]')`
+}
+
+func contactsJSON(visibleOnly bool) string {
+	return `COALESCE((
+		SELECT json_agg(json_build_object(
+			'id', id,
+			'type', type,
+			'label', label,
+			'value', value,
+			'link', link,
+			'icon', icon,
+			'color', color,
+			'order', sort_order,
+			'publishAt', publish_at,
+			'unpublishAt', unpublish_at
+		) ORDER BY sort_order)
+		FROM contacts ` + visibilityFilter(visibleOnly) + `
+	), '[]')`
+}
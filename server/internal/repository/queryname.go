@@ -0,0 +1,59 @@
+package repository
+
+import "strings"
+
+// QueryName names a query by its statement and the table it works on, for
+// metrics and trace spans: "select about", "insert into page_views" becomes
+// "insert page_views". Transaction control and anything unrecognised keep
+// only the statement, so the set of names stays small.
+func QueryName(sql string) string {
+	words := strings.Fields(strings.ToLower(sql))
+	if len(words) == 0 {
+		return "unknown"
+	}
+	stmt := words[0]
+	after := ""
+	switch stmt {
+	case "select", "delete":
+		after = "from"
+	case "insert":
+		after = "into"
+	case "update":
+		if len(words) > 1 {
+			return stmt + " " + tableName(words[1])
+		}
+		return stmt
+	case "begin", "commit", "rollback", "with":
+		return stmt
+	default:
+		if len(stmt) > 16 || strings.ContainsAny(stmt, "();") {
+			return "other"
+		}
+		return stmt
+	}
+	for i, w := range words[:len(words)-1] {
+		if w == after {
+			return stmt + " " + tableName(words[i+1])
+		}
+	}
+	return stmt
+}
+
+func tableName(word string) string {
+	if strings.HasPrefix(word, "(") {
+		return "subquery"
+	}
+	word = strings.Trim(word, `"(),;`)
+	if i := strings.LastIndexByte(word, '.'); i >= 0 {
+		word = strings.Trim(word[i+1:], `"`)
+	}
+	for _, c := range word {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '_' {
+			return "subquery"
+		}
+	}
+	if word == "" {
+		return "subquery"
+	}
+	return word
+}
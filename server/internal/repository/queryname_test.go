@@ -0,0 +1,27 @@
+package repository
+
+import "testing"
+
+func TestQueryName(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want string
+	}{
+		{"SELECT id, name FROM about WHERE id = $1", "select about"},
+		{"select * from public.page_views", "select page_views"},
+		{`SELECT count(*) FROM "sessions"`, "select sessions"},
+		{"INSERT INTO page_views (page) VALUES ($1)", "insert page_views"},
+		{"UPDATE content_versions SET version = version + 1", "update content_versions"},
+		{"DELETE FROM skills WHERE category_id = $1", "delete skills"},
+		{"SELECT x FROM (SELECT 1) s", "select subquery"},
+		{"WITH recent AS (SELECT 1) SELECT * FROM recent", "with"},
+		{"begin", "begin"},
+		{"  ", "unknown"},
+		{"select_everything_from_a_function()", "other"},
+	}
+	for _, tt := range tests {
+		if got := QueryName(tt.sql); got != tt.want {
+			t.Errorf("QueryName(%q) = %q, want %q", tt.sql, got, tt.want)
+		}
+	}
+}
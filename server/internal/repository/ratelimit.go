@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"server/internal/ratelimit"
+)
+
+// TakeToken takes a request out of a shared bucket. The row is created
+// full first and then locked, so concurrent requests of several replicas
+// are counted one after another.
+func (r *PostgresRepository) TakeToken(ctx context.Context, key string, limit ratelimit.Limit, now time.Time) (ratelimit.Result, error) {
+	var res ratelimit.Result
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO rate_limit_buckets (key, tokens, updated_at, expires_at)
+			VALUES ($1, $2, $3, $3)
+			ON CONFLICT (key) DO NOTHING
+		`, key, float64(limit.Requests), now)
+		if err != nil {
+			return err
+		}
+
+		var b ratelimit.Bucket
+		err = tx.QueryRow(ctx, `
+			SELECT tokens, updated_at FROM rate_limit_buckets WHERE key = $1 FOR UPDATE
+		`, key).Scan(&b.Tokens, &b.Updated)
+		if err != nil {
+			return err
+		}
+
+		b, res = limit.Take(b, now)
+		_, err = tx.Exec(ctx, `
+			UPDATE rate_limit_buckets SET tokens = $2, updated_at = $3, expires_at = $4 WHERE key = $1
+		`, key, b.Tokens, b.Updated, limit.FullAt(b))
+		return err
+	})
+	return res, err
+}
+
+// AddAuthFailure counts a failed attempt, starting over if the previous
+// ones are forgotten
+func (r *PostgresRepository) AddAuthFailure(ctx context.Context, key string, now time.Time, forget time.Duration) (int, error) {
+	var failures int
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO auth_failures (key, failures, last_failure_at, expires_at)
+		VALUES ($1, 1, $2, $3)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE WHEN auth_failures.expires_at <= $2 THEN 1 ELSE auth_failures.failures + 1 END,
+			last_failure_at = $2,
+			expires_at = $3
+		RETURNING failures
+	`, key, now, now.Add(forget)).Scan(&failures)
+	return failures, mapPgError(err)
+}
+
+// AuthFailures returns the failures in a row of key, zero if there are none
+func (r *PostgresRepository) AuthFailures(ctx context.Context, key string, now time.Time) (int, time.Time, error) {
+	var failures int
+	var last time.Time
+	err := r.pool.QueryRow(ctx, `
+		SELECT failures, last_failure_at FROM auth_failures WHERE key = $1 AND expires_at > $2
+	`, key, now).Scan(&failures, &last)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, time.Time{}, nil
+	}
+	return failures, last, err
+}
+
+func (r *PostgresRepository) ResetAuthFailures(ctx context.Context, key string) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM auth_failures WHERE key = $1`, key)
+	return err
+}
+
+func (r *PostgresRepository) PruneRateLimits(ctx context.Context, now time.Time) error {
+	if _, err := r.pool.Exec(ctx, `DELETE FROM rate_limit_buckets WHERE expires_at <= $1`, now); err != nil {
+		return err
+	}
+	_, err := r.pool.Exec(ctx, `DELETE FROM auth_failures WHERE expires_at <= $1`, now)
+	return err
+}
+
+var _ ratelimit.Store = (*PostgresRepository)(nil)
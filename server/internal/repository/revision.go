@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"server/internal/entity"
+)
+
+// CreateRevision stores a snapshot of a content section
+func (r *PostgresRepository) CreateRevision(ctx context.Context, rev entity.ContentRevision) (entity.ContentRevision, error) {
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO content_revisions (section, author, summary, snapshot)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`, rev.Section, rev.Author, rev.Summary, rev.Snapshot).Scan(&rev.ID, &rev.CreatedAt)
+	return rev, err
+}
+
+// ListRevisions returns the latest revisions of a section without snapshots
+func (r *PostgresRepository) ListRevisions(ctx context.Context, section string, limit int) ([]entity.ContentRevision, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, section, author, summary, created_at
+		FROM content_revisions
+		WHERE section = $1
+		ORDER BY id DESC
+		LIMIT $2
+	`, section, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []entity.ContentRevision{}
+	for rows.Next() {
+		var rev entity.ContentRevision
+		if err := rows.Scan(&rev.ID, &rev.Section, &rev.Author, &rev.Summary, &rev.CreatedAt); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+
+	return revisions, rows.Err()
+}
+
+// GetRevision returns a single revision including its snapshot
+func (r *PostgresRepository) GetRevision(ctx context.Context, section string, id int64) (entity.ContentRevision, error) {
+	var rev entity.ContentRevision
+	err := r.pool.QueryRow(ctx, `
+		SELECT id, section, author, summary, snapshot, created_at
+		FROM content_revisions
+		WHERE section = $1 AND id = $2
+	`, section, id).Scan(&rev.ID, &rev.Section, &rev.Author, &rev.Summary, &rev.Snapshot, &rev.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return rev, ErrNotFound
+	}
+	return rev, err
+}
@@ -0,0 +1,142 @@
+package repository
+
+import "server/internal/entity"
+
+// defaultContent is the initial site content seeded into an empty store
+func defaultContent() entity.SiteContent {
+	return entity.SiteContent{
+		About: entity.AboutContent{
+			Name:     map[string]string{"ru": "Георгий", "en": "Georgy"},
+			Username: "@Kyureno",
+			Title:    map[string]string{"ru": "Full-stack разработчик", "en": "Full-stack developer"},
+			Bio: map[string]string{
+				"ru": "Я full-stack разработчик, в IT с 2021 года — уже более 4 лет в разработке. На фронтенде использую TypeScript, React, Next.js, Vue.js и Nuxt, стилизую через Tailwind и Sass. Бэкенды пишу на Go, Python и Node.js с Express и Fastify. Работаю с PostgreSQL, MongoDB, Redis. Также изучаю Rust и Java для расширения стека. Использую Docker, GitHub Actions, Nginx для CI/CD и деплоя. Учусь в РАНХиГС, дополнительно углубленно изучаю Data Engineering — Apache Spark, Kafka, Airflow и ClickHouse.",
+				"en": "I'm a full-stack developer, in IT since 2021 — over 4 years in development. On the frontend I use TypeScript, React, Next.js, Vue.js and Nuxt, styling with Tailwind and Sass. Backends are written in Go, Python and Node.js with Express and Fastify. I work with PostgreSQL, MongoDB, Redis. Also learning Rust and Java to expand my stack. Using Docker, GitHub Actions, Nginx for CI/CD and deployment. Studying at RANEPA, additionally deep diving into Data Engineering — Apache Spark, Kafka, Airflow and ClickHouse.",
+			},
+			Photo: "/logo.png",
+			Stats: []entity.Stat{
+				{Value: map[string]string{"ru": "4+ лет", "en": "4+ years"}, Label: map[string]string{"ru": "в разработке", "en": "in development"}, Icon: "calendar", Color: "#3178C6"},
+				{Value: map[string]string{"ru": "10+", "en": "10+"}, Label: map[string]string{"ru": "проектов", "en": "projects"}, Icon: "rocket", Color: "#8B5CF6"},
+				{Value: map[string]string{"ru": "40+", "en": "40+"}, Label: map[string]string{"ru": "технологий", "en": "technologies"}, Icon: "code", Color: "#00ADD8"},
+				{Value: map[string]string{"ru": "РАНХиГС", "en": "RANEPA"}, Label: map[string]string{"ru": "обучение", "en": "studying"}, Icon: "graduation", Color: "#10B981"},
+			},
+		},
+		Projects: []entity.Project{
+			{
+				ID:    "portfolio",
+				Title: map[string]string{"ru": "Портфолио", "en": "Portfolio"},
+				Description: map[string]string{
+					"ru": "Персональный сайт-портфолио с Liquid Glass UI, адаптивным дизайном и поддержкой темной темы.",
+					"en": "Personal portfolio website with Liquid Glass UI, responsive design and dark theme support.",
+				},
+				Categories: []string{"fullstack", "design"},
+				Image:      "/portfolio.jpg",
+				Tags:       []string{"Next.js", "TypeScript", "Tailwind", "Go", "PostgreSQL"},
+				Url:        "https://kyureno.dev",
+				GlowColor:  "#3178C6",
+				Featured:   true,
+			},
+			{
+				ID:    "oll",
+				Title: map[string]string{"ru": "OsuServerLauncher", "en": "OsuServerLauncher"},
+				Description: map[string]string{
+					"ru": "Современный лаунчер для приватных серверов osu! с управлением серверами, темами и мультиязычностью.",
+					"en": "Modern launcher for osu! private servers with server management, themes and multilingual support.",
+				},
+				Categories: []string{"frontend", "design"},
+				Image:      "/oll.jpg",
+				Tags:       []string{"Electron", "React", "TypeScript", "Tailwind", "Vite"},
+				Url:        "https://github.com/Kyureno2374/OsuServerLauncher",
+				GlowColor:  "#FF66AA",
+				Featured:   true,
+			},
+		},
+		Skills: []entity.SkillCategory{
+			{
+				ID:    "frontend",
+				Title: map[string]string{"ru": "Фронтенд", "en": "Frontend"},
+				Skills: []entity.Skill{
+					{ID: "typescript", Name: "TypeScript", Icon: "SiTypescript", Color: "#3178C6"},
+					{ID: "javascript", Name: "JavaScript", Icon: "SiJavascript", Color: "#F7DF1E"},
+					{ID: "react", Name: "React", Icon: "SiReact", Color: "#61DAFB"},
+					{ID: "nextjs", Name: "Next.js", Icon: "SiNextdotjs", Color: "#808080"},
+					{ID: "vue", Name: "Vue.js", Icon: "SiVuedotjs", Color: "#4FC08D"},
+					{ID: "nuxt", Name: "Nuxt", Icon: "SiNuxtdotjs", Color: "#00DC82"},
+					{ID: "tailwind", Name: "Tailwind CSS", Icon: "SiTailwindcss", Color: "#06B6D4"},
+					{ID: "sass", Name: "Sass", Icon: "SiSass", Color: "#CC6699"},
+					{ID: "framer", Name: "Framer Motion", Icon: "SiFramer", Color: "#0055FF"},
+					{ID: "redux", Name: "Redux", Icon: "SiRedux", Color: "#764ABC"},
+					{ID: "zustand", Name: "Zustand", Icon: "SiReact", Color: "#443E38"},
+					{ID: "reactquery", Name: "React Query", Icon: "SiReactquery", Color: "#FF4154"},
+					{ID: "html5", Name: "HTML5", Icon: "SiHtml5", Color: "#E34F26"},
+					{ID: "css3", Name: "CSS3", Icon: "SiCss3", Color: "#1572B6"},
+					{ID: "electron", Name: "Electron", Icon: "SiElectron", Color: "#47848F"},
+				},
+			},
+			{
+				ID:    "backend",
+				Title: map[string]string{"ru": "Бэкенд разработка", "en": "Backend Development"},
+				Skills: []entity.Skill{
+					{ID: "go", Name: "Go", Icon: "SiGo", Color: "#00ADD8"},
+					{ID: "python", Name: "Python", Icon: "SiPython", Color: "#3776AB"},
+					{ID: "nodejs", Name: "Node.js", Icon: "SiNodedotjs", Color: "#339933"},
+					{ID: "rust", Name: "Rust", Icon: "SiRust", Color: "#DEA584"},
+					{ID: "java", Name: "Java", Icon: "FaJava", Color: "#ED8B00"},
+					{ID: "postgresql", Name: "PostgreSQL", Icon: "SiPostgresql", Color: "#4169E1"},
+					{ID: "mongodb", Name: "MongoDB", Icon: "SiMongodb", Color: "#47A248"},
+					{ID: "redis", Name: "Redis", Icon: "SiRedis", Color: "#DC382D"},
+					{ID: "mysql", Name: "MySQL", Icon: "SiMysql", Color: "#4479A1"},
+					{ID: "graphql", Name: "GraphQL", Icon: "SiGraphql", Color: "#E10098"},
+					{ID: "prisma", Name: "Prisma", Icon: "SiPrisma", Color: "#2D3748"},
+					{ID: "express", Name: "Express", Icon: "SiExpress", Color: "#808080"},
+					{ID: "fastify", Name: "Fastify", Icon: "SiFastify", Color: "#808080"},
+					{ID: "gin", Name: "Gin", Icon: "SiGo", Color: "#00ADD8"},
+					{ID: "fastapi", Name: "FastAPI", Icon: "SiFastapi", Color: "#009688"},
+				},
+			},
+			{
+				ID:    "devops",
+				Title: map[string]string{"ru": "Окружение и CI/CD", "en": "Environment & CI/CD"},
+				Skills: []entity.Skill{
+					{ID: "docker", Name: "Docker", Icon: "SiDocker", Color: "#2496ED"},
+					{ID: "git", Name: "Git", Icon: "SiGit", Color: "#F05032"},
+					{ID: "github", Name: "GitHub", Icon: "SiGithub", Color: "#808080"},
+					{ID: "github-actions", Name: "GitHub Actions", Icon: "SiGithubactions", Color: "#2088FF"},
+					{ID: "linux", Name: "Linux", Icon: "SiLinux", Color: "#FCC624"},
+					{ID: "bash", Name: "Bash", Icon: "SiGnubash", Color: "#4EAA25"},
+					{ID: "nginx", Name: "Nginx", Icon: "SiNginx", Color: "#009639"},
+					{ID: "vercel", Name: "Vercel", Icon: "SiVercel", Color: "#808080"},
+					{ID: "aws", Name: "AWS", Icon: "SiAmazonaws", Color: "#FF9900"},
+					{ID: "cloudflare", Name: "Cloudflare", Icon: "SiCloudflare", Color: "#F38020"},
+					{ID: "vite", Name: "Vite", Icon: "SiVite", Color: "#646CFF"},
+					{ID: "webpack", Name: "Webpack", Icon: "SiWebpack", Color: "#8DD6F9"},
+					{ID: "eslint", Name: "ESLint", Icon: "SiEslint", Color: "#4B32C3"},
+					{ID: "prettier", Name: "Prettier", Icon: "SiPrettier", Color: "#F7B93E"},
+					{ID: "jest", Name: "Jest", Icon: "SiJest", Color: "#C21325"},
+				},
+			},
+			{
+				ID:         "data-engineering",
+				Title:      map[string]string{"ru": "Data Engineering (изучаю углубленно)", "en": "Data Engineering (learning)"},
+				IsLearning: true,
+				Skills: []entity.Skill{
+					{ID: "sql", Name: "SQL", Icon: "SiPostgresql", Color: "#4169E1"},
+					{ID: "python-data", Name: "Python", Icon: "SiPython", Color: "#3776AB"},
+					{ID: "spark", Name: "Apache Spark", Icon: "SiApachespark", Color: "#E25A1C"},
+					{ID: "kafka", Name: "Apache Kafka", Icon: "SiApachekafka", Color: "#231F20"},
+					{ID: "airflow", Name: "Apache Airflow", Icon: "SiApacheairflow", Color: "#017CEE"},
+					{ID: "clickhouse", Name: "ClickHouse", Icon: "SiClickhouse", Color: "#FFCC01"},
+					{ID: "etl", Name: "ETL", Icon: "SiDatabricks", Color: "#FF3621"},
+					{ID: "dwh", Name: "DWH", Icon: "SiSnowflake", Color: "#29B5E8"},
+					{ID: "data-modeling", Name: "Data Modeling", Icon: "SiDiagramsdotnet", Color: "#F08705"},
+					{ID: "data-quality", Name: "Data Quality", Icon: "SiApacheairflow", Color: "#017CEE"},
+				},
+			},
+		},
+		Contacts: []entity.Contact{
+			{ID: "telegram", Type: "social", Label: map[string]string{"ru": "Telegram", "en": "Telegram"}, Value: "@kyurenodev", Link: "https://t.me/kyurenodev", Icon: "FaTelegram", Color: "#0088cc"},
+			{ID: "discord", Type: "social", Label: map[string]string{"ru": "Discord", "en": "Discord"}, Value: "kyureno", Link: "https://discord.com/users/kyureno", Icon: "FaDiscord", Color: "#5865F2"},
+			{ID: "github", Type: "social", Label: map[string]string{"ru": "GitHub", "en": "GitHub"}, Value: "Kyureno2374", Link: "https://github.com/Kyureno2374", Icon: "FaGithub", Color: "#333333"},
+		},
+	}
+}
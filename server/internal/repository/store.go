@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"server/internal/entity"
+)
+
+// ContentStore reads and replaces the public site content
+type ContentStore interface {
+	GetAll(ctx context.Context) (*entity.SiteContent, error)
+	GetEditable(ctx context.Context) (*entity.SiteContent, error)
+	GetAbout(ctx context.Context) (entity.AboutContent, error)
+	UpdateAbout(ctx context.Context, about entity.AboutContent) error
+	GetProjects(ctx context.Context) ([]entity.Project, error)
+	UpdateProjects(ctx context.Context, projects []entity.Project) error
+	GetSkills(ctx context.Context) ([]entity.SkillCategory, error)
+	UpdateSkills(ctx context.Context, categories []entity.SkillCategory) error
+	GetContacts(ctx context.Context) ([]entity.Contact, error)
+	UpdateContacts(ctx context.Context, contacts []entity.Contact) error
+}
+
+// AnalyticsStore records visitor events and aggregates them
+type AnalyticsStore interface {
+	TrackPageView(ctx context.Context, page, visitorID, device string) error
+	TrackSession(ctx context.Context, visitorID string, duration, pages int, theme, language string) error
+	GetAnalytics(ctx context.Context) (*entity.AnalyticsData, error)
+}
+
+// RevisionStore keeps the history of content section snapshots
+type RevisionStore interface {
+	CreateRevision(ctx context.Context, rev entity.ContentRevision) (entity.ContentRevision, error)
+	ListRevisions(ctx context.Context, section string, limit int) ([]entity.ContentRevision, error)
+	GetRevision(ctx context.Context, section string, id int64) (entity.ContentRevision, error)
+}
+
+// DraftStore keeps unpublished edits of content sections
+type DraftStore interface {
+	GetDrafts(ctx context.Context) ([]entity.ContentDraft, error)
+	GetDraft(ctx context.Context, section string) (entity.ContentDraft, error)
+	// SectionVersions returns the version of every section. It moves on
+	// each change of a section's draft or published state.
+	SectionVersions(ctx context.Context) (map[string]int64, error)
+	// SaveDraft stores the draft if its section is still at version, and
+	// returns ErrStale otherwise
+	SaveDraft(ctx context.Context, draft entity.ContentDraft, version int64) error
+	DiscardDraft(ctx context.Context, section string) error
+	PublishDrafts(ctx context.Context) ([]string, error)
+}
+
+// ScheduleStore finds upcoming publish/unpublish times
+type ScheduleStore interface {
+	NextScheduleChange(ctx context.Context, t time.Time) (*time.Time, error)
+}
+
+// AuthStore keeps admin accounts and their login sessions
+type AuthStore interface {
+	CountAdminUsers(ctx context.Context) (int, error)
+	ListAdminUsers(ctx context.Context) ([]entity.AdminUser, error)
+	CreateAdminUser(ctx context.Context, user entity.AdminUser) (entity.AdminUser, error)
+	GetAdminUser(ctx context.Context, id int64) (entity.AdminUser, error)
+	GetAdminUserByName(ctx context.Context, username string) (entity.AdminUser, error)
+	// UpdateAdminUser saves the role and password hash of an account
+	UpdateAdminUser(ctx context.Context, user entity.AdminUser) error
+	DeleteAdminUser(ctx context.Context, id int64) error
+	// UpdateAdminUserTOTP saves the TOTP secret, state and recovery codes
+	UpdateAdminUserTOTP(ctx context.Context, user entity.AdminUser) error
+	// UseTOTPStep records a login with the code of a time step; an older or
+	// already used step is ErrConflict
+	UseTOTPStep(ctx context.Context, userID, step int64) error
+	// UseRecoveryCode removes a recovery code; an unknown one is ErrNotFound
+	UseRecoveryCode(ctx context.Context, userID int64, hash string) error
+	CreateAuthSession(ctx context.Context, session entity.AuthSession) error
+	GetAuthSession(ctx context.Context, id string) (entity.AuthSession, error)
+	// RotateAuthSession swaps the refresh token of an active session; it
+	// fails with ErrNotFound if oldHash is not the current one
+	RotateAuthSession(ctx context.Context, id, oldHash, newHash string, expiresAt time.Time) error
+	RevokeAuthSession(ctx context.Context, id string) error
+	RevokeAdminUserSessions(ctx context.Context, userID int64) error
+	CreateAPIKey(ctx context.Context, key entity.APIKey) (entity.APIKey, error)
+	ListAPIKeys(ctx context.Context) ([]entity.APIKey, error)
+	GetAPIKey(ctx context.Context, id string) (entity.APIKey, error)
+	// TouchAPIKey records that the key was used at t
+	TouchAPIKey(ctx context.Context, id string, t time.Time) error
+	RevokeAPIKey(ctx context.Context, id string) error
+}
+
+// AuditStore keeps the log of administrative actions
+type AuditStore interface {
+	CreateAuditEntry(ctx context.Context, e entity.AuditEntry) error
+	// ListAuditEntries returns entries matching the filter, newest first
+	ListAuditEntries(ctx context.Context, f entity.AuditFilter) ([]entity.AuditEntry, error)
+	// PruneAuditLog removes entries older than t and returns how many
+	PruneAuditLog(ctx context.Context, t time.Time) (int64, error)
+}
+
+// Store is everything the API needs from a storage backend
+type Store interface {
+	ContentStore
+	AnalyticsStore
+	RevisionStore
+	DraftStore
+	ScheduleStore
+	AuthStore
+	AuditStore
+	Close()
+}
+
+var (
+	_ Store = (*PostgresRepository)(nil)
+	_ Store = (*MemoryRepository)(nil)
+)
@@ -0,0 +1,68 @@
+// Package scheduler watches publish_at/unpublish_at times and notifies
+// listeners when a scheduled item appears on or disappears from the site.
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"server/internal/repository"
+)
+
+type Scheduler struct {
+	store    repository.ScheduleStore
+	interval time.Duration
+	onChange []func()
+}
+
+// New returns a scheduler that re-reads the schedule at least every
+// interval, so items scheduled after startup are picked up as well
+func New(store repository.ScheduleStore, interval time.Duration) *Scheduler {
+	return &Scheduler{store: store, interval: interval}
+}
+
+// OnChange registers fn to run every time a scheduled item flips state.
+// Must be called before Run.
+func (s *Scheduler) OnChange(fn func()) {
+	s.onChange = append(s.onChange, fn)
+}
+
+// Run blocks until ctx is cancelled
+func (s *Scheduler) Run(ctx context.Context) {
+	last := time.Now()
+
+	for {
+		wait := s.interval
+		next, err := s.store.NextScheduleChange(ctx, last)
+		if err != nil {
+			slog.Error("Scheduler: failed to read schedule", "err", err)
+		} else if next != nil {
+			wait = min(wait, max(time.Until(*next), 0))
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		// Re-check the whole window, an item may have been scheduled into it
+		// while we were waiting
+		now := time.Now()
+		changed, err := s.store.NextScheduleChange(ctx, last)
+		if err != nil {
+			slog.Error("Scheduler: failed to read schedule", "err", err)
+			continue
+		}
+		if changed != nil && !changed.After(now) {
+			slog.Info("Scheduler: scheduled content changed", "at", changed.Format(time.RFC3339))
+			for _, fn := range s.onChange {
+				fn()
+			}
+		}
+		last = now
+	}
+}
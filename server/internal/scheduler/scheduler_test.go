@@ -0,0 +1,77 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeStore reports a single change at a fixed time
+type fakeStore struct {
+	mu sync.Mutex
+	at *time.Time
+}
+
+func (f *fakeStore) NextScheduleChange(_ context.Context, after time.Time) (*time.Time, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.at == nil || !f.at.After(after) {
+		return nil, nil
+	}
+	v := *f.at
+	return &v, nil
+}
+
+func TestRunNotifiesOnChange(t *testing.T) {
+	at := time.Now().Add(50 * time.Millisecond)
+	s := New(&fakeStore{at: &at}, time.Hour)
+
+	changed := make(chan struct{}, 1)
+	s.OnChange(func() { changed <- struct{}{} })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-changed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("OnChange was not called")
+	}
+	cancel()
+	<-done
+}
+
+func TestRunPicksUpNewSchedules(t *testing.T) {
+	// Nothing is scheduled at startup, the item is added while the
+	// scheduler waits for the next poll
+	store := &fakeStore{}
+	s := New(store, 100*time.Millisecond)
+
+	changed := make(chan struct{}, 1)
+	s.OnChange(func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+
+	at := time.Now().Add(20 * time.Millisecond)
+	store.mu.Lock()
+	store.at = &at
+	store.mu.Unlock()
+
+	select {
+	case <-changed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("OnChange was not called for a schedule added after startup")
+	}
+}
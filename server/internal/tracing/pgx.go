@@ -0,0 +1,112 @@
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"server/internal/repository"
+)
+
+// QueryTracer returns a pgx hook that makes every query a child span of
+// the request in its context; a batch becomes a span with one child per
+// query. Queries outside of a trace, like migrations
+// and the scheduler, are not traced. Spans carry the SQL text but never
+// the arguments.
+func QueryTracer() pgx.QueryTracer {
+	return queryTracer{tracer: otel.Tracer(instrumentation)}
+}
+
+type queryTracer struct {
+	tracer trace.Tracer
+}
+
+var _ pgx.BatchTracer = queryTracer{}
+
+// querySpanKey marks a context whose span was started by TraceQueryStart,
+// so TraceQueryEnd never ends the request span of an untraced query
+type querySpanKey struct{}
+
+func (t queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+	ctx, span := t.tracer.Start(ctx, repository.QueryName(data.SQL), trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		semconv.DBSystemPostgreSQL,
+		semconv.DBQueryText(data.SQL),
+	))
+	return context.WithValue(ctx, querySpanKey{}, span)
+}
+
+// batchSpan is the span of a batch and when its previous result was read.
+// Results come back one after another, so each query span runs from the
+// previous result to its own.
+type batchSpan struct {
+	span trace.Span
+	last time.Time
+}
+
+type batchSpanKey struct{}
+
+func (t queryTracer) TraceBatchStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchStartData) context.Context {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+	ctx, span := t.tracer.Start(ctx, "batch", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		semconv.DBSystemPostgreSQL,
+		attribute.Int("db.batch.size", data.Batch.Len()),
+	))
+	return context.WithValue(ctx, batchSpanKey{}, &batchSpan{span: span, last: time.Now()})
+}
+
+func (t queryTracer) TraceBatchQuery(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchQueryData) {
+	batch, ok := ctx.Value(batchSpanKey{}).(*batchSpan)
+	if !ok {
+		return
+	}
+	now := time.Now()
+	_, span := t.tracer.Start(ctx, repository.QueryName(data.SQL), trace.WithSpanKind(trace.SpanKindClient), trace.WithTimestamp(batch.last), trace.WithAttributes(
+		semconv.DBSystemPostgreSQL,
+		semconv.DBQueryText(data.SQL),
+	))
+	endSpan(span, data.Err, data.CommandTag.RowsAffected())
+	span.End(trace.WithTimestamp(now))
+	batch.last = now
+}
+
+func (t queryTracer) TraceBatchEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchEndData) {
+	batch, ok := ctx.Value(batchSpanKey{}).(*batchSpan)
+	if !ok {
+		return
+	}
+	if data.Err != nil {
+		batch.span.RecordError(data.Err)
+		batch.span.SetStatus(codes.Error, data.Err.Error())
+	}
+	batch.span.End()
+}
+
+func (t queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span, ok := ctx.Value(querySpanKey{}).(trace.Span)
+	if !ok {
+		return
+	}
+	endSpan(span, data.Err, data.CommandTag.RowsAffected())
+	span.End()
+}
+
+// endSpan records the outcome of a query on its span
+func endSpan(span trace.Span, err error, rowsAffected int64) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return
+	}
+	span.SetAttributes(attribute.Int64("db.rows_affected", rowsAffected))
+}
@@ -0,0 +1,145 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// record installs a tracer provider that keeps every ended span
+func record(t *testing.T) (*sdktrace.TracerProvider, *tracetest.SpanRecorder) {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() { provider.Shutdown(context.Background()) })
+	return provider, recorder
+}
+
+func attr(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestMiddleware(t *testing.T) {
+	provider, recorder := record(t)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	r := chi.NewRouter()
+	r.Use(Middleware)
+	r.Get("/api/content/{section}", func(w http.ResponseWriter, r *http.Request) {})
+	r.Get("/api/fail", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/content/about", nil)
+	req.Header.Set("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/fail", nil))
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("ended %d spans, want 2", len(spans))
+	}
+	content, failed := spans[0], spans[1]
+	if content.Name() != "GET /api/content/{section}" {
+		t.Errorf("span name = %q", content.Name())
+	}
+	if got := content.SpanContext().TraceID().String(); got != "0af7651916cd43dd8448eb211c80319c" {
+		t.Errorf("trace id = %s, want the one of traceparent", got)
+	}
+	if got := content.Parent().SpanID().String(); got != "b7ad6b7169203331" {
+		t.Errorf("parent span = %s, want the one of traceparent", got)
+	}
+	if got := attr(content, "http.route").AsString(); got != "/api/content/{section}" {
+		t.Errorf("http.route = %q", got)
+	}
+	if got := attr(content, "http.response.status_code").AsInt64(); got != http.StatusOK {
+		t.Errorf("status attribute = %d", got)
+	}
+	if failed.Status().Code != codes.Error || failed.Parent().IsValid() {
+		t.Errorf("failed request span: status %v, parent %v", failed.Status(), failed.Parent())
+	}
+}
+
+func TestQueryTracer(t *testing.T) {
+	provider, recorder := record(t)
+	tracer := queryTracer{tracer: provider.Tracer("test")}
+
+	// Queries outside of a request are not traced
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+	if n := len(recorder.Ended()); n != 0 {
+		t.Fatalf("untraced query ended %d spans", n)
+	}
+
+	ctx, request := provider.Tracer("test").Start(context.Background(), "request")
+	qctx := tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "SELECT * FROM about WHERE id = $1", Args: []any{"secret"}})
+	tracer.TraceQueryEnd(qctx, nil, pgx.TraceQueryEndData{Err: errors.New("boom")})
+
+	batch := &pgx.Batch{}
+	batch.Queue("DELETE FROM skills")
+	batch.Queue("INSERT INTO skills (id) VALUES ($1)", "go")
+	bctx := tracer.TraceBatchStart(ctx, nil, pgx.TraceBatchStartData{Batch: batch})
+	tracer.TraceBatchQuery(bctx, nil, pgx.TraceBatchQueryData{SQL: "DELETE FROM skills"})
+	tracer.TraceBatchQuery(bctx, nil, pgx.TraceBatchQueryData{SQL: "INSERT INTO skills (id) VALUES ($1)"})
+	tracer.TraceBatchEnd(bctx, nil, pgx.TraceBatchEndData{})
+	request.End()
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, s := range recorder.Ended() {
+		spans[s.Name()] = s
+	}
+	parent := request.SpanContext().SpanID()
+
+	query := spans["select about"]
+	if query == nil || query.Parent().SpanID() != parent {
+		t.Fatalf("query span = %v, want a child of the request", query)
+	}
+	if query.Status().Code != codes.Error {
+		t.Errorf("failed query status = %v", query.Status())
+	}
+	for _, kv := range query.Attributes() {
+		if kv.Value.Emit() == "secret" {
+			t.Errorf("span carries a query argument in %s", kv.Key)
+		}
+	}
+
+	b := spans["batch"]
+	if b == nil || b.Parent().SpanID() != parent || attr(b, "db.batch.size").AsInt64() != 2 {
+		t.Fatalf("batch span = %v", b)
+	}
+	for _, name := range []string{"delete skills", "insert skills"} {
+		if s := spans[name]; s == nil || s.Parent().SpanID() != b.SpanContext().SpanID() {
+			t.Errorf("%s span is not a child of the batch", name)
+		}
+	}
+}
+
+func TestSetup(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Options{Exporter: None})
+	if err != nil {
+		t.Fatalf("Setup(none) = %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown = %v", err)
+	}
+	if _, err := Setup(context.Background(), Options{Exporter: "jaeger"}); err == nil {
+		t.Error("Setup(jaeger) = nil, want an error")
+	}
+}
@@ -0,0 +1,277 @@
+// Package validate checks entities against the rules declared in their
+// `validate` struct tags and reports every broken rule with a JSON pointer
+// to the offending field.
+//
+// Rules are comma separated and apply to non-empty values only, unless
+// "required" is given:
+//
+//	required   the value must not be empty
+//	id         letters, digits, '.', '_' and '-', starting with a letter or digit,
+//	           and not a word the item routes use (revisions, reorder, skills)
+//	hexcolor   #RGB, #RRGGBB or #RRGGBBAA
+//	url        absolute http(s)/mailto/tel URL, or a site path starting with /
+//	oneof=a b  one of the listed values
+//	min=n      at least n characters
+//	localized  locale tags as keys, the default locale must be filled in;
+//	           pair it with required, a missing or empty map is not checked
+//	locales    locale tags as keys
+//	after=F    a time later than the one in field F of the same struct
+//	dive       the rules after it apply to every element of a slice
+//
+// Nested structs and slices of structs are always validated.
+package validate
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"server/internal/locale"
+)
+
+var (
+	idRe    = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+	colorRe = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+)
+
+// reservedIDs are path segments that follow /content/{section} or a
+// category id in the routes, so an item with one of them as id could not
+// be addressed
+var reservedIDs = []string{"revisions", "reorder", "skills"}
+
+// FieldError is one broken rule
+type FieldError struct {
+	Pointer string `json:"pointer"`
+	Detail  string `json:"detail"`
+}
+
+// Errors lists every broken rule of a value
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	parts := make([]string, 0, len(e))
+	for _, f := range e {
+		parts = append(parts, f.Pointer+": "+f.Detail)
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+type Validator struct {
+	locales locale.Set
+}
+
+func New(locales locale.Set) *Validator {
+	return &Validator{locales: locales}
+}
+
+// Validate checks v and returns nil if it is valid. Pointers are relative
+// to v, e.g. "/2/skills/0/color" for a []SkillCategory.
+func (val *Validator) Validate(v interface{}) error {
+	var errs Errors
+	val.walk(reflect.ValueOf(v), "", nil, &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func (val *Validator) walk(v reflect.Value, pointer string, rules []string, errs *Errors) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			val.check(v, pointer, rules, errs)
+			return
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() == reflect.Slice {
+		var elemRules []string
+		for i, rule := range rules {
+			if rule == "dive" {
+				elemRules = rules[i+1:]
+				rules = rules[:i]
+				break
+			}
+		}
+		val.check(v, pointer, rules, errs)
+		for i := 0; i < v.Len(); i++ {
+			val.walk(v.Index(i), pointer+"/"+strconv.Itoa(i), elemRules, errs)
+		}
+		return
+	}
+
+	val.check(v, pointer, rules, errs)
+	if v.Kind() != reflect.Struct {
+		return
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fieldPointer := pointer
+		if !field.Anonymous {
+			fieldPointer += "/" + jsonName(field)
+		}
+		rules := splitRules(field.Tag.Get("validate"))
+		val.walk(v.Field(i), fieldPointer, rules, errs)
+		checkAfter(v, v.Field(i), fieldPointer, rules, errs)
+	}
+}
+
+// checkAfter applies the after= rules of a field of the struct parent. The
+// check passes while either time is unset.
+func checkAfter(parent, v reflect.Value, pointer string, rules []string, errs *Errors) {
+	for _, rule := range rules {
+		name, arg, _ := strings.Cut(rule, "=")
+		if name != "after" {
+			continue
+		}
+		other, ok := parent.Type().FieldByName(arg)
+		if !ok {
+			panic("validate: after rule names unknown field " + arg)
+		}
+		end, ok := timeOf(v)
+		start, startOK := timeOf(parent.FieldByIndex(other.Index))
+		if ok && startOK && !end.After(start) {
+			*errs = append(*errs, FieldError{Pointer: pointer, Detail: "must be after " + jsonName(other)})
+		}
+	}
+}
+
+func timeOf(v reflect.Value) (time.Time, bool) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return time.Time{}, false
+		}
+		v = v.Elem()
+	}
+	t, ok := v.Interface().(time.Time)
+	return t, ok && !t.IsZero()
+}
+
+// check applies the rules of one value
+func (val *Validator) check(v reflect.Value, pointer string, rules []string, errs *Errors) {
+	fail := func(format string, args ...interface{}) {
+		*errs = append(*errs, FieldError{Pointer: pointer, Detail: fmt.Sprintf(format, args...)})
+	}
+
+	empty := !v.IsValid() || v.IsZero() || (v.Kind() == reflect.Slice || v.Kind() == reflect.Map) && v.Len() == 0
+	for _, rule := range rules {
+		if rule == "required" && empty {
+			fail("is required")
+			return
+		}
+	}
+	if empty {
+		return
+	}
+
+	for _, rule := range rules {
+		name, arg, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+		case "after":
+			// compared with its sibling field by checkAfter
+		case "id":
+			if !idRe.MatchString(v.String()) {
+				fail("must contain only letters, digits, '.', '_' and '-'")
+			} else if contains(reservedIDs, v.String()) {
+				fail("must not be one of %s", strings.Join(reservedIDs, ", "))
+			}
+		case "hexcolor":
+			if !colorRe.MatchString(v.String()) {
+				fail("must be a hex color like #3178C6")
+			}
+		case "url":
+			if !isURL(v.String()) {
+				fail("must be an http(s), mailto or tel URL, or a path starting with /")
+			}
+		case "oneof":
+			allowed := strings.Fields(arg)
+			if !contains(allowed, v.String()) {
+				fail("must be one of %s", strings.Join(allowed, ", "))
+			}
+		case "min":
+			n, err := strconv.Atoi(arg)
+			if err != nil {
+				panic("validate: bad rule " + rule)
+			}
+			if len([]rune(v.String())) < n {
+				fail("must be at least %d characters", n)
+			}
+		case "localized", "locales":
+			m, ok := v.Interface().(map[string]string)
+			if !ok {
+				panic("validate: " + name + " rule on " + v.Type().String())
+			}
+			// Locales outside the config are kept, they may be
+			// translations from before a locale was taken out of it
+			for tag := range m {
+				if !locale.IsTag(tag) {
+					*errs = append(*errs, FieldError{
+						Pointer: pointer + "/" + escape(tag),
+						Detail:  fmt.Sprintf("invalid locale, expected a tag like %s", val.locales[0]),
+					})
+				}
+			}
+			if name == "localized" && strings.TrimSpace(m[val.locales[0]]) == "" {
+				*errs = append(*errs, FieldError{Pointer: pointer + "/" + val.locales[0], Detail: "is required"})
+			}
+		default:
+			panic("validate: unknown rule " + rule)
+		}
+	}
+}
+
+func splitRules(tag string) []string {
+	if tag == "" {
+		return nil
+	}
+	return strings.Split(tag, ",")
+}
+
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" {
+		name = field.Name
+	}
+	return escape(name)
+}
+
+// escape encodes a JSON pointer reference token (RFC 6901)
+func escape(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func isURL(s string) bool {
+	if strings.HasPrefix(s, "/") && !strings.HasPrefix(s, "//") {
+		return true
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return false
+	}
+	switch u.Scheme {
+	case "http", "https":
+		return u.Host != ""
+	case "mailto", "tel":
+		return u.Opaque != ""
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
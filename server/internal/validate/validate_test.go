@@ -0,0 +1,285 @@
+package validate
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"server/internal/entity"
+	"server/internal/locale"
+)
+
+type item struct {
+	ID    string            `json:"id" validate:"required,id"`
+	Color string            `json:"color" validate:"hexcolor"`
+	Link  string            `json:"link" validate:"url"`
+	Kind  string            `json:"kind" validate:"oneof=a b"`
+	Code  string            `json:"code" validate:"min=3"`
+	Title map[string]string `json:"title" validate:"localized"`
+	Note  map[string]string `json:"note" validate:"locales"`
+	Tags  []string          `json:"tags" validate:"required,dive,oneof=x y"`
+	Path  string            `json:"a/b~c"`
+	Child *child            `json:"child"`
+	Start *time.Time        `json:"start"`
+	End   *time.Time        `json:"end" validate:"after=Start"`
+}
+
+type child struct {
+	Name string `json:"name" validate:"required"`
+}
+
+func validItem() item {
+	return item{
+		ID:    "go-1.21_x",
+		Color: "#3178C6",
+		Link:  "https://example.com",
+		Kind:  "a",
+		Code:  "abc",
+		Title: map[string]string{"ru": "Заголовок", "en": "Title"},
+		Tags:  []string{"x"},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*item)
+		want   Errors
+	}{
+		{
+			name:   "valid",
+			modify: func(*item) {},
+		},
+		{
+			name: "optional fields may be empty",
+			modify: func(it *item) {
+				it.Color, it.Link, it.Kind, it.Code = "", "", "", ""
+			},
+		},
+		{
+			name:   "required",
+			modify: func(it *item) { it.ID, it.Tags = "", nil },
+			want: Errors{
+				{Pointer: "/id", Detail: "is required"},
+				{Pointer: "/tags", Detail: "is required"},
+			},
+		},
+		{
+			name:   "id",
+			modify: func(it *item) { it.ID = "-bad id" },
+			want:   Errors{{Pointer: "/id", Detail: "must contain only letters, digits, '.', '_' and '-'"}},
+		},
+		{
+			name:   "id used by the routes",
+			modify: func(it *item) { it.ID = "revisions" },
+			want:   Errors{{Pointer: "/id", Detail: "must not be one of revisions, reorder, skills"}},
+		},
+		{
+			name:   "hexcolor",
+			modify: func(it *item) { it.Color = "#12345" },
+			want:   Errors{{Pointer: "/color", Detail: "must be a hex color like #3178C6"}},
+		},
+		{
+			name:   "url",
+			modify: func(it *item) { it.Link = "javascript:alert(1)" },
+			want:   Errors{{Pointer: "/link", Detail: "must be an http(s), mailto or tel URL, or a path starting with /"}},
+		},
+		{
+			name:   "oneof",
+			modify: func(it *item) { it.Kind = "c" },
+			want:   Errors{{Pointer: "/kind", Detail: "must be one of a, b"}},
+		},
+		{
+			name:   "min counts characters",
+			modify: func(it *item) { it.Code = "яя" },
+			want:   Errors{{Pointer: "/code", Detail: "must be at least 3 characters"}},
+		},
+		{
+			name:   "dive",
+			modify: func(it *item) { it.Tags = []string{"x", "z"} },
+			want:   Errors{{Pointer: "/tags/1", Detail: "must be one of x, y"}},
+		},
+		{
+			name:   "localized needs the default locale",
+			modify: func(it *item) { it.Title = map[string]string{"en": "Title"} },
+			want:   Errors{{Pointer: "/title/ru", Detail: "is required"}},
+		},
+		{
+			name:   "locale outside the config",
+			modify: func(it *item) { it.Note = map[string]string{"de": "Notiz"} },
+		},
+		{
+			name:   "invalid locale",
+			modify: func(it *item) { it.Note = map[string]string{"de-DE": "Notiz"} },
+			want:   Errors{{Pointer: "/note/de-DE", Detail: "invalid locale, expected a tag like ru"}},
+		},
+		{
+			name:   "end after start",
+			modify: func(it *item) { it.Start, it.End = at(10), at(11) },
+		},
+		{
+			name:   "open ended",
+			modify: func(it *item) { it.End = at(1) },
+		},
+		{
+			name:   "end before start",
+			modify: func(it *item) { it.Start, it.End = at(10), at(9) },
+			want:   Errors{{Pointer: "/end", Detail: "must be after start"}},
+		},
+		{
+			name:   "end at start",
+			modify: func(it *item) { it.Start, it.End = at(10), at(10) },
+			want:   Errors{{Pointer: "/end", Detail: "must be after start"}},
+		},
+		{
+			name:   "nested struct",
+			modify: func(it *item) { it.Child = &child{} },
+			want:   Errors{{Pointer: "/child/name", Detail: "is required"}},
+		},
+	}
+
+	val := New(locale.Default)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			it := validItem()
+			tt.modify(&it)
+
+			err := val.Validate(it)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			var got Errors
+			if !errors.As(err, &got) {
+				t.Fatalf("Validate() = %v, want Errors", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func at(hour int) *time.Time {
+	t := time.Date(2026, 1, 1, hour, 0, 0, 0, time.UTC)
+	return &t
+}
+
+func TestValidateSchedule(t *testing.T) {
+	projects := []entity.Project{
+		{ID: "a", Title: map[string]string{"ru": "А"}, Schedule: entity.Schedule{PublishAt: at(12), UnpublishAt: at(8)}},
+	}
+
+	err := New(locale.Default).Validate(projects)
+	want := Errors{{Pointer: "/0/unpublishAt", Detail: "must be after publishAt"}}
+	var got Errors
+	if !errors.As(err, &got) || !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate() = %v, want %v", err, want)
+	}
+}
+
+func TestValidateMissingTranslations(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  Errors
+	}{
+		{
+			name:  "project without title",
+			value: []entity.Project{{ID: "a"}},
+			want:  Errors{{Pointer: "/0/title", Detail: "is required"}},
+		},
+		{
+			name:  "project with empty title",
+			value: []entity.Project{{ID: "a", Title: map[string]string{}}},
+			want:  Errors{{Pointer: "/0/title", Detail: "is required"}},
+		},
+		{
+			name:  "skill category without title",
+			value: []entity.SkillCategory{{ID: "a", Title: map[string]string{}}},
+			want:  Errors{{Pointer: "/0/title", Detail: "is required"}},
+		},
+		{
+			name: "contact without label",
+			value: []entity.Contact{{
+				ID: "a", Type: "email", Value: "me@example.com", Link: "mailto:me@example.com", Icon: "FaEnvelope", Color: "#000000",
+			}},
+			want: Errors{{Pointer: "/0/label", Detail: "is required"}},
+		},
+		{
+			name: "about without name, title and stat texts",
+			value: entity.AboutContent{
+				Username: "@me",
+				Title:    map[string]string{},
+				Stats:    []entity.Stat{{Icon: "FaStar", Color: "#000000"}},
+			},
+			want: Errors{
+				{Pointer: "/name", Detail: "is required"},
+				{Pointer: "/title", Detail: "is required"},
+				{Pointer: "/stats/0/value", Detail: "is required"},
+				{Pointer: "/stats/0/label", Detail: "is required"},
+			},
+		},
+	}
+
+	val := New(locale.Default)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := val.Validate(tt.value)
+			var got Errors
+			if !errors.As(err, &got) || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateSlicePointers(t *testing.T) {
+	categories := []entity.SkillCategory{
+		{ID: "frontend", Title: map[string]string{"ru": "Фронтенд"}},
+		{ID: "backend", Title: map[string]string{"ru": "Бэкенд"}, Skills: []entity.Skill{
+			{ID: "go", Name: "Go", Icon: "SiGo", Color: "#00ADD8"},
+			{ID: "pg", Name: "PostgreSQL", Icon: "SiPostgresql", Color: "blue"},
+		}},
+	}
+
+	err := New(locale.Default).Validate(categories)
+	want := Errors{{Pointer: "/1/skills/1/color", Detail: "must be a hex color like #3178C6"}}
+	var got Errors
+	if !errors.As(err, &got) || !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate() = %v, want %v", err, want)
+	}
+}
+
+func TestIsURL(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"https://example.com/a", true},
+		{"http://localhost:8080", true},
+		{"mailto:me@example.com", true},
+		{"tel:+79990000000", true},
+		{"/images/photo.png", true},
+		{"//evil.example.com", false},
+		{"https://", false},
+		{"mailto:", false},
+		{"ftp://example.com", false},
+		{"example.com", false},
+	}
+	for _, tt := range tests {
+		if got := isURL(tt.in); got != tt.want {
+			t.Errorf("isURL(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestJSONNameEscapes(t *testing.T) {
+	field, _ := reflect.TypeOf(item{}).FieldByName("Path")
+	if got := jsonName(field); got != "a~1b~0c" {
+		t.Errorf("jsonName() = %q, want %q", got, "a~1b~0c")
+	}
+}
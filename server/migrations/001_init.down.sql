@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS idx_daily_stats_date;
+DROP INDEX IF EXISTS idx_page_views_visitor;
+DROP INDEX IF EXISTS idx_page_views_page;
+DROP INDEX IF EXISTS idx_page_views_created_at;
+
+DROP TABLE IF EXISTS daily_stats;
+DROP TABLE IF EXISTS sessions;
+DROP TABLE IF EXISTS page_views;
+DROP TABLE IF EXISTS contacts;
+DROP TABLE IF EXISTS skills;
+DROP TABLE IF EXISTS skill_categories;
+DROP TABLE IF EXISTS projects;
+DROP TABLE IF EXISTS stats;
+DROP TABLE IF EXISTS about;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_content_revisions_section;
+DROP TABLE IF EXISTS content_revisions;
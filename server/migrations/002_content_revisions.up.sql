@@ -0,0 +1,11 @@
+-- Full JSON snapshot of a content section after every admin update
+CREATE TABLE IF NOT EXISTS content_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    section TEXT NOT NULL,
+    author TEXT NOT NULL DEFAULT '',
+    summary TEXT NOT NULL DEFAULT '',
+    snapshot JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_content_revisions_section ON content_revisions(section, id DESC);
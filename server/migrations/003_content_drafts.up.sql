@@ -0,0 +1,7 @@
+-- Unpublished admin edits, one row per content section
+CREATE TABLE IF NOT EXISTS content_drafts (
+    section TEXT PRIMARY KEY,
+    data JSONB NOT NULL,
+    author TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,6 @@
+ALTER TABLE contacts DROP COLUMN IF EXISTS unpublish_at;
+ALTER TABLE contacts DROP COLUMN IF EXISTS publish_at;
+ALTER TABLE skill_categories DROP COLUMN IF EXISTS unpublish_at;
+ALTER TABLE skill_categories DROP COLUMN IF EXISTS publish_at;
+ALTER TABLE projects DROP COLUMN IF EXISTS unpublish_at;
+ALTER TABLE projects DROP COLUMN IF EXISTS publish_at;
@@ -0,0 +1,7 @@
+-- Optional publish window for list items; NULL means no limit
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS publish_at TIMESTAMPTZ;
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS unpublish_at TIMESTAMPTZ;
+ALTER TABLE skill_categories ADD COLUMN IF NOT EXISTS publish_at TIMESTAMPTZ;
+ALTER TABLE skill_categories ADD COLUMN IF NOT EXISTS unpublish_at TIMESTAMPTZ;
+ALTER TABLE contacts ADD COLUMN IF NOT EXISTS publish_at TIMESTAMPTZ;
+ALTER TABLE contacts ADD COLUMN IF NOT EXISTS unpublish_at TIMESTAMPTZ;
@@ -0,0 +1,41 @@
+-- Only ru and en survive the rollback, other locales are dropped
+
+ALTER TABLE about
+    ADD COLUMN name_ru TEXT NOT NULL DEFAULT '', ADD COLUMN name_en TEXT NOT NULL DEFAULT '',
+    ADD COLUMN title_ru TEXT NOT NULL DEFAULT '', ADD COLUMN title_en TEXT NOT NULL DEFAULT '',
+    ADD COLUMN bio_ru TEXT NOT NULL DEFAULT '', ADD COLUMN bio_en TEXT NOT NULL DEFAULT '';
+UPDATE about SET
+    name_ru = COALESCE(name->>'ru', ''), name_en = COALESCE(name->>'en', ''),
+    title_ru = COALESCE(title->>'ru', ''), title_en = COALESCE(title->>'en', ''),
+    bio_ru = COALESCE(bio->>'ru', ''), bio_en = COALESCE(bio->>'en', '');
+ALTER TABLE about DROP COLUMN name, DROP COLUMN title, DROP COLUMN bio;
+
+ALTER TABLE stats
+    ADD COLUMN value_ru TEXT NOT NULL DEFAULT '', ADD COLUMN value_en TEXT NOT NULL DEFAULT '',
+    ADD COLUMN label_ru TEXT NOT NULL DEFAULT '', ADD COLUMN label_en TEXT NOT NULL DEFAULT '';
+UPDATE stats SET
+    value_ru = COALESCE(value->>'ru', ''), value_en = COALESCE(value->>'en', ''),
+    label_ru = COALESCE(label->>'ru', ''), label_en = COALESCE(label->>'en', '');
+ALTER TABLE stats DROP COLUMN value, DROP COLUMN label;
+
+ALTER TABLE projects
+    ADD COLUMN title_ru TEXT NOT NULL DEFAULT '', ADD COLUMN title_en TEXT NOT NULL DEFAULT '',
+    ADD COLUMN description_ru TEXT NOT NULL DEFAULT '', ADD COLUMN description_en TEXT NOT NULL DEFAULT '';
+UPDATE projects SET
+    title_ru = COALESCE(title->>'ru', ''), title_en = COALESCE(title->>'en', ''),
+    description_ru = COALESCE(description->>'ru', ''), description_en = COALESCE(description->>'en', '');
+ALTER TABLE projects DROP COLUMN title, DROP COLUMN description;
+
+ALTER TABLE skill_categories
+    ADD COLUMN title_ru TEXT NOT NULL DEFAULT '', ADD COLUMN title_en TEXT NOT NULL DEFAULT '',
+    ADD COLUMN badge_ru TEXT DEFAULT '', ADD COLUMN badge_en TEXT DEFAULT '';
+UPDATE skill_categories SET
+    title_ru = COALESCE(title->>'ru', ''), title_en = COALESCE(title->>'en', ''),
+    badge_ru = COALESCE(badge->>'ru', ''), badge_en = COALESCE(badge->>'en', '');
+ALTER TABLE skill_categories DROP COLUMN title, DROP COLUMN badge;
+
+ALTER TABLE contacts
+    ADD COLUMN label_ru TEXT NOT NULL DEFAULT '', ADD COLUMN label_en TEXT NOT NULL DEFAULT '';
+UPDATE contacts SET
+    label_ru = COALESCE(label->>'ru', ''), label_en = COALESCE(label->>'en', '');
+ALTER TABLE contacts DROP COLUMN label;
@@ -0,0 +1,52 @@
+-- Localized fields move from one column per language (title_ru, title_en)
+-- to a single JSONB object keyed by locale: {"ru": "...", "en": "...", "de": "..."}
+
+ALTER TABLE about
+    ADD COLUMN IF NOT EXISTS name JSONB NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS title JSONB NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS bio JSONB NOT NULL DEFAULT '{}';
+UPDATE about SET
+    name = jsonb_build_object('ru', name_ru, 'en', name_en),
+    title = jsonb_build_object('ru', title_ru, 'en', title_en),
+    bio = jsonb_build_object('ru', bio_ru, 'en', bio_en);
+ALTER TABLE about
+    DROP COLUMN name_ru, DROP COLUMN name_en,
+    DROP COLUMN title_ru, DROP COLUMN title_en,
+    DROP COLUMN bio_ru, DROP COLUMN bio_en;
+
+ALTER TABLE stats
+    ADD COLUMN IF NOT EXISTS value JSONB NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS label JSONB NOT NULL DEFAULT '{}';
+UPDATE stats SET
+    value = jsonb_build_object('ru', value_ru, 'en', value_en),
+    label = jsonb_build_object('ru', label_ru, 'en', label_en);
+ALTER TABLE stats
+    DROP COLUMN value_ru, DROP COLUMN value_en,
+    DROP COLUMN label_ru, DROP COLUMN label_en;
+
+ALTER TABLE projects
+    ADD COLUMN IF NOT EXISTS title JSONB NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS description JSONB NOT NULL DEFAULT '{}';
+UPDATE projects SET
+    title = jsonb_build_object('ru', title_ru, 'en', title_en),
+    description = jsonb_build_object('ru', description_ru, 'en', description_en);
+ALTER TABLE projects
+    DROP COLUMN title_ru, DROP COLUMN title_en,
+    DROP COLUMN description_ru, DROP COLUMN description_en;
+
+ALTER TABLE skill_categories
+    ADD COLUMN IF NOT EXISTS title JSONB NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS badge JSONB NOT NULL DEFAULT '{}';
+UPDATE skill_categories SET
+    title = jsonb_build_object('ru', title_ru, 'en', title_en),
+    badge = jsonb_build_object('ru', COALESCE(badge_ru, ''), 'en', COALESCE(badge_en, ''));
+ALTER TABLE skill_categories
+    DROP COLUMN title_ru, DROP COLUMN title_en,
+    DROP COLUMN badge_ru, DROP COLUMN badge_en;
+
+ALTER TABLE contacts
+    ADD COLUMN IF NOT EXISTS label JSONB NOT NULL DEFAULT '{}';
+UPDATE contacts SET
+    label = jsonb_build_object('ru', label_ru, 'en', label_en);
+ALTER TABLE contacts
+    DROP COLUMN label_ru, DROP COLUMN label_en;
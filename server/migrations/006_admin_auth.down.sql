@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS auth_sessions;
+DROP TABLE IF EXISTS admin_users;
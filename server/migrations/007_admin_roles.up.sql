@@ -0,0 +1,4 @@
+-- Admin roles; accounts created before roles existed keep full access
+ALTER TABLE admin_users ADD COLUMN IF NOT EXISTS role TEXT NOT NULL DEFAULT 'owner'
+    CHECK (role IN ('owner', 'editor', 'analyst'));
+ALTER TABLE admin_users ALTER COLUMN role DROP DEFAULT;
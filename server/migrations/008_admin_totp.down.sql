@@ -0,0 +1,4 @@
+ALTER TABLE admin_users DROP COLUMN IF EXISTS recovery_codes;
+ALTER TABLE admin_users DROP COLUMN IF EXISTS totp_last_step;
+ALTER TABLE admin_users DROP COLUMN IF EXISTS totp_enabled;
+ALTER TABLE admin_users DROP COLUMN IF EXISTS totp_secret;
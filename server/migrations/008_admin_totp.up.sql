@@ -0,0 +1,7 @@
+-- Optional TOTP second factor. totp_last_step is the newest time step used
+-- to log in, so a code cannot be replayed; recovery codes are SHA-256
+-- hashes and are removed once used.
+ALTER TABLE admin_users ADD COLUMN IF NOT EXISTS totp_secret TEXT NOT NULL DEFAULT '';
+ALTER TABLE admin_users ADD COLUMN IF NOT EXISTS totp_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE admin_users ADD COLUMN IF NOT EXISTS totp_last_step BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE admin_users ADD COLUMN IF NOT EXISTS recovery_codes TEXT[] NOT NULL DEFAULT '{}';
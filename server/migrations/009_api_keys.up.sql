@@ -0,0 +1,14 @@
+-- API keys for automation. Only the SHA-256 of the secret part is stored;
+-- scopes are permission names, content:write optionally narrowed to one
+-- section as content:<section>:write.
+CREATE TABLE IF NOT EXISTS api_keys (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    scopes TEXT[] NOT NULL,
+    secret_hash TEXT NOT NULL,
+    created_by BIGINT REFERENCES admin_users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ
+);
@@ -0,0 +1,11 @@
+-- A counter per content section that moves on every change of its draft or
+-- published state. Writes compare it in the same transaction, so two
+-- replicas cannot both pass the If-Match check and overwrite each other.
+CREATE TABLE IF NOT EXISTS content_versions (
+    section TEXT PRIMARY KEY,
+    version BIGINT NOT NULL DEFAULT 0
+);
+
+INSERT INTO content_versions (section)
+VALUES ('about'), ('projects'), ('skills'), ('contacts')
+ON CONFLICT (section) DO NOTHING;
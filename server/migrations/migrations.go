@@ -0,0 +1,10 @@
+// Package migrations embeds the versioned SQL migrations into the binary.
+//
+// Files are named NNN_name.up.sql / NNN_name.down.sql and are applied in
+// version order by internal/migrate.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS